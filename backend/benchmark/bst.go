@@ -0,0 +1,46 @@
+package benchmark
+
+// bstNode is a node of the unbalanced benchmark binary search tree
+type bstNode struct {
+	key         int
+	left, right *bstNode
+}
+
+// bst is a plain binary search tree that never rebalances, without step
+// tracking. Sorted input degenerates it into a linked list, so every
+// operation costs O(n). Duplicate keys are ignored.
+type bst struct {
+	root *bstNode
+}
+
+// insert adds key below the last node on its search path
+func (t *bst) insert(key int) {
+	link := &t.root
+	for *link != nil {
+		switch {
+		case key < (*link).key:
+			link = &(*link).left
+		case key > (*link).key:
+			link = &(*link).right
+		default:
+			return
+		}
+	}
+	*link = &bstNode{key: key}
+}
+
+// search reports whether key is in the tree
+func (t *bst) search(key int) bool {
+	node := t.root
+	for node != nil {
+		switch {
+		case key < node.key:
+			node = node.left
+		case key > node.key:
+			node = node.right
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package benchmark
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// bstHeight counts the nodes on the longest path below node
+func bstHeight(node *bstNode) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + max(bstHeight(node.left), bstHeight(node.right))
+}
+
+func TestBSTDegeneratesOnSortedInput(t *testing.T) {
+	sorted, shuffled := &bst{}, &bst{}
+	for i, v := range rand.New(rand.NewSource(1)).Perm(1000) {
+		sorted.insert(i)
+		shuffled.insert(v)
+	}
+	sorted.insert(500)
+
+	if h := bstHeight(sorted.root); h != 1000 {
+		t.Errorf("sorted input: height %d, want the 1000 of a list", h)
+	}
+	if h := bstHeight(shuffled.root); h >= 100 {
+		t.Errorf("shuffled input: height %d, want far below 1000", h)
+	}
+	for _, key := range []int{0, 500, 999} {
+		if !sorted.search(key) || !shuffled.search(key) {
+			t.Errorf("key %d not found", key)
+		}
+	}
+	if sorted.search(1000) || shuffled.search(-1) {
+		t.Error("found a key that was never inserted")
+	}
+}
+
+func TestBSTBenchmarkHonorsStop(t *testing.T) {
+	r := NewRunner()
+	done := make(chan bool)
+	go func() {
+		completed := false
+		// Sorted inserts into the bst are quadratic; this would take
+		// minutes unless the stop is honored. The first report, and with it
+		// the stop, comes after 1% of the data, a few million node visits
+		// even under the race detector.
+		r.RunBenchmark(BenchmarkConfig{
+			DataSize:           200000,
+			Structures:         []string{"bst"},
+			Operation:          "insert",
+			Seed:               1,
+			Sorted:             true,
+			ReportEveryPercent: 1,
+		}, func(result BenchmarkResult) {
+			completed = completed || result.Completed
+			r.Stop()
+		})
+		done <- completed
+	}()
+
+	select {
+	case completed := <-done:
+		if completed {
+			t.Error("the stopped bst reported a completed result")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("bst benchmark did not stop")
+	}
+}
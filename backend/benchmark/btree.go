@@ -0,0 +1,97 @@
+package benchmark
+
+import "sort"
+
+// DefaultBTreeOrder is the minimum degree of the benchmark B-tree when a run
+// does not choose one
+const DefaultBTreeOrder = 32
+
+// MinBTreeOrder is the smallest minimum degree a B-tree can have
+const MinBTreeOrder = 2
+
+// bTreeNode holds between order-1 and 2*order-1 sorted keys, except for the
+// root which may hold fewer
+type bTreeNode struct {
+	keys     []int
+	children []*bTreeNode
+}
+
+func (n *bTreeNode) leaf() bool {
+	return len(n.children) == 0
+}
+
+// bTree is a B-tree of minimum degree order, without step tracking.
+// Duplicate keys are ignored.
+type bTree struct {
+	order int
+	root  *bTreeNode
+}
+
+func newBTree(order int) *bTree {
+	return &bTree{order: order, root: &bTreeNode{}}
+}
+
+// search reports whether key is in the tree
+func (t *bTree) search(key int) bool {
+	node := t.root
+	for {
+		i := sort.SearchInts(node.keys, key)
+		if i < len(node.keys) && node.keys[i] == key {
+			return true
+		}
+		if node.leaf() {
+			return false
+		}
+		node = node.children[i]
+	}
+}
+
+// insert adds key, splitting every full node on the way down so that the
+// leaf it lands in always has room
+func (t *bTree) insert(key int) {
+	if t.search(key) {
+		return
+	}
+	if len(t.root.keys) == 2*t.order-1 {
+		old := t.root
+		t.root = &bTreeNode{children: []*bTreeNode{old}}
+		t.splitChild(t.root, 0)
+	}
+
+	node := t.root
+	for !node.leaf() {
+		i := sort.SearchInts(node.keys, key)
+		if len(node.children[i].keys) == 2*t.order-1 {
+			t.splitChild(node, i)
+			if key > node.keys[i] {
+				i++
+			}
+		}
+		node = node.children[i]
+	}
+	i := sort.SearchInts(node.keys, key)
+	node.keys = append(node.keys, 0)
+	copy(node.keys[i+1:], node.keys[i:])
+	node.keys[i] = key
+}
+
+// splitChild splits the full child i of parent around its middle key, which
+// moves up into parent
+func (t *bTree) splitChild(parent *bTreeNode, i int) {
+	child := parent.children[i]
+	mid := t.order - 1
+	right := &bTreeNode{keys: append([]int(nil), child.keys[mid+1:]...)}
+	if !child.leaf() {
+		right.children = append([]*bTreeNode(nil), child.children[mid+1:]...)
+		child.children = child.children[:mid+1]
+	}
+	middle := child.keys[mid]
+	child.keys = child.keys[:mid]
+
+	parent.keys = append(parent.keys, 0)
+	copy(parent.keys[i+1:], parent.keys[i:])
+	parent.keys[i] = middle
+	parent.children = append(parent.children, nil)
+	copy(parent.children[i+2:], parent.children[i+1:])
+	parent.children[i+1] = right
+}
@@ -0,0 +1,34 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// CSVHeader is the header row written by WriteCSV
+var CSVHeader = []string{"structure", "operation", "dataSize", "duration", "opsPerSec", "memoryUsed"}
+
+// WriteCSV writes results as CSV, one row per result after the header.
+// Durations are in milliseconds and memory in bytes, as in BenchmarkResult.
+func WriteCSV(w io.Writer, results []BenchmarkResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		row := []string{
+			r.Structure,
+			r.Operation,
+			strconv.Itoa(r.DataSize),
+			strconv.FormatFloat(r.Duration, 'f', -1, 64),
+			strconv.FormatFloat(r.OpsPerSec, 'f', -1, 64),
+			strconv.FormatUint(r.MemoryUsed, 10),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -3,6 +3,7 @@ package benchmark
 import (
 	"math/rand"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 )
@@ -16,7 +17,38 @@ type BenchmarkResult struct {
 	MemoryUsed uint64  `json:"memoryUsed"` // in bytes
 	OpsPerSec  float64 `json:"opsPerSec"`
 	Progress   int     `json:"progress"` // 0-100
-	Completed  bool    `json:"completed"`
+	// OverallProgress is the mean progress of all structures in the run,
+	// reaching 100 only once every structure has completed
+	OverallProgress int  `json:"overallProgress"`
+	Completed       bool `json:"completed"`
+	Stopped         bool `json:"stopped,omitempty"` // set on the terminal result of a run cut short by its time budget
+	// BTreeOrder is the minimum degree the btree structure ran with
+	BTreeOrder int `json:"btreeOrder,omitempty"`
+}
+
+// Structures lists the structure names a benchmark can run
+var Structures = []string{"hashmap", "btree", "bst", "rbtree", "avltree"}
+
+// Operations lists the operations a benchmark can measure
+var Operations = []string{"insert", "search"}
+
+// IsStructure reports whether name is a known benchmark structure
+func IsStructure(name string) bool {
+	return contains(Structures, name)
+}
+
+// IsOperation reports whether name is a known benchmark operation
+func IsOperation(name string) bool {
+	return contains(Operations, name)
+}
+
+func contains(list []string, name string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
+		}
+	}
+	return false
 }
 
 // BenchmarkConfig represents configuration for a benchmark run
@@ -24,8 +56,35 @@ type BenchmarkConfig struct {
 	DataSize   int      `json:"dataSize"`
 	Structures []string `json:"structures"`
 	Operation  string   `json:"operation"`
+	// Seed makes the generated data and search keys reproducible
+	Seed int64 `json:"seed"`
+	// Sorted inserts the generated data in ascending order, the worst case
+	// of the unbalanced bst structure
+	Sorted bool `json:"sorted"`
+	// BTreeOrder is the minimum degree of the btree structure. Zero selects
+	// DefaultBTreeOrder.
+	BTreeOrder int `json:"btreeOrder"`
+	// Sequential runs the structures one at a time, collecting garbage
+	// before each, instead of concurrently. Runs take longer but no
+	// structure's timing or memory figures include another's work.
+	Sequential bool `json:"sequential"`
+	// ReportEveryPercent is how far, in percent of the data, a structure
+	// advances between progress reports. Zero selects DefaultReportPercent.
+	ReportEveryPercent int `json:"reportEveryPercent"`
+	// Timeout is the overall time budget; zero means no limit
+	Timeout time.Duration `json:"-"`
 }
 
+// DefaultReportPercent is the progress step between reports when a run does
+// not choose one
+const DefaultReportPercent = 5
+
+// MinReportPercent and MaxReportPercent bound ReportEveryPercent
+const (
+	MinReportPercent = 1
+	MaxReportPercent = 50
+)
+
 // ProgressCallback is called with benchmark progress updates
 type ProgressCallback func(result BenchmarkResult)
 
@@ -44,10 +103,10 @@ func NewRunner() *Runner {
 }
 
 // generateRandomData generates random integers for benchmarking
-func generateRandomData(size int) []int {
+func generateRandomData(rng *rand.Rand, size int) []int {
 	data := make([]int, size)
 	for i := 0; i < size; i++ {
-		data[i] = rand.Intn(size * 10)
+		data[i] = rng.Intn(size * 10)
 	}
 	return data
 }
@@ -67,47 +126,144 @@ func (r *Runner) RunBenchmark(config BenchmarkConfig, callback ProgressCallback)
 		return
 	}
 	r.running = true
-	r.stopChan = make(chan struct{})
 	r.mu.Unlock()
 
+	// A fresh channel is made once the run ends rather than when it
+	// starts, so a Stop that lands before the run gets going still ends it
 	defer func() {
 		r.mu.Lock()
 		r.running = false
+		r.stopChan = make(chan struct{})
 		r.mu.Unlock()
 	}()
 
-	data := generateRandomData(config.DataSize)
+	timedOut := false
+	if config.Timeout > 0 {
+		timer := time.AfterFunc(config.Timeout, func() {
+			r.mu.Lock()
+			timedOut = r.running
+			r.mu.Unlock()
+			r.Stop()
+		})
+		defer timer.Stop()
+	}
+
+	data := generateRandomData(rand.New(rand.NewSource(config.Seed)), config.DataSize)
+	if config.Sorted {
+		sort.Ints(data)
+	}
+	order := config.BTreeOrder
+	if order == 0 {
+		order = DefaultBTreeOrder
+	}
+
+	percent := config.ReportEveryPercent
+	if percent == 0 {
+		percent = DefaultReportPercent
+	}
+	interval := len(data) * percent / 100
+	if interval < 1 {
+		interval = 1
+	}
+
+	callback = trackOverallProgress(config.Structures, callback)
+
+	if config.Sequential {
+		r.runSequential(config, data, order, interval, callback)
+	} else {
+		r.runConcurrent(config, data, order, interval, callback)
+	}
+
+	r.mu.Lock()
+	stopped := timedOut
+	r.mu.Unlock()
+	if stopped {
+		callback(BenchmarkResult{
+			Operation: config.Operation,
+			DataSize:  config.DataSize,
+			Stopped:   true,
+		})
+	}
+}
 
+// runConcurrent benchmarks every structure in its own goroutine
+func (r *Runner) runConcurrent(config BenchmarkConfig, data []int, order, reportInterval int, callback ProgressCallback) {
 	var wg sync.WaitGroup
-	for _, structure := range config.Structures {
+	for i, structure := range config.Structures {
+		// Each goroutine gets its own source since rand.Rand is not safe
+		// for concurrent use
+		rng := rand.New(rand.NewSource(config.Seed + int64(i) + 1))
 		wg.Add(1)
 		go func(structName string) {
 			defer wg.Done()
-			r.runSingleBenchmark(structName, config.Operation, data, callback)
+			r.runSingleBenchmark(structName, config.Operation, data, order, reportInterval, rng, callback)
 		}(structure)
 	}
 	wg.Wait()
 }
 
-func (r *Runner) runSingleBenchmark(structure, operation string, data []int, callback ProgressCallback) {
-	startMem := getMemoryUsage()
-	startTime := time.Now()
+// runSequential benchmarks the structures one after another, collecting
+// garbage first so the memory delta of each belongs to it alone
+func (r *Runner) runSequential(config BenchmarkConfig, data []int, order, reportInterval int, callback ProgressCallback) {
+	for i, structure := range config.Structures {
+		select {
+		case <-r.stopChan:
+			return
+		default:
+		}
+		rng := rand.New(rand.NewSource(config.Seed + int64(i) + 1))
+		runtime.GC()
+		r.runSingleBenchmark(structure, config.Operation, data, order, reportInterval, rng, callback)
+	}
+}
 
-	total := len(data)
-	reportInterval := total / 20 // Report every 5%
-	if reportInterval < 1 {
-		reportInterval = 1
+// trackOverallProgress wraps callback so every result carries the overall
+// progress of the run across structures
+func trackOverallProgress(structures []string, callback ProgressCallback) ProgressCallback {
+	var mu sync.Mutex
+	progress := make(map[string]int, len(structures))
+	for _, structure := range structures {
+		progress[structure] = 0
 	}
 
+	return func(result BenchmarkResult) {
+		mu.Lock()
+		if _, ok := progress[result.Structure]; ok {
+			if result.Completed {
+				progress[result.Structure] = 100
+			} else {
+				progress[result.Structure] = result.Progress
+			}
+		}
+		sum := 0
+		for _, p := range progress {
+			sum += p
+		}
+		if len(progress) > 0 {
+			result.OverallProgress = sum / len(progress)
+		}
+		mu.Unlock()
+		callback(result)
+	}
+}
+
+// runSingleBenchmark measures one structure, reporting progress every
+// reportInterval elements
+func (r *Runner) runSingleBenchmark(structure, operation string, data []int, order, reportInterval int, rng *rand.Rand, callback ProgressCallback) {
+	startMem := getMemoryUsage()
+	startTime := time.Now()
+
 	switch structure {
 	case "hashmap":
-		r.benchmarkHashMap(operation, data, callback, reportInterval)
+		r.benchmarkHashMap(operation, data, rng, callback, reportInterval)
 	case "btree":
-		r.benchmarkBTree(operation, data, callback, reportInterval)
+		r.benchmarkBTree(operation, data, order, rng, callback, reportInterval)
+	case "bst":
+		r.benchmarkBST(operation, data, rng, callback, reportInterval)
 	case "rbtree":
-		r.benchmarkRBTree(operation, data, callback, reportInterval)
+		r.benchmarkRBTree(operation, data, rng, callback, reportInterval)
 	case "avltree":
-		r.benchmarkAVLTree(operation, data, callback, reportInterval)
+		r.benchmarkAVLTree(operation, data, rng, callback, reportInterval)
 	}
 
 	endMem := getMemoryUsage()
@@ -130,7 +286,7 @@ func (r *Runner) runSingleBenchmark(structure, operation string, data []int, cal
 	}
 
 	// Final result
-	callback(BenchmarkResult{
+	result := BenchmarkResult{
 		Structure:  structure,
 		Operation:  operation,
 		DataSize:   len(data),
@@ -139,10 +295,14 @@ func (r *Runner) runSingleBenchmark(structure, operation string, data []int, cal
 		OpsPerSec:  opsPerSec,
 		Progress:   100,
 		Completed:  true,
-	})
+	}
+	if structure == "btree" {
+		result.BTreeOrder = order
+	}
+	callback(result)
 }
 
-func (r *Runner) benchmarkHashMap(operation string, data []int, callback ProgressCallback, reportInterval int) BenchmarkResult {
+func (r *Runner) benchmarkHashMap(operation string, data []int, rng *rand.Rand, callback ProgressCallback, reportInterval int) BenchmarkResult {
 	m := make(map[int]int)
 	startTime := time.Now()
 
@@ -158,7 +318,7 @@ func (r *Runner) benchmarkHashMap(operation string, data []int, callback Progres
 			m[v] = v
 		case "search":
 			if i > 0 {
-				_ = m[data[rand.Intn(i)]]
+				_ = m[data[rng.Intn(i)]]
 			}
 		}
 
@@ -179,9 +339,8 @@ func (r *Runner) benchmarkHashMap(operation string, data []int, callback Progres
 	return BenchmarkResult{}
 }
 
-func (r *Runner) benchmarkBTree(operation string, data []int, callback ProgressCallback, reportInterval int) {
-	// Simple B-Tree simulation using sorted slice
-	tree := make([]int, 0, len(data))
+func (r *Runner) benchmarkBTree(operation string, data []int, order int, rng *rand.Rand, callback ProgressCallback, reportInterval int) {
+	tree := newBTree(order)
 	startTime := time.Now()
 
 	for i, v := range data {
@@ -193,14 +352,10 @@ func (r *Runner) benchmarkBTree(operation string, data []int, callback ProgressC
 
 		switch operation {
 		case "insert":
-			// Binary search insert to keep sorted
-			idx := binarySearchInsertPos(tree, v)
-			tree = append(tree, 0)
-			copy(tree[idx+1:], tree[idx:])
-			tree[idx] = v
+			tree.insert(v)
 		case "search":
-			if len(tree) > 0 {
-				_ = binarySearch(tree, data[rand.Intn(len(tree))])
+			if i > 0 {
+				_ = tree.search(data[rng.Intn(i)])
 			}
 		}
 
@@ -214,14 +369,14 @@ func (r *Runner) benchmarkBTree(operation string, data []int, callback ProgressC
 				MemoryUsed: getMemoryUsage(),
 				Progress:   progress,
 				Completed:  false,
+				BTreeOrder: order,
 			})
 		}
 	}
 }
 
-func (r *Runner) benchmarkRBTree(operation string, data []int, callback ProgressCallback, reportInterval int) {
-	// Simplified benchmark without step tracking
-	m := make(map[int]struct{})
+func (r *Runner) benchmarkBST(operation string, data []int, rng *rand.Rand, callback ProgressCallback, reportInterval int) {
+	tree := &bst{}
 	startTime := time.Now()
 
 	for i, v := range data {
@@ -233,17 +388,17 @@ func (r *Runner) benchmarkRBTree(operation string, data []int, callback Progress
 
 		switch operation {
 		case "insert":
-			m[v] = struct{}{}
+			tree.insert(v)
 		case "search":
 			if i > 0 {
-				_, _ = m[data[rand.Intn(i)]]
+				_ = tree.search(data[rng.Intn(i)])
 			}
 		}
 
 		if i > 0 && i%reportInterval == 0 {
 			progress := (i * 100) / len(data)
 			callback(BenchmarkResult{
-				Structure:  "rbtree",
+				Structure:  "bst",
 				Operation:  operation,
 				DataSize:   len(data),
 				Duration:   time.Since(startTime).Seconds() * 1000,
@@ -255,7 +410,8 @@ func (r *Runner) benchmarkRBTree(operation string, data []int, callback Progress
 	}
 }
 
-func (r *Runner) benchmarkAVLTree(operation string, data []int, callback ProgressCallback, reportInterval int) {
+func (r *Runner) benchmarkRBTree(operation string, data []int, rng *rand.Rand, callback ProgressCallback, reportInterval int) {
+	// Simplified benchmark without step tracking
 	m := make(map[int]struct{})
 	startTime := time.Now()
 
@@ -271,14 +427,14 @@ func (r *Runner) benchmarkAVLTree(operation string, data []int, callback Progres
 			m[v] = struct{}{}
 		case "search":
 			if i > 0 {
-				_, _ = m[data[rand.Intn(i)]]
+				_, _ = m[data[rng.Intn(i)]]
 			}
 		}
 
 		if i > 0 && i%reportInterval == 0 {
 			progress := (i * 100) / len(data)
 			callback(BenchmarkResult{
-				Structure:  "avltree",
+				Structure:  "rbtree",
 				Operation:  operation,
 				DataSize:   len(data),
 				Duration:   time.Since(startTime).Seconds() * 1000,
@@ -290,45 +446,58 @@ func (r *Runner) benchmarkAVLTree(operation string, data []int, callback Progres
 	}
 }
 
-// Stop stops any running benchmark
-func (r *Runner) Stop() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if r.running {
+func (r *Runner) benchmarkAVLTree(operation string, data []int, rng *rand.Rand, callback ProgressCallback, reportInterval int) {
+	m := make(map[int]struct{})
+	startTime := time.Now()
+
+	for i, v := range data {
 		select {
 		case <-r.stopChan:
-			// already closed
+			return
 		default:
-			close(r.stopChan)
 		}
-		r.running = false
-	}
-}
 
-func binarySearchInsertPos(arr []int, target int) int {
-	left, right := 0, len(arr)
-	for left < right {
-		mid := (left + right) / 2
-		if arr[mid] < target {
-			left = mid + 1
-		} else {
-			right = mid
+		switch operation {
+		case "insert":
+			m[v] = struct{}{}
+		case "search":
+			if i > 0 {
+				_, _ = m[data[rng.Intn(i)]]
+			}
+		}
+
+		if i > 0 && i%reportInterval == 0 {
+			progress := (i * 100) / len(data)
+			callback(BenchmarkResult{
+				Structure:  "avltree",
+				Operation:  operation,
+				DataSize:   len(data),
+				Duration:   time.Since(startTime).Seconds() * 1000,
+				MemoryUsed: getMemoryUsage(),
+				Progress:   progress,
+				Completed:  false,
+			})
 		}
 	}
-	return left
 }
 
-func binarySearch(arr []int, target int) int {
-	left, right := 0, len(arr)-1
-	for left <= right {
-		mid := (left + right) / 2
-		if arr[mid] == target {
-			return mid
-		} else if arr[mid] < target {
-			left = mid + 1
-		} else {
-			right = mid - 1
-		}
+// Running reports whether a benchmark is currently in progress
+func (r *Runner) Running() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+// Stop stops the running benchmark. Called before RunBenchmark, it makes
+// the next run return without measuring anything.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	select {
+	case <-r.stopChan:
+		// already closed
+	default:
+		close(r.stopChan)
 	}
-	return -1
+	r.running = false
 }
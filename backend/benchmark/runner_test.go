@@ -0,0 +1,114 @@
+package benchmark
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSameSeedGeneratesSameData(t *testing.T) {
+	first := generateRandomData(rand.New(rand.NewSource(42)), 1000)
+	second := generateRandomData(rand.New(rand.NewSource(42)), 1000)
+	if !slices.Equal(first, second) {
+		t.Error("two runs with seed 42 generated different data")
+	}
+	if other := generateRandomData(rand.New(rand.NewSource(43)), 1000); slices.Equal(first, other) {
+		t.Error("seeds 42 and 43 generated the same data")
+	}
+}
+
+func TestOverallProgressCompletesWithTheLastStructure(t *testing.T) {
+	var overall []int
+	track := trackOverallProgress([]string{"bst", "avltree"}, func(result BenchmarkResult) {
+		overall = append(overall, result.OverallProgress)
+	})
+	track(BenchmarkResult{Structure: "bst", Progress: 50})
+	track(BenchmarkResult{Structure: "bst", Progress: 100, Completed: true})
+	track(BenchmarkResult{Structure: "avltree", Progress: 50})
+	track(BenchmarkResult{Stopped: true})
+	track(BenchmarkResult{Structure: "avltree", Progress: 100, Completed: true})
+	if want := []int{25, 50, 75, 75, 100}; !slices.Equal(overall, want) {
+		t.Errorf("overall progress %v, want %v", overall, want)
+	}
+
+	structures := []string{"hashmap", "btree", "bst"}
+	last, completed := 0, 0
+	NewRunner().RunBenchmark(BenchmarkConfig{
+		DataSize:   5000,
+		Structures: structures,
+		Operation:  "insert",
+		Seed:       1,
+		Sequential: true,
+	}, func(result BenchmarkResult) {
+		if result.OverallProgress < last {
+			t.Errorf("overall progress fell from %d to %d", last, result.OverallProgress)
+		}
+		last = result.OverallProgress
+		if result.Completed {
+			completed++
+		}
+		if (last == 100) != (completed == len(structures)) {
+			t.Errorf("overall progress %d after %d of %d structures completed", last, completed, len(structures))
+		}
+	})
+	if last != 100 {
+		t.Errorf("run finished at %d%%", last)
+	}
+}
+
+func TestSequentialTimingsDoNotOverlap(t *testing.T) {
+	structures := []string{"hashmap", "btree", "bst", "rbtree", "avltree"}
+	var order []string
+	var starts, ends []time.Time
+	NewRunner().RunBenchmark(BenchmarkConfig{
+		DataSize:   20000,
+		Structures: structures,
+		Operation:  "insert",
+		Seed:       1,
+		Sequential: true,
+	}, func(result BenchmarkResult) {
+		if len(order) == 0 || order[len(order)-1] != result.Structure {
+			order = append(order, result.Structure)
+		}
+		if result.Completed {
+			end := time.Now()
+			ends = append(ends, end)
+			// The duration was measured before the result was reported, so
+			// this is no earlier than the structure really started
+			starts = append(starts, end.Add(-time.Duration(result.Duration*float64(time.Millisecond))))
+		}
+	})
+
+	if !slices.Equal(order, structures) {
+		t.Fatalf("results streamed in order %v, want each structure in turn %v", order, structures)
+	}
+	for i := 1; i < len(ends); i++ {
+		if starts[i].Before(ends[i-1]) {
+			t.Errorf("%s started %v before %s finished", structures[i], ends[i-1].Sub(starts[i]), structures[i-1])
+		}
+	}
+}
+
+func TestStopBeforeRunCancelsIt(t *testing.T) {
+	r := NewRunner()
+	r.Stop()
+	config := BenchmarkConfig{
+		DataSize:   1000,
+		Structures: []string{"hashmap"},
+		Operation:  "insert",
+		Seed:       1,
+	}
+	reported := 0
+	r.RunBenchmark(config, func(BenchmarkResult) { reported++ })
+	if reported != 0 {
+		t.Errorf("a run stopped before it started reported %d results", reported)
+	}
+
+	// The stop is used up by the canceled run
+	completed := false
+	r.RunBenchmark(config, func(result BenchmarkResult) { completed = completed || result.Completed })
+	if !completed {
+		t.Error("the next run did not complete")
+	}
+}
@@ -0,0 +1,57 @@
+package benchmark
+
+import "sort"
+
+// RankedResult is one row of a benchmark comparison table
+type RankedResult struct {
+	Rank      int     `json:"rank"`
+	Structure string  `json:"structure"`
+	OpsPerSec float64 `json:"opsPerSec"`
+	Duration  float64 `json:"duration"` // in milliseconds
+	// Speedup is the throughput relative to the slowest structure of the run
+	Speedup float64 `json:"speedup"`
+}
+
+// Summary ranks the final results of a benchmark run
+type Summary struct {
+	Operation string         `json:"operation"`
+	DataSize  int            `json:"dataSize"`
+	Ranking   []RankedResult `json:"ranking"`
+}
+
+// Summarize ranks completed results by ops/sec, fastest first
+func Summarize(results []BenchmarkResult) Summary {
+	summary := Summary{Ranking: make([]RankedResult, 0, len(results))}
+
+	completed := make([]BenchmarkResult, 0, len(results))
+	for _, r := range results {
+		if r.Completed {
+			completed = append(completed, r)
+		}
+	}
+	if len(completed) == 0 {
+		return summary
+	}
+	summary.Operation = completed[0].Operation
+	summary.DataSize = completed[0].DataSize
+
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].OpsPerSec > completed[j].OpsPerSec
+	})
+	slowest := completed[len(completed)-1].OpsPerSec
+
+	for i, r := range completed {
+		speedup := 0.0
+		if slowest > 0 {
+			speedup = r.OpsPerSec / slowest
+		}
+		summary.Ranking = append(summary.Ranking, RankedResult{
+			Rank:      i + 1,
+			Structure: r.Structure,
+			OpsPerSec: r.OpsPerSec,
+			Duration:  r.Duration,
+			Speedup:   speedup,
+		})
+	}
+	return summary
+}
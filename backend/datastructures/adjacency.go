@@ -0,0 +1,151 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// AdjacencyNeighbor is an outgoing edge in the adjacency list document
+type AdjacencyNeighbor struct {
+	To     string  `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+// AdjacencyNode is a node and its edges in the adjacency list document
+type AdjacencyNode struct {
+	ID        string              `json:"id"`
+	X         *float64            `json:"x,omitempty"`
+	Y         *float64            `json:"y,omitempty"`
+	Weight    int                 `json:"weight,omitempty"`
+	Neighbors []AdjacencyNeighbor `json:"neighbors"`
+}
+
+// AdjacencyDocument is the adjacency list JSON form of a graph. In an
+// undirected graph every edge is listed once, under its smaller endpoint;
+// in a directed graph every edge is listed under its source.
+type AdjacencyDocument struct {
+	Directed           bool            `json:"directed"`
+	AllowSelfLoops     bool            `json:"allowSelfLoops,omitempty"`
+	AllowParallelEdges bool            `json:"allowParallelEdges,omitempty"`
+	Nodes              []AdjacencyNode `json:"nodes"`
+}
+
+// ExportAdjacency serializes the graph as an adjacency list JSON document
+func (g *Graph) ExportAdjacency() ([]byte, error) {
+	export := g.Export()
+
+	doc := AdjacencyDocument{
+		Directed:           export.Directed,
+		AllowSelfLoops:     export.AllowSelfLoops,
+		AllowParallelEdges: export.AllowParallelEdges,
+		Nodes:              make([]AdjacencyNode, 0, len(export.Nodes)),
+	}
+	index := make(map[string]int, len(export.Nodes))
+	for i, n := range export.Nodes {
+		index[n.ID] = i
+		doc.Nodes = append(doc.Nodes, AdjacencyNode{
+			ID:        n.ID,
+			X:         n.X,
+			Y:         n.Y,
+			Weight:    n.Weight,
+			Neighbors: make([]AdjacencyNeighbor, 0),
+		})
+	}
+	for _, e := range export.Edges {
+		node := &doc.Nodes[index[e.From]]
+		node.Neighbors = append(node.Neighbors, AdjacencyNeighbor{To: e.To, Weight: e.Weight})
+	}
+
+	return json.Marshal(doc)
+}
+
+// ImportAdjacency replaces the graph with the one described by an adjacency
+// list JSON document. The graph is left untouched if the document is invalid.
+func (g *Graph) ImportAdjacency(data []byte) error {
+	var doc AdjacencyDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	export := GraphExport{
+		Nodes:              make([]GraphNodeInput, 0, len(doc.Nodes)),
+		Edges:              make([]GraphEdgeInput, 0),
+		AllowSelfLoops:     doc.AllowSelfLoops,
+		AllowParallelEdges: doc.AllowParallelEdges,
+		Directed:           doc.Directed,
+	}
+	seen := make(map[string]bool, len(doc.Nodes))
+	for _, n := range doc.Nodes {
+		if n.ID == "" {
+			return fmt.Errorf("node id must not be empty")
+		}
+		if seen[n.ID] {
+			return fmt.Errorf("duplicate node id %s", n.ID)
+		}
+		seen[n.ID] = true
+		export.Nodes = append(export.Nodes, GraphNodeInput{ID: n.ID, X: n.X, Y: n.Y, Weight: n.Weight})
+		for _, nb := range n.Neighbors {
+			export.Edges = append(export.Edges, GraphEdgeInput{From: n.ID, To: nb.To, Weight: nb.Weight})
+		}
+	}
+
+	imported, err := ImportGraph(export)
+	if err != nil {
+		return err
+	}
+	g.Nodes = imported.Nodes
+	g.NodeCoords = imported.NodeCoords
+	g.NodeWeight = imported.NodeWeight
+	g.AllowSelfLoops = imported.AllowSelfLoops
+	g.AllowParallelEdges = imported.AllowParallelEdges
+	g.Directed = imported.Directed
+	return nil
+}
+
+// AdjacencyMatrix is the weight matrix form of a graph. Weights[i][j] is
+// the weight of the edge from Nodes[i] to Nodes[j], or nil (null in JSON)
+// when there is none; any float, negative ones included, is a real weight.
+type AdjacencyMatrix struct {
+	Nodes   []string     `json:"nodes"`
+	Weights [][]*float64 `json:"weights"`
+}
+
+// ToAdjacencyMatrix returns the weight matrix of the graph and its node
+// ordering, sorted by ID. Undirected graphs give a symmetric matrix; of
+// parallel edges the lightest one is kept.
+func (g *Graph) ToAdjacencyMatrix() ([][]*float64, []string) {
+	nodes := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		nodes = append(nodes, id)
+	}
+	sort.Strings(nodes)
+
+	index := make(map[string]int, len(nodes))
+	for i, id := range nodes {
+		index[id] = i
+	}
+
+	weights := make([][]*float64, len(nodes))
+	for i := range weights {
+		weights[i] = make([]*float64, len(nodes))
+	}
+	// Undirected edges are stored on both endpoints, so walking every
+	// node's edges fills both halves of the matrix
+	for from, edges := range g.Nodes {
+		row := weights[index[from]]
+		for _, e := range edges {
+			j := index[e.To]
+			if row[j] == nil || e.Weight < *row[j] {
+				w := e.Weight
+				row[j] = &w
+			}
+		}
+	}
+	return weights, nodes
+}
+
+// AdjacencyMatrix returns the graph's weight matrix for operation results
+func (g *Graph) AdjacencyMatrix() *AdjacencyMatrix {
+	weights, nodes := g.ToAdjacencyMatrix()
+	return &AdjacencyMatrix{Nodes: nodes, Weights: weights}
+}
@@ -0,0 +1,105 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestAdjacencyRoundTrip(t *testing.T) {
+	directed := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 2},
+		GraphEdgeInput{From: "B", To: "A", Weight: 3},
+		GraphEdgeInput{From: "B", To: "C", Weight: 1.5},
+	)
+	for name, g := range map[string]*Graph{"undirected": CreateSampleGraph(), "directed": directed} {
+		t.Run(name, func(t *testing.T) {
+			data, err := g.ExportAdjacency()
+			if err != nil {
+				t.Fatal(err)
+			}
+			imported := NewGraph()
+			if err := imported.ImportAdjacency(data); err != nil {
+				t.Fatal(err)
+			}
+
+			if imported.Directed != g.Directed {
+				t.Errorf("directed %v, want %v", imported.Directed, g.Directed)
+			}
+			wantWeights, wantNodes := g.ToAdjacencyMatrix()
+			weights, nodes := imported.ToAdjacencyMatrix()
+			if !reflect.DeepEqual(nodes, wantNodes) || !reflect.DeepEqual(weights, wantWeights) {
+				t.Errorf("adjacency %v %v, want %v %v", nodes, weights, wantNodes, wantWeights)
+			}
+			if imported.edgeCount() != g.edgeCount() {
+				t.Errorf("%d edges, want %d", imported.edgeCount(), g.edgeCount())
+			}
+			if !maps.Equal(imported.NodeCoords, g.NodeCoords) {
+				t.Errorf("coordinates %v, want %v", imported.NodeCoords, g.NodeCoords)
+			}
+		})
+	}
+}
+
+func TestImportAdjacencyRejectsInvalidDocuments(t *testing.T) {
+	docs := map[string]string{
+		"malformed":    `{"nodes": [`,
+		"empty id":     `{"nodes": [{"id": "", "neighbors": []}]}`,
+		"duplicate id": `{"nodes": [{"id": "A", "neighbors": []}, {"id": "A", "neighbors": []}]}`,
+		"unknown node": `{"nodes": [{"id": "A", "neighbors": [{"to": "Z", "weight": 1}]}]}`,
+	}
+	for name, doc := range docs {
+		g := CreateSampleGraph()
+		if err := g.ImportAdjacency([]byte(doc)); err == nil {
+			t.Errorf("%s: document accepted", name)
+		}
+		if len(g.Nodes) != 6 {
+			t.Errorf("%s: rejected import changed the graph", name)
+		}
+	}
+}
+
+// matrixJSON returns the JSON form of m, where missing edges are null
+func matrixJSON(t *testing.T, m interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSampleGraphAdjacencyMatrix(t *testing.T) {
+	want := `{"nodes":["A","B","C","D","E","F"],"weights":[` +
+		`[null,4,2,null,null,null],` +
+		`[4,null,1,5,null,null],` +
+		`[2,1,null,8,10,null],` +
+		`[null,5,8,null,2,6],` +
+		`[null,null,10,2,null,3],` +
+		`[null,null,null,6,3,null]]}`
+	if got := matrixJSON(t, CreateSampleGraph().AdjacencyMatrix()); got != want {
+		t.Errorf("matrix %s, want %s", got, want)
+	}
+
+	// A directed graph fills only the row of each edge's source, keeping
+	// the lightest of parallel edges
+	g := NewGraph()
+	g.Directed = true
+	g.AllowParallelEdges = true
+	g.AddNode("A", 0, 0)
+	g.AddNode("B", 0, 0)
+	g.AddEdge("A", "B", 7)
+	g.AddEdge("A", "B", 3)
+	weights, _ := g.ToAdjacencyMatrix()
+	if got, want := matrixJSON(t, weights), `[[null,3],[null,null]]`; got != want {
+		t.Errorf("directed matrix %s, want %s", got, want)
+	}
+}
+
+func TestAdjacencyMatrixTellsNegativeWeightsFromMissingEdges(t *testing.T) {
+	g := buildGraph(t, true, GraphEdgeInput{From: "A", To: "B", Weight: -1})
+	if got, want := matrixJSON(t, g.AdjacencyMatrix()), `{"nodes":["A","B"],"weights":[[null,-1],[null,null]]}`; got != want {
+		t.Errorf("matrix %s, want %s", got, want)
+	}
+}
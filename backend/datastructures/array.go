@@ -0,0 +1,215 @@
+package datastructures
+
+import "time"
+
+// MaxArraySize bounds the arrays the sorter accepts. Every step carries a
+// copy of the array, so the step log grows as n² log n.
+const MaxArraySize = 500
+
+// ArraySnapshot is the state of the array being sorted at one step
+type ArraySnapshot struct {
+	Values    []int `json:"values"`
+	Highlight []int `json:"highlight,omitempty"`
+	// Pivot is the index of the current quicksort pivot
+	Pivot *int `json:"pivot,omitempty"`
+}
+
+// ArraySorter visualizes sorting algorithms on an array of values. It keeps
+// no state between operations: every operation sorts the values it is given.
+type ArraySorter struct {
+	items []int
+	steps []Step
+
+	// started is when the current operation began, the origin of its step
+	// timestamps
+	started time.Time
+
+	// includeSnapshots controls whether every step embeds an ArraySnapshot
+	includeSnapshots bool
+
+	// locale selects the language of step descriptions and messages
+	locale Locale
+}
+
+// NewArraySorter creates an array sorter
+func NewArraySorter() *ArraySorter {
+	return &ArraySorter{
+		steps:            make([]Step, 0),
+		includeSnapshots: true,
+	}
+}
+
+// SetIncludeSnapshots toggles per-step array snapshots
+func (s *ArraySorter) SetIncludeSnapshots(include bool) {
+	s.includeSnapshots = include
+}
+
+// SetLocale selects the language of step descriptions and messages
+func (s *ArraySorter) SetLocale(locale Locale) {
+	s.locale = locale
+}
+
+// Values returns a copy of the array the last sort left behind
+func (s *ArraySorter) Values() []int {
+	return append([]int{}, s.items...)
+}
+
+func (s *ArraySorter) msg(key string, args ...interface{}) string {
+	return Localize(s.locale, key, args...)
+}
+
+func (s *ArraySorter) clearSteps() {
+	s.steps = make([]Step, 0)
+	s.started = time.Now()
+}
+
+// addStep records a step highlighting the given indices. pivot is the index
+// of the current pivot, if any.
+func (s *ArraySorter) addStep(stepType StepType, desc string, pivot *int, highlights ...int) {
+	step := Step{
+		Type:        stepType,
+		Description: desc,
+		Highlight:   highlights,
+	}
+	if s.includeSnapshots {
+		step.ArrayState = &ArraySnapshot{
+			Values:    append([]int(nil), s.items...),
+			Highlight: highlights,
+			Pivot:     pivot,
+		}
+	}
+	step.stamp(len(s.steps), s.started)
+	s.steps = append(s.steps, step)
+}
+
+// begin loads values for a sort, returning an unsuccessful result if there
+// are too many of them
+func (s *ArraySorter) begin(values []int) (OperationResult, bool) {
+	s.clearSteps()
+	if len(values) > MaxArraySize {
+		return OperationResult{
+			Success: false,
+			Message: s.msg("array.too_large", MaxArraySize),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}, false
+	}
+	s.items = append([]int(nil), values...)
+	s.addStep(StepVisit, s.msg("array.start", len(values)), nil)
+	return OperationResult{}, true
+}
+
+// finish builds the result of a completed sort
+func (s *ArraySorter) finish(algorithm string) OperationResult {
+	s.addStep(StepComplete, s.msg("array.done", s.items), nil)
+	return OperationResult{
+		Success: true,
+		Message: s.msg("array.success", algorithm, len(s.items)),
+		Steps:   s.steps,
+		Values:  s.items,
+	}
+}
+
+func (s *ArraySorter) swap(i, j, pivot int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.addStep(StepSwap, s.msg("array.swap", i, j), &pivot, i, j)
+}
+
+// QuickSort sorts values with quicksort, using the Lomuto scheme with the
+// last element of every range as its pivot
+func (s *ArraySorter) QuickSort(values []int) OperationResult {
+	if result, ok := s.begin(values); !ok {
+		return result
+	}
+	s.quickSort(0, len(s.items)-1)
+	return s.finish("quicksort")
+}
+
+func (s *ArraySorter) quickSort(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+
+	pivot := hi
+	s.addStep(StepPivot, s.msg("array.quick.pivot", s.items[pivot], pivot, lo, hi), &pivot, pivot)
+	store := lo
+	for j := lo; j < hi; j++ {
+		s.addStep(StepCompare, s.msg("array.quick.compare", s.items[j], s.items[pivot]), &pivot, j, pivot)
+		if s.items[j] < s.items[pivot] {
+			if store != j {
+				s.swap(store, j, pivot)
+			}
+			store++
+		}
+	}
+	if store != hi {
+		s.swap(store, hi, store)
+	}
+	s.addStep(StepVisit, s.msg("array.quick.placed", s.items[store], store), &store, store)
+
+	s.quickSort(lo, store-1)
+	s.quickSort(store+1, hi)
+}
+
+// MergeSort sorts values with top-down mergesort
+func (s *ArraySorter) MergeSort(values []int) OperationResult {
+	if result, ok := s.begin(values); !ok {
+		return result
+	}
+	s.mergeSort(0, len(s.items)-1)
+	return s.finish("mergesort")
+}
+
+func (s *ArraySorter) mergeSort(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	s.addStep(StepVisit, s.msg("array.merge.split", lo, mid, mid+1, hi), nil, indexRange(lo, hi)...)
+	s.mergeSort(lo, mid)
+	s.mergeSort(mid+1, hi)
+	s.merge(lo, mid, hi)
+}
+
+// merge combines the sorted ranges [lo, mid] and [mid+1, hi], writing the
+// merged values back one index at a time
+func (s *ArraySorter) merge(lo, mid, hi int) {
+	left := append([]int(nil), s.items[lo:mid+1]...)
+	right := append([]int(nil), s.items[mid+1:hi+1]...)
+
+	i, j := 0, 0
+	for k := lo; k <= hi; k++ {
+		var value int
+		switch {
+		case i < len(left) && j < len(right):
+			// The left half has been copied out, so only the right value
+			// is still at its index
+			s.addStep(StepCompare, s.msg("array.merge.compare", left[i], right[j]), nil, k, mid+1+j)
+			if left[i] <= right[j] {
+				value = left[i]
+				i++
+			} else {
+				value = right[j]
+				j++
+			}
+		case i < len(left):
+			value = left[i]
+			i++
+		default:
+			value = right[j]
+			j++
+		}
+		s.items[k] = value
+		s.addStep(StepWrite, s.msg("array.merge.write", value, k), nil, k)
+	}
+	s.addStep(StepVisit, s.msg("array.merge.merged", lo, hi), nil, indexRange(lo, hi)...)
+}
+
+// indexRange returns the indices lo through hi
+func indexRange(lo, hi int) []int {
+	indices := make([]int, 0, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
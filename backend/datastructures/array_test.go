@@ -0,0 +1,58 @@
+package datastructures
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSortsProduceSortedOutput(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	values := make([]int, 60)
+	for i := range values {
+		values[i] = rng.Intn(20)
+	}
+	want := slices.Sorted(slices.Values(values))
+
+	sorter := NewArraySorter()
+	for name, sort := range map[string]func([]int) OperationResult{
+		"quicksort": sorter.QuickSort,
+		"mergesort": sorter.MergeSort,
+	} {
+		t.Run(name, func(t *testing.T) {
+			input := slices.Clone(values)
+			result := sort(input)
+			if !result.Success || !slices.Equal(result.Values, want) {
+				t.Fatalf("success %v values %v, want %v", result.Success, result.Values, want)
+			}
+			if !slices.Equal(input, values) {
+				t.Error("the input slice was modified")
+			}
+			last := result.Steps[len(result.Steps)-1]
+			if last.ArrayState == nil || !slices.Equal(last.ArrayState.Values, want) {
+				t.Error("the last step does not show the sorted array")
+			}
+
+			pivots := 0
+			for _, step := range result.Steps {
+				if step.Type != StepPivot {
+					continue
+				}
+				pivots++
+				if step.ArrayState.Pivot == nil || !slices.Equal(step.Highlight, []int{*step.ArrayState.Pivot}) {
+					t.Fatalf("pivot step %d highlights %v, pivot %v", step.Index, step.Highlight, step.ArrayState.Pivot)
+				}
+			}
+			if name == "quicksort" && pivots == 0 {
+				t.Error("quicksort emitted no pivot steps")
+			}
+			if name == "mergesort" && pivots != 0 {
+				t.Errorf("mergesort emitted %d pivot steps", pivots)
+			}
+		})
+	}
+
+	if result := sorter.QuickSort(make([]int, MaxArraySize+1)); result.Success || result.Code != CodeInvalidParam {
+		t.Errorf("oversized input: success %v code %q", result.Success, result.Code)
+	}
+}
@@ -0,0 +1,89 @@
+package datastructures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// asciiNode is a tree node prepared for text rendering
+type asciiNode struct {
+	label       string
+	left, right *asciiNode
+}
+
+// renderASCII draws root with box-drawing characters, one node per line.
+// Children are indented under their parent and tagged L or R, so a lone
+// child still shows its side. An empty tree renders as an empty string.
+func renderASCII(root *asciiNode) string {
+	if root == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(root.label + "\n")
+	writeASCIIChildren(&b, root, "")
+	return b.String()
+}
+
+func writeASCIIChildren(b *strings.Builder, node *asciiNode, prefix string) {
+	children := make([]*asciiNode, 0, 2)
+	sides := make([]string, 0, 2)
+	if node.left != nil {
+		children = append(children, node.left)
+		sides = append(sides, "L")
+	}
+	if node.right != nil {
+		children = append(children, node.right)
+		sides = append(sides, "R")
+	}
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(b, "%s%s%s: %s\n", prefix, branch, sides[i], child.label)
+		writeASCIIChildren(b, child, prefix+indent)
+	}
+}
+
+// ToASCII renders the AVL Tree as a text diagram with each node's height
+func (t *AVLTree) ToASCII() string {
+	return renderASCII(t.asciiNode(t.Root))
+}
+
+func (t *AVLTree) asciiNode(node *AVLNode) *asciiNode {
+	if node == nil {
+		return nil
+	}
+	a := &asciiNode{
+		label: fmt.Sprintf("%d (h=%d)", node.Value, node.Height),
+		left:  t.asciiNode(node.Left),
+	}
+	if !t.threads[node] {
+		a.right = t.asciiNode(node.Right)
+	}
+	return a
+}
+
+// ToASCII renders the Red-Black Tree as a text diagram, marking every node
+// R or B by color
+func (t *RedBlackTree) ToASCII() string {
+	return renderASCII(t.asciiNode(t.Root))
+}
+
+func (t *RedBlackTree) asciiNode(node *RBNode) *asciiNode {
+	if node == t.NIL {
+		return nil
+	}
+	color := "B"
+	if node.Color == Red {
+		color = "R"
+	}
+	a := &asciiNode{
+		label: fmt.Sprintf("%d (%s)", node.Value, color),
+		left:  t.asciiNode(node.Left),
+	}
+	if !t.threads[node] {
+		a.right = t.asciiNode(node.Right)
+	}
+	return a
+}
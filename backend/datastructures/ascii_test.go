@@ -0,0 +1,47 @@
+package datastructures
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRedBlackASCIIIndentsChildrenUnderParent(t *testing.T) {
+	tree := NewRedBlackTree()
+	for _, v := range []int{2, 1, 3} {
+		tree.Insert(v)
+	}
+	want := "2 (B)\n" +
+		"├── L: 1 (R)\n" +
+		"└── R: 3 (R)\n"
+	if got := tree.ToASCII(); got != want {
+		t.Errorf("ToASCII() =\n%s\nwant\n%s", got, want)
+	}
+	if got := NewRedBlackTree().ToASCII(); got != "" {
+		t.Errorf("empty tree rendered %q", got)
+	}
+}
+
+func TestAVLASCIIListsEveryValue(t *testing.T) {
+	tree := NewAVLTree()
+	values := []int{4, 2, 6, 1, 3, 5, 7}
+	for _, v := range values {
+		tree.Insert(v)
+	}
+	got := tree.ToASCII()
+	for _, v := range values {
+		if !strings.Contains(got, strconv.Itoa(v)+" (h=") {
+			t.Errorf("value %d missing from\n%s", v, got)
+		}
+	}
+	want := "4 (h=3)\n" +
+		"├── L: 2 (h=2)\n" +
+		"│   ├── L: 1 (h=1)\n" +
+		"│   └── R: 3 (h=1)\n" +
+		"└── R: 6 (h=2)\n" +
+		"    ├── L: 5 (h=1)\n" +
+		"    └── R: 7 (h=1)\n"
+	if got != want {
+		t.Errorf("ToASCII() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,6 +1,9 @@
 package datastructures
 
-import "fmt"
+import (
+	"context"
+	"time"
+)
 
 // AVLNode represents a node in the AVL Tree
 type AVLNode struct {
@@ -16,19 +19,91 @@ type AVLTree struct {
 	Root   *AVLNode
 	nextID int
 	steps  []Step
+
+	// started is when the current operation began, the origin of its step
+	// timestamps
+	started time.Time
+
+	// valueIDs maps every value ever inserted to its permanent node ID
+	valueIDs map[int]int
+
+	// threads marks the nodes whose Right pointer is a temporary Morris
+	// thread; snapshots do not follow them
+	threads map[*AVLNode]bool
+
+	// layout is the canvas size snapshots are laid out for
+	layout TreeLayout
+
+	// includeSnapshots controls whether every step embeds a full TreeState.
+	// Disabling it keeps descriptions and highlights but skips the O(n) walk.
+	includeSnapshots bool
+
+	// operand is the value the current insert/delete/search works on and is
+	// attached to every step it records
+	operand *int
+
+	// rotations counts rotations performed by the current operation
+	rotations int
+
+	// invariant is the property the running rebalancing restores; every
+	// step recorded meanwhile carries it
+	invariant string
+
+	// locale selects the language of step descriptions and messages
+	locale Locale
+
+	// ctx aborts long-running operations when canceled
+	ctx context.Context
 }
 
 // NewAVLTree creates a new AVL Tree
 func NewAVLTree() *AVLTree {
 	return &AVLTree{
-		Root:   nil,
-		nextID: 0,
-		steps:  make([]Step, 0),
+		Root:             nil,
+		nextID:           0,
+		steps:            make([]Step, 0),
+		includeSnapshots: true,
+		layout:           newTreeLayout(0, 0),
 	}
 }
 
+// SetIncludeSnapshots toggles per-step tree snapshots. The final tree
+// snapshot of an operation is always produced.
+func (t *AVLTree) SetIncludeSnapshots(include bool) {
+	t.includeSnapshots = include
+}
+
+// SetLayout sets the canvas width and the distance between levels of the
+// snapshots. Non-positive values restore the defaults.
+func (t *AVLTree) SetLayout(width, levelHeight float64) {
+	t.layout = newTreeLayout(width, levelHeight)
+}
+
+// SetLocale selects the language of step descriptions and messages
+func (t *AVLTree) SetLocale(locale Locale) {
+	t.locale = locale
+}
+
+func (t *AVLTree) msg(key string, args ...interface{}) string {
+	return Localize(t.locale, key, args...)
+}
+
 func (t *AVLTree) clearSteps() {
 	t.steps = make([]Step, 0)
+	t.started = time.Now()
+	t.rotations = 0
+	t.operand = nil
+}
+
+// newResult builds an OperationResult from the steps recorded so far
+func (t *AVLTree) newResult(success bool, message string) OperationResult {
+	return OperationResult{
+		Success:       success,
+		Message:       message,
+		Steps:         t.steps,
+		FinalTree:     t.getTreeSnapshot(),
+		RotationCount: t.rotations,
+	}
 }
 
 func (t *AVLTree) addStep(stepType StepType, desc string, nodeID *int, extra ...interface{}) {
@@ -36,19 +111,24 @@ func (t *AVLTree) addStep(stepType StepType, desc string, nodeID *int, extra ...
 		Type:        stepType,
 		Description: desc,
 		NodeID:      nodeID,
-		TreeState:   t.getTreeSnapshot(),
+		Value:       t.operand,
+		Invariant:   t.invariant,
+	}
+	if t.includeSnapshots {
+		step.TreeState = t.getTreeSnapshot()
 	}
 	if len(extra) > 0 {
 		if highlights, ok := extra[0].([]int); ok {
 			step.Highlight = highlights
 		}
 	}
+	step.stamp(len(t.steps), t.started)
 	t.steps = append(t.steps, step)
 }
 
 func (t *AVLTree) getTreeSnapshot() []TreeNodeSnapshot {
 	var nodes []TreeNodeSnapshot
-	t.inorderSnapshot(t.Root, &nodes, 0, 0, 800)
+	t.inorderSnapshot(t.Root, &nodes, 0, 0, t.layout.Width)
 	return nodes
 }
 
@@ -58,7 +138,7 @@ func (t *AVLTree) inorderSnapshot(node *AVLNode, nodes *[]TreeNodeSnapshot, dept
 	}
 
 	x := (xMin + xMax) / 2
-	y := float64(depth*80 + 50)
+	y := t.layout.levelY(depth)
 
 	snapshot := TreeNodeSnapshot{
 		ID:     node.ID,
@@ -72,15 +152,21 @@ func (t *AVLTree) inorderSnapshot(node *AVLNode, nodes *[]TreeNodeSnapshot, dept
 		leftID := node.Left.ID
 		snapshot.LeftID = &leftID
 	}
-	if node.Right != nil {
-		rightID := node.Right.ID
+	right := node.Right
+	if t.threads[node] {
+		threadID := right.ID
+		snapshot.ThreadID = &threadID
+		right = nil
+	}
+	if right != nil {
+		rightID := right.ID
 		snapshot.RightID = &rightID
 	}
 
 	*nodes = append(*nodes, snapshot)
 
 	t.inorderSnapshot(node.Left, nodes, depth+1, xMin, x)
-	t.inorderSnapshot(node.Right, nodes, depth+1, x, xMax)
+	t.inorderSnapshot(right, nodes, depth+1, x, xMax)
 }
 
 func height(node *AVLNode) int {
@@ -104,7 +190,8 @@ func (t *AVLTree) getBalance(node *AVLNode) int {
 	return height(node.Left) - height(node.Right)
 }
 
-func (t *AVLTree) rightRotate(y *AVLNode) *AVLNode {
+func (t *AVLTree) rightRotate(link **AVLNode) {
+	y := *link
 	x := y.Left
 	T2 := x.Right
 
@@ -114,12 +201,14 @@ func (t *AVLTree) rightRotate(y *AVLNode) *AVLNode {
 	y.Height = max(height(y.Left), height(y.Right)) + 1
 	x.Height = max(height(x.Left), height(x.Right)) + 1
 
-	t.addStep(StepRotateRight, fmt.Sprintf("对节点 %d 进行右旋", y.Value), &y.ID, []int{x.ID, y.ID})
-
-	return x
+	// Relink before recording so the step's snapshot contains both nodes
+	*link = x
+	t.rotations++
+	t.addStep(StepRotateRight, t.msg("tree.rotate_right", y.Value), &y.ID, []int{x.ID, y.ID})
 }
 
-func (t *AVLTree) leftRotate(x *AVLNode) *AVLNode {
+func (t *AVLTree) leftRotate(link **AVLNode) {
+	x := *link
 	y := x.Right
 	T2 := y.Left
 
@@ -129,95 +218,204 @@ func (t *AVLTree) leftRotate(x *AVLNode) *AVLNode {
 	x.Height = max(height(x.Left), height(x.Right)) + 1
 	y.Height = max(height(y.Left), height(y.Right)) + 1
 
-	t.addStep(StepRotateLeft, fmt.Sprintf("对节点 %d 进行左旋", x.Value), &x.ID, []int{x.ID, y.ID})
-
-	return y
+	*link = y
+	t.rotations++
+	t.addStep(StepRotateLeft, t.msg("tree.rotate_left", x.Value), &x.ID, []int{x.ID, y.ID})
 }
 
-func (t *AVLTree) insert(node *AVLNode, value int) *AVLNode {
+// insert adds value below *link, returning false if the context was
+// canceled on the way down. Nothing has been changed by then, so a canceled
+// insert leaves the tree as it was.
+func (t *AVLTree) insert(link **AVLNode, value int) bool {
+	node := *link
 	if node == nil {
-		newNode := &AVLNode{
-			ID:     t.nextID,
+		*link = &AVLNode{
+			ID:     t.idFor(value),
 			Value:  value,
 			Height: 1,
 		}
-		t.nextID++
-		t.addStep(StepInsert, fmt.Sprintf("插入节点 %d", value), &newNode.ID, []int{newNode.ID})
-		return newNode
+		t.addStep(StepInsert, t.msg("avl.insert.node", value), &(*link).ID, []int{(*link).ID})
+		return true
+	}
+	if contextDone(t.ctx) {
+		return false
 	}
 
-	t.addStep(StepCompare, fmt.Sprintf("比较 %d 与节点 %d", value, node.Value), &node.ID, []int{node.ID})
+	t.addStep(StepCompare, t.msg("tree.compare", value, node.Value), &node.ID, []int{node.ID})
 
+	inserted := true
 	if value < node.Value {
-		node.Left = t.insert(node.Left, value)
+		inserted = t.insert(&node.Left, value)
 	} else if value > node.Value {
-		node.Right = t.insert(node.Right, value)
+		inserted = t.insert(&node.Right, value)
 	} else {
-		return node // Duplicate values not allowed
+		return true // Duplicate values not allowed
+	}
+	if !inserted {
+		return false
 	}
 
 	node.Height = 1 + max(height(node.Left), height(node.Right))
 
+	t.rebalanceInsert(link, value)
+	return true
+}
+
+// beginFixup and endFixup bracket the steps of one rebalancing so that a
+// player can group them. The bracket steps have no node.
+func (t *AVLTree) beginFixup() {
+	t.invariant = t.msg("avl.invariant.balance")
+	t.addStep(StepRebalance, t.msg("tree.fixup.start"), nil)
+}
+
+func (t *AVLTree) endFixup() {
+	t.addStep(StepRebalance, t.msg("tree.fixup.done"), nil)
+	t.invariant = ""
+}
+
+// rebalanceInsert restores the AVL property of the subtree at *link after
+// value was inserted somewhere below it, relinking the new subtree root
+func (t *AVLTree) rebalanceInsert(link **AVLNode, value int) {
+	node := *link
 	balance := t.getBalance(node)
+	if balance >= -1 && balance <= 1 {
+		return
+	}
+	t.beginFixup()
+	defer t.endFixup()
 
 	// Left Left Case
 	if balance > 1 && value < node.Left.Value {
-		t.addStep(StepRebalance, "LL情况：需要右旋", &node.ID)
-		return t.rightRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.ll"), &node.ID)
+		t.rightRotate(link)
+		return
 	}
 
 	// Right Right Case
 	if balance < -1 && value > node.Right.Value {
-		t.addStep(StepRebalance, "RR情况：需要左旋", &node.ID)
-		return t.leftRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.rr"), &node.ID)
+		t.leftRotate(link)
+		return
 	}
 
 	// Left Right Case
 	if balance > 1 && value > node.Left.Value {
-		t.addStep(StepRebalance, "LR情况：先左旋后右旋", &node.ID)
-		node.Left = t.leftRotate(node.Left)
-		return t.rightRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.lr"), &node.ID)
+		t.leftRotate(&node.Left)
+		t.rightRotate(link)
+		return
 	}
 
 	// Right Left Case
 	if balance < -1 && value < node.Right.Value {
-		t.addStep(StepRebalance, "RL情况：先右旋后左旋", &node.ID)
-		node.Right = t.rightRotate(node.Right)
-		return t.leftRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.rl"), &node.ID)
+		t.rightRotate(&node.Right)
+		t.leftRotate(link)
 	}
-
-	return node
 }
 
 // Insert inserts a value into the AVL Tree
 func (t *AVLTree) Insert(value int) OperationResult {
 	t.clearSteps()
-	t.addStep(StepInsert, fmt.Sprintf("开始插入值 %d", value), nil)
-	t.Root = t.insert(t.Root, value)
-	t.addStep(StepComplete, "插入完成", nil)
+	t.operand = &value
+	t.addStep(StepInsert, t.msg("tree.insert.start", value), nil)
+	if !t.insert(&t.Root, value) {
+		return t.canceledResult()
+	}
+	t.addStep(StepComplete, t.msg("tree.insert.done"), nil)
 
-	return OperationResult{
-		Success:   true,
-		Steps:     t.steps,
-		FinalTree: t.getTreeSnapshot(),
+	return t.newResult(true, "")
+}
+
+// InsertIterative inserts a value like Insert, but walks down with an
+// explicit parent stack and rebalances on the way back up instead of
+// recursing, so very deep trees cannot exhaust the goroutine stack
+func (t *AVLTree) InsertIterative(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+	t.addStep(StepInsert, t.msg("tree.insert.start", value), nil)
+	if !t.insertIterative(value) {
+		return t.canceledResult()
+	}
+	t.addStep(StepComplete, t.msg("tree.insert.done"), nil)
+
+	return t.newResult(true, "")
+}
+
+// insertIterative adds value without recursion, returning false if the
+// context was canceled on the way down. Like insert, a canceled insert
+// leaves the tree as it was.
+func (t *AVLTree) insertIterative(value int) bool {
+	var path []*AVLNode
+	current := t.Root
+	for current != nil {
+		if contextDone(t.ctx) {
+			return false
+		}
+		t.addStep(StepCompare, t.msg("tree.compare", value, current.Value), &current.ID, []int{current.ID})
+		if value == current.Value {
+			// Duplicate values not allowed
+			return true
+		}
+		path = append(path, current)
+		if value < current.Value {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+
+	newNode := &AVLNode{
+		ID:     t.idFor(value),
+		Value:  value,
+		Height: 1,
+	}
+	if len(path) == 0 {
+		t.Root = newNode
+	} else if parent := path[len(path)-1]; value < parent.Value {
+		parent.Left = newNode
+	} else {
+		parent.Right = newNode
 	}
+	t.addStep(StepInsert, t.msg("avl.insert.node", value), &newNode.ID, []int{newNode.ID})
+
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		node.Height = 1 + max(height(node.Left), height(node.Right))
+
+		link := &t.Root
+		if i > 0 {
+			if parent := path[i-1]; parent.Left == node {
+				link = &parent.Left
+			} else {
+				link = &parent.Right
+			}
+		}
+		t.rebalanceInsert(link, value)
+	}
+
+	return true
 }
 
 // Search searches for a value in the AVL Tree
 func (t *AVLTree) Search(value int) OperationResult {
 	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
 
 	current := t.Root
 	for current != nil {
-		t.addStep(StepCompare, fmt.Sprintf("比较 %d 与节点 %d", value, current.Value), &current.ID, []int{current.ID})
+		t.addStep(StepCompare, t.msg("tree.compare", value, current.Value), &current.ID, []int{current.ID})
 		if value == current.Value {
-			t.addStep(StepFound, fmt.Sprintf("找到节点 %d", value), &current.ID, []int{current.ID})
-			return OperationResult{
-				Success:   true,
-				Message:   fmt.Sprintf("找到值 %d", value),
-				Steps:     t.steps,
-				FinalTree: t.getTreeSnapshot(),
-			}
+			t.addStep(StepFound, t.msg("tree.search.found_node", value), &current.ID, []int{current.ID})
+			return t.newResult(true, t.msg("tree.search.found", value))
 		} else if value < current.Value {
 			current = current.Left
 		} else {
@@ -225,13 +423,26 @@ func (t *AVLTree) Search(value int) OperationResult {
 		}
 	}
 
-	t.addStep(StepNotFound, fmt.Sprintf("值 %d 不存在于树中", value), nil)
-	return OperationResult{
-		Success:   false,
-		Message:   fmt.Sprintf("值 %d 不存在", value),
-		Steps:     t.steps,
-		FinalTree: t.getTreeSnapshot(),
+	t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
+}
+
+// Contains reports whether value is in the AVL Tree without recording steps
+func (t *AVLTree) Contains(value int) bool {
+	current := t.Root
+	for current != nil {
+		if value == current.Value {
+			return true
+		} else if value < current.Value {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
 	}
+	return false
 }
 
 // minValueNode finds the node with minimum value in a subtree
@@ -243,43 +454,61 @@ func (t *AVLTree) minValueNode(node *AVLNode) *AVLNode {
 	return current
 }
 
-// delete deletes a node with given value from the subtree
-func (t *AVLTree) delete(node *AVLNode, value int) *AVLNode {
+// delete deletes a node with given value from the subtree, returning false
+// if the context was canceled while searching for it. Nothing has been
+// changed by then, so a canceled delete leaves the tree as it was.
+func (t *AVLTree) delete(link **AVLNode, value int) bool {
+	node := *link
 	if node == nil {
-		t.addStep(StepNotFound, fmt.Sprintf("值 %d 不存在于树中", value), nil)
-		return node
+		t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+		return true
+	}
+	if contextDone(t.ctx) {
+		return false
 	}
 
-	t.addStep(StepCompare, fmt.Sprintf("比较 %d 与节点 %d", value, node.Value), &node.ID, []int{node.ID})
+	t.addStep(StepCompare, t.msg("tree.compare", value, node.Value), &node.ID, []int{node.ID})
 
 	if value < node.Value {
-		node.Left = t.delete(node.Left, value)
+		if !t.delete(&node.Left, value) {
+			return false
+		}
 	} else if value > node.Value {
-		node.Right = t.delete(node.Right, value)
+		if !t.delete(&node.Right, value) {
+			return false
+		}
 	} else {
 		// Node to be deleted found
-		t.addStep(StepDelete, fmt.Sprintf("找到要删除的节点 %d", value), &node.ID, []int{node.ID})
+		t.addStep(StepDelete, t.msg("tree.delete.found", value), &node.ID, []int{node.ID})
 
 		// Node with only one child or no child
 		if node.Left == nil {
-			t.addStep(StepDelete, fmt.Sprintf("节点 %d 没有左子节点，用右子节点替换", node.Value), &node.ID)
-			return node.Right
+			t.addStep(StepDelete, t.msg("tree.delete.no_left", node.Value), &node.ID)
+			*link = node.Right
+			return true
 		} else if node.Right == nil {
-			t.addStep(StepDelete, fmt.Sprintf("节点 %d 没有右子节点，用左子节点替换", node.Value), &node.ID)
-			return node.Left
+			t.addStep(StepDelete, t.msg("tree.delete.no_right", node.Value), &node.ID)
+			*link = node.Left
+			return true
 		}
 
 		// Node with two children: Get the inorder successor (smallest in right subtree)
 		successor := t.minValueNode(node.Right)
-		t.addStep(StepDelete, fmt.Sprintf("节点 %d 有两个子节点，找到后继节点 %d", node.Value, successor.Value), &successor.ID, []int{node.ID, successor.ID})
-
-		// Copy the inorder successor's value to this node
-		node.Value = successor.Value
-		node.ID = successor.ID
-		t.addStep(StepDelete, fmt.Sprintf("用后继节点 %d 替换被删除节点", successor.Value), &node.ID)
-
-		// Delete the inorder successor
-		node.Right = t.delete(node.Right, successor.Value)
+		t.addStep(StepDelete, t.msg("tree.delete.successor", node.Value, successor.Value), &successor.ID, []int{node.ID, successor.ID})
+
+		// Unlink the successor from the right subtree and move it into the
+		// deleted node's place, so both keep their IDs. The removal has
+		// started, so the successor is unlinked even if the context is
+		// canceled meanwhile.
+		ctx := t.ctx
+		t.ctx = nil
+		t.delete(&node.Right, successor.Value)
+		t.ctx = ctx
+		successor.Left = node.Left
+		successor.Right = node.Right
+		*link = successor
+		node = successor
+		t.addStep(StepDelete, t.msg("tree.delete.replace", successor.Value), &node.ID)
 	}
 
 	// Update height
@@ -287,72 +516,91 @@ func (t *AVLTree) delete(node *AVLNode, value int) *AVLNode {
 
 	// Get balance factor
 	balance := t.getBalance(node)
+	if balance >= -1 && balance <= 1 {
+		return true
+	}
+	t.beginFixup()
+	defer t.endFixup()
 
 	// Left Left Case
 	if balance > 1 && t.getBalance(node.Left) >= 0 {
-		t.addStep(StepRebalance, "LL情况：需要右旋", &node.ID, []int{node.ID})
-		return t.rightRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.ll"), &node.ID, []int{node.ID})
+		t.rightRotate(link)
+		return true
 	}
 
 	// Left Right Case
 	if balance > 1 && t.getBalance(node.Left) < 0 {
-		t.addStep(StepRebalance, "LR情况：先左旋后右旋", &node.ID, []int{node.ID})
-		node.Left = t.leftRotate(node.Left)
-		return t.rightRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.lr"), &node.ID, []int{node.ID})
+		t.leftRotate(&node.Left)
+		t.rightRotate(link)
+		return true
 	}
 
 	// Right Right Case
 	if balance < -1 && t.getBalance(node.Right) <= 0 {
-		t.addStep(StepRebalance, "RR情况：需要左旋", &node.ID, []int{node.ID})
-		return t.leftRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.rr"), &node.ID, []int{node.ID})
+		t.leftRotate(link)
+		return true
 	}
 
 	// Right Left Case
 	if balance < -1 && t.getBalance(node.Right) > 0 {
-		t.addStep(StepRebalance, "RL情况：先右旋后左旋", &node.ID, []int{node.ID})
-		node.Right = t.rightRotate(node.Right)
-		return t.leftRotate(node)
+		t.addStep(StepRebalance, t.msg("avl.case.rl"), &node.ID, []int{node.ID})
+		t.rightRotate(&node.Right)
+		t.leftRotate(link)
 	}
-
-	return node
+	return true
 }
 
 // Delete deletes a value from the AVL Tree
 func (t *AVLTree) Delete(value int) OperationResult {
 	t.clearSteps()
-	t.addStep(StepDelete, fmt.Sprintf("开始删除值 %d", value), nil)
-
-	// Check if value exists
-	found := false
-	current := t.Root
-	for current != nil {
-		if value == current.Value {
-			found = true
-			break
-		} else if value < current.Value {
-			current = current.Left
-		} else {
-			current = current.Right
+	t.operand = &value
+	t.addStep(StepDelete, t.msg("tree.delete.start", value), nil)
+
+	if !t.Contains(value) {
+		t.addStep(StepNotFound, t.msg("tree.delete.missing_step", value), nil)
+		result := t.newResult(false, t.msg("tree.delete.missing", value))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		if t.Root == nil {
+			result.Reason = ReasonEmptyTree
 		}
+		return result
 	}
 
-	if !found {
-		t.addStep(StepNotFound, fmt.Sprintf("值 %d 不存在于树中，无法删除", value), nil)
-		return OperationResult{
-			Success:   false,
-			Message:   fmt.Sprintf("值 %d 不存在，无法删除", value),
-			Steps:     t.steps,
-			FinalTree: t.getTreeSnapshot(),
-		}
+	if !t.delete(&t.Root, value) {
+		return t.canceledResult()
 	}
+	t.addStep(StepComplete, t.msg("tree.delete.done", value), nil)
 
-	t.Root = t.delete(t.Root, value)
-	t.addStep(StepComplete, fmt.Sprintf("删除节点 %d 完成", value), nil)
+	return t.newResult(true, t.msg("tree.delete.success", value))
+}
 
+// State returns a snapshot of the tree without modifying it
+func (t *AVLTree) State() OperationResult {
 	return OperationResult{
 		Success:   true,
-		Message:   fmt.Sprintf("成功删除值 %d", value),
-		Steps:     t.steps,
+		Message:   t.msg("tree.state", t.Size()),
+		Steps:     []Step{},
 		FinalTree: t.getTreeSnapshot(),
 	}
 }
+
+// Height returns the height of the AVL Tree (0 for an empty tree)
+func (t *AVLTree) Height() int {
+	return height(t.Root)
+}
+
+// Size returns the number of nodes in the AVL Tree
+func (t *AVLTree) Size() int {
+	return avlSize(t.Root)
+}
+
+func avlSize(node *AVLNode) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + avlSize(node.Left) + avlSize(node.Right)
+}
@@ -0,0 +1,128 @@
+package datastructures
+
+import "testing"
+
+// benchmarkInsert10k inserts and deletes one value in a balanced
+// 10k-node AVL tree
+func benchmarkInsert10k(b *testing.B, includeSnapshots bool) {
+	values := make([]int, 10000)
+	for i := range values {
+		values[i] = i * 2
+	}
+	tree := NewAVLTree()
+	tree.SetIncludeSnapshots(false)
+	tree.BuildBalanced(values)
+	tree.SetIncludeSnapshots(includeSnapshots)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Insert(10001)
+		tree.Delete(10001)
+	}
+}
+
+// BenchmarkAVLInsert10k shows the cost of per-step snapshots on a
+// 10k-node tree
+func BenchmarkAVLInsert10k(b *testing.B) {
+	b.Run("snapshots", func(b *testing.B) { benchmarkInsert10k(b, true) })
+	b.Run("no_snapshots", func(b *testing.B) { benchmarkInsert10k(b, false) })
+}
+
+// checkAVL fails t unless every node below node stores its height and has a
+// balance factor within [-1, 1], and returns the subtree height
+func checkAVL(t *testing.T, node *AVLNode) int {
+	t.Helper()
+	if node == nil {
+		return 0
+	}
+	left, right := checkAVL(t, node.Left), checkAVL(t, node.Right)
+	if left-right > 1 || right-left > 1 {
+		t.Fatalf("node %d unbalanced: left height %d, right height %d", node.Value, left, right)
+	}
+	h := 1 + max(left, right)
+	if node.Height != h {
+		t.Fatalf("node %d stores height %d, want %d", node.Value, node.Height, h)
+	}
+	return h
+}
+
+func TestInsertIterativeSorted100k(t *testing.T) {
+	const n = 100000
+	tree := NewAVLTree()
+	tree.SetIncludeSnapshots(false)
+	// Insert through the core so each value does not pay for a final
+	// snapshot of the whole tree
+	for v := 1; v < n; v++ {
+		tree.clearSteps()
+		if !tree.insertIterative(v) {
+			t.Fatalf("insert %d canceled", v)
+		}
+	}
+	if result := tree.InsertIterative(n); !result.Success || len(result.FinalTree) != n {
+		t.Fatalf("insert %d: success %v with %d nodes", n, result.Success, len(result.FinalTree))
+	}
+
+	// A perfectly balanced tree of 100k nodes has height 17
+	if h := checkAVL(t, tree.Root); h != 17 {
+		t.Errorf("height %d, want 17", h)
+	}
+	values := tree.Values()
+	if len(values) != n || values[0] != 1 || values[n-1] != n {
+		t.Fatalf("%d values from %d to %d", len(values), values[0], values[len(values)-1])
+	}
+	for i := 1; i < n; i++ {
+		if values[i] <= values[i-1] {
+			t.Fatalf("values out of order at %d: %d after %d", i, values[i], values[i-1])
+		}
+	}
+}
+
+func TestAVLInsertLeftRightCaseRotatesTwice(t *testing.T) {
+	tree := NewAVLTree()
+	tree.Insert(30)
+	tree.Insert(10)
+	result := tree.Insert(20)
+	if result.RotationCount != 2 {
+		t.Errorf("rotation count %d, want 2", result.RotationCount)
+	}
+	if tree.Root.Value != 20 {
+		t.Errorf("root %d, want 20", tree.Root.Value)
+	}
+}
+
+func TestAVLBalanceInfoOnFreshTree(t *testing.T) {
+	tree := NewAVLTree()
+	tree.SetIncludeSnapshots(false)
+	for i := 1; i <= 64; i++ {
+		tree.Insert(i)
+	}
+	for i := 0; i < 50; i++ {
+		tree.Insert(100 + (i*37)%50)
+	}
+	for i := 2; i <= 64; i += 3 {
+		tree.Delete(i)
+	}
+
+	result := tree.BalanceInfo()
+	if len(result.FinalTree) != len(tree.Values()) {
+		t.Fatalf("reported %d nodes, tree holds %d", len(result.FinalTree), len(tree.Values()))
+	}
+	for _, n := range result.FinalTree {
+		if n.BalanceFactor == nil {
+			t.Fatalf("node %d has no balance factor", n.Value)
+		}
+		if bf := *n.BalanceFactor; bf < -1 || bf > 1 {
+			t.Errorf("node %d balance factor %d", n.Value, bf)
+		}
+		if n.Violation {
+			t.Errorf("node %d flagged on a freshly built tree", n.Value)
+		}
+	}
+
+	tree.Root.Height = 99
+	result = tree.BalanceInfo()
+	for _, n := range result.FinalTree {
+		if n.ID == tree.Root.ID && !n.Violation {
+			t.Error("stale root height not flagged")
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package datastructures
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// BuildBalanced replaces the AVL Tree with a height-balanced BST built from
+// values by recursively choosing the median of each sorted range as the
+// subtree root. Node IDs are assigned in construction order. Duplicate
+// values are rejected and leave the tree untouched.
+func (t *AVLTree) BuildBalanced(values []int) OperationResult {
+	t.clearSteps()
+
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return OperationResult{
+				Success: false,
+				Message: t.msg("avl.build.duplicate", sorted[i]),
+				Code:    CodeInvalidParam,
+				Steps:   []Step{},
+			}
+		}
+	}
+
+	t.Root = nil
+	t.nextID = 0
+	t.valueIDs = make(map[int]int)
+	t.addStep(StepVisit, t.msg("avl.build.start", sorted), nil)
+
+	var build func(lo, hi int, attach func(*AVLNode))
+	build = func(lo, hi int, attach func(*AVLNode)) {
+		if lo > hi {
+			return
+		}
+		mid := lo + (hi-lo)/2
+		node := &AVLNode{
+			ID:     t.idFor(sorted[mid]),
+			Value:  sorted[mid],
+			Height: bits.Len(uint(hi - lo + 1)),
+		}
+		attach(node)
+		t.addStep(StepInsert, t.msg("avl.build.root", sorted[mid], sorted[lo:hi+1]), &node.ID, []int{node.ID})
+
+		build(lo, mid-1, func(child *AVLNode) { node.Left = child })
+		build(mid+1, hi, func(child *AVLNode) { node.Right = child })
+	}
+	build(0, len(sorted)-1, func(root *AVLNode) { t.Root = root })
+
+	t.addStep(StepComplete, t.msg("avl.build.done"), nil)
+	return t.newResult(true, t.msg("avl.build.success", len(sorted), t.Height()))
+}
+
+// Rebalance rebuilds the Red-Black Tree from its current contents into a
+// tree of minimal height, choosing the median of each in-order range as the
+// subtree root. The existing nodes are relinked, so every value keeps its
+// node ID. All nodes are black except those on the bottom level of a tree
+// whose bottom level is not full, which are red so every path carries the
+// same number of black nodes.
+func (t *RedBlackTree) Rebalance() OperationResult {
+	t.clearSteps()
+
+	nodes := make([]*RBNode, 0)
+	stack := make([]*RBNode, 0)
+	for current := t.Root; current != t.NIL || len(stack) > 0; {
+		for current != t.NIL {
+			stack = append(stack, current)
+			current = current.Left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes = append(nodes, current)
+		current = current.Right
+	}
+
+	before := t.Height()
+	height := bits.Len(uint(len(nodes)))
+	perfect := len(nodes) == 1<<height-1
+	t.Root = t.NIL
+	t.addStep(StepVisit, t.msg("rb.rebalance.start", len(nodes), before), nil)
+
+	var build func(lo, hi, depth int, parent *RBNode, attach func(*RBNode))
+	build = func(lo, hi, depth int, parent *RBNode, attach func(*RBNode)) {
+		if lo > hi {
+			return
+		}
+		mid := lo + (hi-lo)/2
+		node := nodes[mid]
+		node.Left, node.Right, node.Parent = t.NIL, t.NIL, parent
+		node.Color = Black
+		if !perfect && depth == height-1 {
+			node.Color = Red
+		}
+		attach(node)
+		t.addStep(StepInsert, t.msg("rb.rebalance.root", node.Value, node.Color, depth), &node.ID, []int{node.ID})
+
+		build(lo, mid-1, depth+1, node, func(child *RBNode) { node.Left = child })
+		build(mid+1, hi, depth+1, node, func(child *RBNode) { node.Right = child })
+	}
+	build(0, len(nodes)-1, 0, t.NIL, func(root *RBNode) { t.Root = root })
+
+	after := t.Height()
+	t.addStep(StepComplete, t.msg("rb.rebalance.done", before, after), nil)
+	return t.newResult(true, t.msg("rb.rebalance.success", len(nodes), before, after))
+}
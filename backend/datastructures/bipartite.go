@@ -0,0 +1,149 @@
+package datastructures
+
+import "sort"
+
+// undirectedNeighbors returns the sorted neighbors of every node, ignoring
+// edge direction
+func (g *Graph) undirectedNeighbors() map[string][]string {
+	seen := make(map[string]map[string]bool, len(g.Nodes))
+	link := func(a, b string) {
+		if seen[a] == nil {
+			seen[a] = make(map[string]bool)
+		}
+		seen[a][b] = true
+	}
+	for from, edges := range g.Nodes {
+		for _, e := range edges {
+			link(from, e.To)
+			link(e.To, from)
+		}
+	}
+
+	neighbors := make(map[string][]string, len(g.Nodes))
+	for id, set := range seen {
+		for other := range set {
+			neighbors[id] = append(neighbors[id], other)
+		}
+		sort.Strings(neighbors[id])
+	}
+	return neighbors
+}
+
+// annotateColorClasses sets the color class of every node of the latest
+// step that has already been colored
+func (g *Graph) annotateColorClasses(color map[string]int) {
+	last := g.latest
+	for i := range last.Nodes {
+		if c, ok := color[last.Nodes[i].ID]; ok {
+			last.Nodes[i].ColorClass = &c
+		}
+	}
+}
+
+// oddCycle closes the BFS tree paths of u and v, two adjacent nodes of the
+// same color, into the odd cycle u → … → ancestor → … → v → u
+func oddCycle(parent map[string]string, u, v string) []string {
+	depthOf := make(map[string]int)
+	for at, d := u, 0; ; at, d = parent[at], d+1 {
+		depthOf[at] = d
+		if _, ok := parent[at]; !ok {
+			break
+		}
+	}
+
+	tail := make([]string, 0)
+	at := v
+	for {
+		if _, ok := depthOf[at]; ok {
+			break
+		}
+		tail = append(tail, at)
+		at = parent[at]
+	}
+
+	cycle := make([]string, 0)
+	for node := u; node != at; node = parent[node] {
+		cycle = append(cycle, node)
+	}
+	cycle = append(cycle, at)
+	for i := len(tail) - 1; i >= 0; i-- {
+		cycle = append(cycle, tail[i])
+	}
+	return append(cycle, u)
+}
+
+// IsBipartite attempts a 2-coloring of the graph by BFS, ignoring edge
+// direction. On success the two color classes are returned in Partition; on
+// failure the conflicting edge is selected and the odd cycle through it is
+// highlighted as the proof.
+func (g *Graph) IsBipartite() OperationResult {
+	g.clearSteps()
+
+	ids := g.sortedIDs()
+
+	neighbors := g.undirectedNeighbors()
+	color := make(map[string]int, len(ids))
+	parent := make(map[string]string, len(ids))
+	visited := make(map[string]bool, len(ids))
+
+	record := func(stepType StepType, desc string, path []string, edge *[2]string) {
+		g.addStep(stepType, desc, nil, visited, path, edge)
+		g.annotateColorClasses(color)
+	}
+
+	for _, root := range ids {
+		if _, colored := color[root]; colored {
+			continue
+		}
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		color[root] = 0
+		visited[root] = true
+		record(StepVisit, g.msg("graph.bipartite.root", root), nil, nil)
+
+		queue := []string{root}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range neighbors[current] {
+				edge := &[2]string{current, next}
+				if _, colored := color[next]; !colored {
+					color[next] = 1 - color[current]
+					parent[next] = current
+					visited[next] = true
+					record(StepVisit, g.msg("graph.bipartite.color", next, color[next], current), nil, edge)
+					queue = append(queue, next)
+					continue
+				}
+				if color[next] != color[current] {
+					continue
+				}
+
+				cycle := oddCycle(parent, current, next)
+				record(StepCompare, g.msg("graph.bipartite.conflict", current, next, color[current], cycle), cycle, edge)
+				return OperationResult{
+					Success:    false,
+					Message:    g.msg("graph.bipartite.failure", len(cycle)-1),
+					NoOp:       true,
+					Steps:      g.steps,
+					FinalGraph: g.latest,
+				}
+			}
+		}
+	}
+
+	partition := [][]string{{}, {}}
+	for _, id := range ids {
+		partition[color[id]] = append(partition[color[id]], id)
+	}
+	record(StepComplete, g.msg("graph.bipartite.done", partition[0], partition[1]), nil, nil)
+
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.bipartite.success", len(partition[0]), len(partition[1])),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+		Partition:  partition,
+	}
+}
@@ -0,0 +1,56 @@
+package datastructures
+
+import (
+	"slices"
+	"testing"
+)
+
+// cycleGraph builds the undirected cycle through ids in order
+func cycleGraph(t *testing.T, ids ...string) *Graph {
+	t.Helper()
+	edges := make([]GraphEdgeInput, len(ids))
+	for i, id := range ids {
+		edges[i] = GraphEdgeInput{From: id, To: ids[(i+1)%len(ids)], Weight: 1}
+	}
+	return buildGraph(t, false, edges...)
+}
+
+func TestBipartiteEvenCycle(t *testing.T) {
+	result := cycleGraph(t, "A", "B", "C", "D", "E", "F").IsBipartite()
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+	want := [][]string{{"A", "C", "E"}, {"B", "D", "F"}}
+	if !slices.EqualFunc(result.Partition, want, slices.Equal) {
+		t.Errorf("partition %v, want %v", result.Partition, want)
+	}
+}
+
+func TestBipartiteOddCycle(t *testing.T) {
+	ids := []string{"A", "B", "C", "D", "E"}
+	result := cycleGraph(t, ids...).IsBipartite()
+	if result.Success || result.Partition != nil {
+		t.Fatalf("success %v partition %v, want a failure", result.Success, result.Partition)
+	}
+
+	// The last step highlights the whole odd cycle and its conflicting edge
+	last := result.Steps[len(result.Steps)-1]
+	var inPath []string
+	for _, node := range last.GraphNodes {
+		if node.InPath {
+			inPath = append(inPath, node.ID)
+		}
+	}
+	if !slices.Equal(inPath, ids) {
+		t.Errorf("highlighted %v, want the cycle %v", inPath, ids)
+	}
+	selected := 0
+	for _, e := range last.GraphEdges {
+		if e.Selected {
+			selected++
+		}
+	}
+	if selected == 0 {
+		t.Error("the conflicting edge is not selected")
+	}
+}
@@ -0,0 +1,137 @@
+package datastructures
+
+// OrderViolation is the first pair of nodes found out of binary search
+// order. Nodes adjacent in an in-order walk are always ancestor and
+// descendant, so the pair is the ancestor Parent and the node Child in its
+// Side subtree whose value does not belong there.
+type OrderViolation struct {
+	ParentID    int    `json:"parentId"`
+	ParentValue int    `json:"parentValue"`
+	ChildID     int    `json:"childId"`
+	ChildValue  int    `json:"childValue"`
+	Side        string `json:"side"`
+}
+
+// orderEntry is one node of an in-order walk. hasRight tells whether the
+// next entry comes from the node's right subtree or from an ancestor.
+type orderEntry struct {
+	id, value int
+	hasRight  bool
+}
+
+// findOrderViolation checks that entries, an in-order walk, increase
+// monotonically, calling compare for every adjacent pair it checks. Equal
+// neighbors are allowed unless strict is set.
+func findOrderViolation(entries []orderEntry, strict bool, compare func(prev, cur orderEntry)) *OrderViolation {
+	for i := 1; i < len(entries); i++ {
+		prev, cur := entries[i-1], entries[i]
+		compare(prev, cur)
+		if prev.value < cur.value || (prev.value == cur.value && !strict) {
+			continue
+		}
+		if prev.hasRight {
+			return &OrderViolation{ParentID: prev.id, ParentValue: prev.value, ChildID: cur.id, ChildValue: cur.value, Side: "right"}
+		}
+		return &OrderViolation{ParentID: cur.id, ParentValue: cur.value, ChildID: prev.id, ChildValue: prev.value, Side: "left"}
+	}
+	return nil
+}
+
+// CheckBST verifies only the binary search order of the AVL Tree, ignoring
+// heights and balance, by walking it in order. The first out-of-order pair
+// is highlighted and returned in OrderViolation.
+func (t *AVLTree) CheckBST() OperationResult {
+	t.clearSteps()
+
+	entries := make([]orderEntry, 0)
+	stack := make([]*AVLNode, 0)
+	current := t.Root
+	for current != nil || len(stack) > 0 {
+		for current != nil {
+			stack = append(stack, current)
+			current = current.Left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		entries = append(entries, orderEntry{id: current.ID, value: current.Value, hasRight: current.Right != nil})
+		current = current.Right
+	}
+
+	violation := findOrderViolation(entries, true, func(prev, cur orderEntry) {
+		t.addStep(StepCompare, t.msg("tree.check_bst.compare", prev.value, cur.value), &cur.id, []int{prev.id, cur.id})
+	})
+	return t.orderResult(len(entries), violation)
+}
+
+func (t *AVLTree) orderResult(size int, violation *OrderViolation) OperationResult {
+	if violation == nil {
+		message := t.msg("tree.check_bst.ok", size)
+		t.addStep(StepComplete, message, nil)
+		return t.newResult(true, message)
+	}
+	message := orderViolationMessage(t.locale, violation)
+	t.addStep(StepComplete, message, &violation.ChildID, []int{violation.ParentID, violation.ChildID})
+	result := t.newResult(true, message)
+	result.FinalTree = markOrderViolation(result.FinalTree, violation)
+	result.OrderViolation = violation
+	return result
+}
+
+// CheckBST verifies only the binary search order of the Red-Black Tree,
+// ignoring colors, by walking it in order. Duplicates are allowed on either
+// side of an equal value, since rotations can move a later copy above an
+// earlier one. The first out-of-order pair is highlighted and returned in
+// OrderViolation.
+func (t *RedBlackTree) CheckBST() OperationResult {
+	t.clearSteps()
+
+	entries := make([]orderEntry, 0)
+	stack := make([]*RBNode, 0)
+	current := t.Root
+	for current != t.NIL || len(stack) > 0 {
+		for current != t.NIL {
+			stack = append(stack, current)
+			current = current.Left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		entries = append(entries, orderEntry{id: current.ID, value: current.Value, hasRight: current.Right != t.NIL})
+		current = current.Right
+	}
+
+	violation := findOrderViolation(entries, false, func(prev, cur orderEntry) {
+		t.addStep(StepCompare, t.msg("tree.check_bst.compare", prev.value, cur.value), &cur.id, []int{prev.id, cur.id})
+	})
+	return t.orderResult(len(entries), violation)
+}
+
+func (t *RedBlackTree) orderResult(size int, violation *OrderViolation) OperationResult {
+	if violation == nil {
+		message := t.msg("tree.check_bst.ok", size)
+		t.addStep(StepComplete, message, nil)
+		return t.newResult(true, message)
+	}
+	message := orderViolationMessage(t.locale, violation)
+	t.addStep(StepComplete, message, &violation.ChildID, []int{violation.ParentID, violation.ChildID})
+	result := t.newResult(true, message)
+	result.FinalTree = markOrderViolation(result.FinalTree, violation)
+	result.OrderViolation = violation
+	return result
+}
+
+func orderViolationMessage(locale Locale, v *OrderViolation) string {
+	if v.Side == "left" {
+		return Localize(locale, "tree.check_bst.left", v.ChildValue, v.ParentValue)
+	}
+	return Localize(locale, "tree.check_bst.right", v.ChildValue, v.ParentValue)
+}
+
+// markOrderViolation flags the two nodes of v in snapshot
+func markOrderViolation(snapshot []TreeNodeSnapshot, v *OrderViolation) []TreeNodeSnapshot {
+	for i := range snapshot {
+		if snapshot[i].ID == v.ParentID || snapshot[i].ID == v.ChildID {
+			snapshot[i].Violation = true
+		}
+	}
+	return snapshot
+}
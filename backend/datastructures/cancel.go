@@ -0,0 +1,75 @@
+package datastructures
+
+import "context"
+
+// contextDone reports whether ctx has been canceled or has timed out. A nil
+// context is never done.
+func contextDone(ctx context.Context) bool {
+	return ctx != nil && ctx.Err() != nil
+}
+
+// SetContext attaches a context whose cancellation aborts long-running
+// operations between steps
+func (g *Graph) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// canceledResult is returned by graph algorithms aborted through their
+// context. It keeps the steps recorded before the cancellation. Graph
+// algorithms never mutate the graph, so nothing is rolled back.
+func (g *Graph) canceledResult() OperationResult {
+	return OperationResult{
+		Success:    false,
+		Message:    g.msg("op.canceled"),
+		Reason:     ReasonCanceled,
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+	}
+}
+
+// SetContext attaches a context whose cancellation aborts long-running
+// operations between steps
+func (t *RedBlackTree) SetContext(ctx context.Context) {
+	t.ctx = ctx
+}
+
+// canceledResult is returned by tree operations aborted through their
+// context. Work finished before the cancellation is kept, so the steps
+// recorded so far and the final tree reflect the partially applied
+// operation.
+func (t *RedBlackTree) canceledResult() OperationResult {
+	result := t.newResult(false, t.msg("op.canceled"))
+	result.Reason = ReasonCanceled
+	return result
+}
+
+// SetContext attaches a context whose cancellation aborts long-running
+// operations between steps
+func (t *AVLTree) SetContext(ctx context.Context) {
+	t.ctx = ctx
+}
+
+// canceledResult is returned by AVL operations aborted through their
+// context. Inserts and deletes stop while still searching and traversals
+// undo their threads, so the final tree is the one the operation started
+// from; the steps recorded so far are kept.
+func (t *AVLTree) canceledResult() OperationResult {
+	result := t.newResult(false, t.msg("op.canceled"))
+	result.Reason = ReasonCanceled
+	return result
+}
+
+// SetContext attaches a context whose cancellation aborts long-running
+// operations between steps
+func (h *BinaryHeap) SetContext(ctx context.Context) {
+	h.ctx = ctx
+}
+
+// canceledResult is returned by heap operations aborted through their
+// context. Only heap sort checks for cancellation, and it works on a
+// scratch copy, so the final tree is the heap it started from.
+func (h *BinaryHeap) canceledResult() OperationResult {
+	result := h.newResult(false, h.msg("op.canceled"))
+	result.Reason = ReasonCanceled
+	return result
+}
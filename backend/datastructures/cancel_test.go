@@ -0,0 +1,100 @@
+package datastructures
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// countdownContext reports cancellation once Err has been called n times,
+// so an operation is canceled partway through
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func cancelAfter(n int) context.Context {
+	return &countdownContext{Context: context.Background(), n: n}
+}
+
+func balancedAVL(n int) *AVLTree {
+	tree := NewAVLTree()
+	for v := 1; v <= n; v++ {
+		tree.Insert(v * 10)
+	}
+	return tree
+}
+
+func TestAVLCancelStopsSteps(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(tree *AVLTree) OperationResult
+	}{
+		{"insert", func(tree *AVLTree) OperationResult { return tree.Insert(55) }},
+		{"insert_iterative", func(tree *AVLTree) OperationResult { return tree.InsertIterative(55) }},
+		{"delete", func(tree *AVLTree) OperationResult { return tree.Delete(10) }},
+		{"level_order", func(tree *AVLTree) OperationResult { return tree.LevelOrder() }},
+		{"morris_inorder", func(tree *AVLTree) OperationResult { return tree.MorrisInorder() }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := balancedAVL(31)
+			before := tree.Values()
+			tree.SetIncludeSnapshots(false)
+
+			full := tc.run(balancedAVL(31))
+			tree.SetContext(cancelAfter(2))
+			result := tc.run(tree)
+
+			if result.Success || result.Reason != ReasonCanceled {
+				t.Fatalf("success=%v reason=%q, want canceled", result.Success, result.Reason)
+			}
+			if len(result.Steps) >= len(full.Steps) {
+				t.Errorf("canceled run recorded %d steps, the full run %d", len(result.Steps), len(full.Steps))
+			}
+			tree.SetContext(nil)
+			if got := tree.Values(); !slices.Equal(got, before) {
+				t.Errorf("tree changed to %v", got)
+			}
+			if violation := tree.CheckBST(); !violation.Success {
+				t.Errorf("tree left invalid: %s", violation.Message)
+			}
+		})
+	}
+}
+
+func TestRedBlackBulkDeleteCancel(t *testing.T) {
+	tree := NewRedBlackTree()
+	for v := 1; v <= 10; v++ {
+		tree.Insert(v)
+	}
+	tree.SetContext(cancelAfter(3))
+	result := tree.DeleteMany([]int{1, 2, 3, 4, 5, 6})
+	if result.Reason != ReasonCanceled {
+		t.Fatalf("reason %q, want canceled", result.Reason)
+	}
+	if got := tree.Values(); !slices.Equal(got, []int{4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("values %v, want the first three deleted", got)
+	}
+}
+
+func TestGraphCancelStopsSteps(t *testing.T) {
+	g := CreateSampleGraph()
+	full := g.Dijkstra("A", "E")
+	g.SetContext(cancelAfter(1))
+	result := g.Dijkstra("A", "E")
+	if result.Reason != ReasonCanceled {
+		t.Fatalf("reason %q, want canceled", result.Reason)
+	}
+	if len(result.Steps) >= len(full.Steps) {
+		t.Errorf("canceled run recorded %d steps, the full run %d", len(result.Steps), len(full.Steps))
+	}
+}
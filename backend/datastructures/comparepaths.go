@@ -0,0 +1,151 @@
+package datastructures
+
+import "math"
+
+// PathComparison is the outcome of one algorithm run by compare_paths, with
+// its own step log
+type PathComparison struct {
+	Algorithm string   `json:"algorithm"`
+	Success   bool     `json:"success"`
+	Path      []string `json:"path,omitempty"`
+	// Cost is the weighted cost of Path and Hops its number of edges
+	Cost       float64     `json:"cost"`
+	Hops       int         `json:"hops"`
+	Steps      []Step      `json:"steps"`
+	FinalGraph *GraphState `json:"finalGraph,omitempty"`
+}
+
+// BFSPath finds the path from start to end with the fewest edges, treating
+// every edge as weight 1. Distances in the steps are hop counts.
+func (g *Graph) BFSPath(start, end string) OperationResult {
+	g.clearSteps()
+
+	distances := make(map[string]float64)
+	previous := make(map[string]string)
+	visited := make(map[string]bool)
+	for node := range g.Nodes {
+		distances[node] = math.Inf(1)
+	}
+	distances[start] = 0
+	visited[start] = true
+	g.addStep(StepVisit, g.msg("graph.bfs.init", start), distances, visited, nil, nil)
+
+	queue := []string{start}
+	for len(queue) > 0 {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		current := queue[0]
+		queue = queue[1:]
+		g.addStep(StepSelectNode, g.msg("graph.bfs.dequeue", current, distances[current]), distances, visited, nil, nil)
+
+		if current == end {
+			path := make([]string, 0)
+			for at := end; at != ""; at = previous[at] {
+				path = append([]string{at}, path...)
+			}
+			g.addStep(StepComplete, g.msg("graph.bfs.found", path, distances[end]), distances, visited, path, nil)
+			return OperationResult{
+				Success:    true,
+				Message:    g.msg("graph.bfs.success", distances[end]),
+				Steps:      g.steps,
+				FinalGraph: g.latest,
+				Paths:      []PathResult{{Nodes: path, Cost: g.pathCost(path)}},
+			}
+		}
+
+		for _, edge := range g.Nodes[current] {
+			if visited[edge.To] {
+				continue
+			}
+			visited[edge.To] = true
+			distances[edge.To] = distances[current] + 1
+			previous[edge.To] = current
+			queue = append(queue, edge.To)
+			g.addStep(StepUpdateDist, g.msg("graph.bfs.discover", edge.To, distances[edge.To], current), distances, visited, nil, &[2]string{current, edge.To})
+		}
+	}
+
+	g.addStep(StepNotFound, g.msg("graph.unreachable_step", start, end), distances, visited, nil, nil)
+	return OperationResult{
+		Success: false,
+		Message: g.msg("graph.unreachable"),
+		Reason:  ReasonUnreachable,
+		NoOp:    true,
+		Steps:   g.steps,
+	}
+}
+
+// ComparePaths runs Dijkstra and BFS from start to end and reports both
+// routes side by side, showing where edge weights change the best path.
+// Each run keeps its own steps in Comparison; Paths lists the two routes.
+func (g *Graph) ComparePaths(start, end string) OperationResult {
+	for _, id := range []string{start, end} {
+		if !g.HasNode(id) {
+			g.clearSteps()
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+
+	runs := []struct {
+		algorithm string
+		run       func(start, end string) OperationResult
+	}{
+		{"dijkstra", g.Dijkstra},
+		{"bfs", g.BFSPath},
+	}
+	comparison := make([]PathComparison, 0, len(runs))
+	for _, r := range runs {
+		result := r.run(start, end)
+		if result.Reason == ReasonCanceled {
+			// The runs finished before the cancellation are kept
+			result.Comparison = comparison
+			return result
+		}
+		entry := PathComparison{
+			Algorithm:  r.algorithm,
+			Success:    result.Success,
+			Steps:      result.Steps,
+			FinalGraph: result.FinalGraph,
+		}
+		if result.Success {
+			entry.Path = result.Paths[0].Nodes
+			entry.Cost = g.pathCost(entry.Path)
+			entry.Hops = len(entry.Path) - 1
+		}
+		comparison = append(comparison, entry)
+	}
+
+	dijkstra, bfs := comparison[0], comparison[1]
+	if !dijkstra.Success {
+		return OperationResult{
+			Success:    false,
+			Message:    g.msg("graph.unreachable"),
+			Reason:     ReasonUnreachable,
+			NoOp:       true,
+			Steps:      []Step{},
+			Comparison: comparison,
+		}
+	}
+
+	message := g.msg("graph.compare_paths.same", dijkstra.Path, dijkstra.Cost)
+	if !samePath(dijkstra.Path, bfs.Path) {
+		message = g.msg("graph.compare_paths.differ", dijkstra.Path, dijkstra.Cost, dijkstra.Hops, bfs.Path, bfs.Cost, bfs.Hops)
+	}
+	return OperationResult{
+		Success:    true,
+		Message:    message,
+		Steps:      []Step{},
+		FinalGraph: dijkstra.FinalGraph,
+		Paths: []PathResult{
+			{Nodes: dijkstra.Path, Cost: dijkstra.Cost},
+			{Nodes: bfs.Path, Cost: bfs.Cost},
+		},
+		Comparison: comparison,
+	}
+}
@@ -0,0 +1,47 @@
+package datastructures
+
+import "fmt"
+
+// StepReferenceError reports a step that references a node missing from
+// its own tree snapshot
+type StepReferenceError struct {
+	Index  int
+	Field  string
+	NodeID int
+}
+
+func (e StepReferenceError) Error() string {
+	return fmt.Sprintf("step %d: %s references node %d, which is not in its tree state", e.Index, e.Field, e.NodeID)
+}
+
+// CheckStepReferences verifies that every NodeID, TargetID and Highlight ID
+// of a tree step exists in that step's TreeState, returning one error per
+// dangling reference. Steps without a tree snapshot are skipped.
+func CheckStepReferences(steps []Step) []error {
+	var errs []error
+	for _, step := range steps {
+		if len(step.TreeState) == 0 {
+			continue
+		}
+		present := make(map[int]bool, len(step.TreeState))
+		for _, node := range step.TreeState {
+			present[node.ID] = true
+		}
+		check := func(field string, id int) {
+			if !present[id] {
+				errs = append(errs, StepReferenceError{Index: step.Index, Field: field, NodeID: id})
+			}
+		}
+
+		if step.NodeID != nil {
+			check("nodeId", *step.NodeID)
+		}
+		if step.TargetID != nil {
+			check("targetId", *step.TargetID)
+		}
+		for _, id := range step.Highlight {
+			check("highlight", id)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,52 @@
+package datastructures
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestStepsReferenceNodesOfTheirSnapshot(t *testing.T) {
+	trees := map[string]interface {
+		Insert(value int) OperationResult
+		Delete(value int) OperationResult
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	rng := rand.New(rand.NewSource(5))
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			values := rng.Perm(80)
+			var results []OperationResult
+			for _, v := range values {
+				results = append(results, tree.Insert(v))
+			}
+			rng.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+			for _, v := range values {
+				results = append(results, tree.Delete(v))
+			}
+			for _, result := range results {
+				for _, err := range CheckStepReferences(result.Steps) {
+					t.Errorf("%s: %v", result.Message, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckStepReferencesReportsDanglingIDs(t *testing.T) {
+	missing := 9
+	steps := []Step{
+		{Index: 0, NodeID: &missing},
+		{Index: 1, TreeState: []TreeNodeSnapshot{{ID: 1}}, NodeID: &missing, Highlight: []int{1, missing}},
+	}
+	errs := CheckStepReferences(steps)
+	if len(errs) != 2 {
+		t.Fatalf("%d errors, want 2: %v", len(errs), errs)
+	}
+	var refErr StepReferenceError
+	if !errors.As(errs[1], &refErr) || refErr != (StepReferenceError{Index: 1, Field: "highlight", NodeID: missing}) {
+		t.Errorf("second error %v", errs[1])
+	}
+}
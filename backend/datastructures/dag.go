@@ -0,0 +1,121 @@
+package datastructures
+
+import "sort"
+
+// topologicalOrder returns the nodes of a directed graph in topological
+// order using Kahn's algorithm, or false if the graph has a cycle. Ties are
+// broken by node ID so the order is deterministic.
+func (g *Graph) topologicalOrder() ([]string, bool) {
+	inDegree := g.inDegrees()
+
+	ready := make([]string, 0)
+	for id, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, id)
+		}
+	}
+	sort.Strings(ready)
+
+	order := make([]string, 0, len(g.Nodes))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+
+		released := make([]string, 0)
+		for _, e := range g.Nodes[current] {
+			inDegree[e.To]--
+			if inDegree[e.To] == 0 {
+				released = append(released, e.To)
+			}
+		}
+		ready = append(ready, released...)
+		sort.Strings(ready)
+	}
+
+	return order, len(order) == len(g.Nodes)
+}
+
+// LongestPath finds the critical (maximum-weight) path of a directed acyclic
+// graph by dynamic programming over a topological order. Every node starts
+// with a longest distance of 0, so the path may begin anywhere.
+func (g *Graph) LongestPath() OperationResult {
+	g.clearSteps()
+
+	if !g.Directed {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.longest.undirected"),
+			Code:    CodeInvalidState,
+			Steps:   []Step{},
+		}
+	}
+	order, acyclic := g.topologicalOrder()
+	if !acyclic {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.longest.cyclic"),
+			Code:    CodeInvalidState,
+			Steps:   []Step{},
+		}
+	}
+	if len(order) == 0 {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.longest.empty"),
+			Code:    CodeInvalidState,
+			Steps:   []Step{},
+		}
+	}
+
+	distances := make(map[string]float64, len(order))
+	previous := make(map[string]string)
+	visited := make(map[string]bool, len(order))
+	for _, id := range order {
+		distances[id] = 0
+	}
+
+	g.addStep(StepVisit, g.msg("graph.longest.order", order), distances, visited, nil, nil)
+
+	for _, current := range order {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		visited[current] = true
+		g.addStep(StepSelectNode, g.msg("graph.longest.select", current, distances[current]), distances, visited, nil, nil)
+
+		for _, edge := range g.Nodes[current] {
+			candidate := distances[current] + edge.Weight
+			edgePtr := &[2]string{current, edge.To}
+			if candidate > distances[edge.To] {
+				oldDist := distances[edge.To]
+				distances[edge.To] = candidate
+				previous[edge.To] = current
+				g.addStep(StepUpdateDist, g.msg("graph.longest.update", edge.To, oldDist, candidate, current), distances, visited, nil, edgePtr)
+			} else {
+				g.addStep(StepCompare, g.msg("graph.longest.no_update", current, edge.To, candidate, distances[edge.To]), distances, visited, nil, edgePtr)
+			}
+		}
+	}
+
+	end := order[0]
+	for _, id := range order {
+		if distances[id] > distances[end] {
+			end = id
+		}
+	}
+	path := make([]string, 0)
+	for at := end; at != ""; at = previous[at] {
+		path = append([]string{at}, path...)
+	}
+
+	g.addStep(StepComplete, g.msg("graph.longest.found", path, distances[end]), distances, visited, path, nil)
+
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.longest.success", distances[end]),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+		Paths:      []PathResult{{Nodes: path, Cost: distances[end]}},
+	}
+}
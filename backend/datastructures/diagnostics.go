@@ -0,0 +1,177 @@
+package datastructures
+
+// BalanceInfo reports the measured height and balance factor of every AVL
+// node in the final snapshot, flagging nodes that break the AVL invariant
+// (|balance factor| > 1) or whose stored height is stale
+func (t *AVLTree) BalanceInfo() OperationResult {
+	t.clearSteps()
+
+	type info struct {
+		height, balance int
+		violation       bool
+	}
+	infos := make(map[int]info)
+	var measure func(node *AVLNode) int
+	measure = func(node *AVLNode) int {
+		if node == nil {
+			return 0
+		}
+		left, right := measure(node.Left), measure(node.Right)
+		h := 1 + max(left, right)
+		balance := left - right
+		infos[node.ID] = info{
+			height:    h,
+			balance:   balance,
+			violation: balance > 1 || balance < -1 || node.Height != h,
+		}
+		return h
+	}
+	measure(t.Root)
+
+	violations := make([]int, 0)
+	snapshot := t.getTreeSnapshot()
+	for i := range snapshot {
+		n := &snapshot[i]
+		in := infos[n.ID]
+		balance := in.balance
+		n.BalanceFactor = &balance
+		n.Height = in.height
+		n.Violation = in.violation
+		if in.violation {
+			violations = append(violations, n.ID)
+		}
+	}
+
+	message := t.msg("avl.balance.ok", len(snapshot))
+	if len(violations) > 0 {
+		message = t.msg("avl.balance.violations", len(violations))
+	}
+	t.addStep(StepComplete, message, nil, violations)
+
+	result := t.newResult(true, message)
+	result.FinalTree = snapshot
+	return result
+}
+
+// BalanceInfo reports the black-height of every Red-Black node in the final
+// snapshot, i.e. the number of black nodes on each path from the node down
+// to a leaf, not counting the node itself. Nodes whose subtrees disagree on
+// black-height, red nodes with a red child and a red root are flagged.
+func (t *RedBlackTree) BalanceInfo() OperationResult {
+	t.clearSteps()
+
+	type info struct {
+		blackHeight int
+		violation   bool
+	}
+	infos := make(map[int]info)
+	var measure func(node *RBNode) int
+	measure = func(node *RBNode) int {
+		if node == t.NIL {
+			return 0
+		}
+		left, right := measure(node.Left), measure(node.Right)
+		violation := left != right
+		if node.Color == Red && (node.Left.Color == Red || node.Right.Color == Red) {
+			violation = true
+		}
+		infos[node.ID] = info{blackHeight: left, violation: violation}
+
+		if node.Color == Black {
+			return max(left, right) + 1
+		}
+		return max(left, right)
+	}
+	measure(t.Root)
+	if t.Root != t.NIL && t.Root.Color == Red {
+		in := infos[t.Root.ID]
+		in.violation = true
+		infos[t.Root.ID] = in
+	}
+
+	violations := make([]int, 0)
+	snapshot := t.getTreeSnapshot()
+	for i := range snapshot {
+		n := &snapshot[i]
+		in := infos[n.ID]
+		blackHeight := in.blackHeight
+		n.BlackHeight = &blackHeight
+		n.Violation = in.violation
+		if in.violation {
+			violations = append(violations, n.ID)
+		}
+	}
+
+	rootBlackHeight := 0
+	if t.Root != t.NIL {
+		rootBlackHeight = infos[t.Root.ID].blackHeight
+	}
+	message := t.msg("rb.balance.ok", rootBlackHeight)
+	if len(violations) > 0 {
+		message = t.msg("rb.balance.violations", len(violations))
+	}
+	t.addStep(StepComplete, message, nil, violations)
+
+	result := t.newResult(true, message)
+	result.FinalTree = snapshot
+	return result
+}
+
+// BlackHeight reports the black-height of the Red-Black Tree, the number of
+// black nodes below the root on any path to a leaf, matching BalanceInfo.
+// When value is given, the black nodes on the path from the root to it are
+// counted (both ends included) and highlighted.
+func (t *RedBlackTree) BlackHeight(value *int) OperationResult {
+	t.clearSteps()
+	t.operand = value
+
+	height := 0
+	spine := make([]int, 0)
+	for node := t.Root; node != t.NIL; node = node.Left {
+		spine = append(spine, node.ID)
+		if node.Color == Black && node != t.Root {
+			height++
+		}
+	}
+	t.addStep(StepVisit, t.msg("rb.black_height.tree", height), nil, spine)
+
+	if value == nil {
+		t.addStep(StepComplete, t.msg("rb.black_height.success", height), nil)
+		result := t.newResult(true, t.msg("rb.black_height.success", height))
+		result.BlackHeight = &height
+		return result
+	}
+
+	path := make([]int, 0)
+	blacks := make([]int, 0)
+	for x := t.Root; x != t.NIL; {
+		path = append(path, x.ID)
+		if x.Color == Black {
+			blacks = append(blacks, x.ID)
+		}
+		t.addStep(StepCompare, t.msg("rb.black_height.compare", *value, x.Value, x.Color, len(blacks)), &x.ID, []int{x.ID})
+		if *value == x.Value {
+			count := len(blacks)
+			t.addStep(StepFound, t.msg("rb.black_height.path", *value, count), &x.ID, blacks)
+			result := t.newResult(true, t.msg("rb.black_height.path_success", height, *value, count))
+			result.BlackHeight = &height
+			result.BlackCount = &count
+			result.PathIDs = path
+			return result
+		} else if *value < x.Value {
+			x = x.Left
+		} else {
+			x = x.Right
+		}
+	}
+
+	t.addStep(StepNotFound, t.msg("tree.search.missing_step", *value), nil)
+	result := t.newResult(false, t.msg("tree.search.missing", *value))
+	result.Reason = ReasonNotFound
+	if t.Root == t.NIL {
+		result.Reason = ReasonEmptyTree
+	}
+	result.NoOp = true
+	result.BlackHeight = &height
+	return result
+}
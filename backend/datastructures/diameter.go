@@ -0,0 +1,96 @@
+package datastructures
+
+// throughPath joins the deepest chains below the left and right children of
+// a node into the longest path passing through it, ordered left to right
+func throughPath(left []int, id int, right []int) []int {
+	path := make([]int, 0, len(left)+1+len(right))
+	for i := len(left) - 1; i >= 0; i-- {
+		path = append(path, left[i])
+	}
+	path = append(path, id)
+	return append(path, right...)
+}
+
+// deeperChain prepends id to the longer of two chains, preferring left
+func deeperChain(id int, left, right []int) []int {
+	chain := left
+	if len(right) > len(left) {
+		chain = right
+	}
+	return append([]int{id}, chain...)
+}
+
+// Diameter finds the longest path between any two nodes of the AVL Tree in
+// a single post-order pass. At each node the deepest chains of both
+// children are combined; the longest combination seen is the diameter.
+func (t *AVLTree) Diameter() OperationResult {
+	t.clearSteps()
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	var best []int
+	var deepest func(node *AVLNode) []int
+	deepest = func(node *AVLNode) []int {
+		if node == nil {
+			return nil
+		}
+		left := deepest(node.Left)
+		right := deepest(node.Right)
+		t.addStep(StepVisit, t.msg("tree.diameter.visit", node.Value, len(left), len(right)), &node.ID, []int{node.ID})
+
+		if through := throughPath(left, node.ID, right); len(through) > len(best) {
+			best = through
+			t.addStep(StepCompare, t.msg("tree.diameter.best", node.Value, len(best)-1), &node.ID, best)
+		}
+		return deeperChain(node.ID, left, right)
+	}
+	deepest(t.Root)
+
+	t.addStep(StepFound, t.msg("tree.diameter.found", len(best)-1, len(best)), nil, best)
+	result := t.newResult(true, t.msg("tree.diameter.success", len(best)-1))
+	result.PathIDs = best
+	return result
+}
+
+// Diameter finds the longest path between any two nodes of the Red-Black
+// Tree in a single post-order pass, like the AVL version
+func (t *RedBlackTree) Diameter() OperationResult {
+	t.clearSteps()
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	var best []int
+	var deepest func(node *RBNode) []int
+	deepest = func(node *RBNode) []int {
+		if node == t.NIL {
+			return nil
+		}
+		left := deepest(node.Left)
+		right := deepest(node.Right)
+		t.addStep(StepVisit, t.msg("tree.diameter.visit", node.Value, len(left), len(right)), &node.ID, []int{node.ID})
+
+		if through := throughPath(left, node.ID, right); len(through) > len(best) {
+			best = through
+			t.addStep(StepCompare, t.msg("tree.diameter.best", node.Value, len(best)-1), &node.ID, best)
+		}
+		return deeperChain(node.ID, left, right)
+	}
+	deepest(t.Root)
+
+	t.addStep(StepFound, t.msg("tree.diameter.found", len(best)-1, len(best)), nil, best)
+	result := t.newResult(true, t.msg("tree.diameter.success", len(best)-1))
+	result.PathIDs = best
+	return result
+}
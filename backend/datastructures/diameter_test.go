@@ -0,0 +1,115 @@
+package datastructures
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// treeLinks maps every node ID to the IDs of its children
+type treeLinks map[int][]int
+
+func avlLinks(node *AVLNode, links treeLinks) {
+	if node == nil {
+		return
+	}
+	links[node.ID] = nil
+	for _, child := range []*AVLNode{node.Left, node.Right} {
+		if child != nil {
+			links[node.ID] = append(links[node.ID], child.ID)
+			avlLinks(child, links)
+		}
+	}
+}
+
+func rbLinks(tree *RedBlackTree, node *RBNode, links treeLinks) {
+	if node == tree.NIL {
+		return
+	}
+	links[node.ID] = nil
+	for _, child := range []*RBNode{node.Left, node.Right} {
+		if child != tree.NIL {
+			links[node.ID] = append(links[node.ID], child.ID)
+			rbLinks(tree, child, links)
+		}
+	}
+}
+
+// longestPath returns the edge count of the longest path below id by
+// trying every node as the turning point
+func longestPath(links treeLinks, id int) (height, diameter int) {
+	var heights []int
+	for _, child := range links[id] {
+		h, d := longestPath(links, child)
+		heights = append(heights, h+1)
+		diameter = max(diameter, d)
+	}
+	through := 0
+	for _, h := range heights {
+		through += h
+		height = max(height, h)
+	}
+	return height, max(diameter, through)
+}
+
+// checkDiameter verifies result is a simple path of adjacent nodes as long
+// as the longest path of the tree
+func checkDiameter(t *testing.T, result OperationResult, links treeLinks, root int) {
+	t.Helper()
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+	_, want := longestPath(links, root)
+	if got := len(result.PathIDs) - 1; got != want {
+		t.Fatalf("diameter %d edges, want %d", got, want)
+	}
+	seen := make(map[int]bool)
+	for i, id := range result.PathIDs {
+		if seen[id] {
+			t.Fatalf("node %d repeats in %v", id, result.PathIDs)
+		}
+		seen[id] = true
+		if i == 0 {
+			continue
+		}
+		prev := result.PathIDs[i-1]
+		if !slices.Contains(links[prev], id) && !slices.Contains(links[id], prev) {
+			t.Fatalf("%d and %d are not adjacent in %v", prev, id, result.PathIDs)
+		}
+	}
+}
+
+func TestDiameter(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	values := rng.Perm(60)
+
+	t.Run("avltree", func(t *testing.T) {
+		tree := NewAVLTree()
+		if result := tree.Diameter(); result.Success || result.Reason != ReasonEmptyTree {
+			t.Errorf("empty tree: success %v reason %q", result.Success, result.Reason)
+		}
+		tree.Insert(values[0])
+		if result := tree.Diameter(); !result.Success || len(result.PathIDs) != 1 {
+			t.Errorf("single node: success %v path %v", result.Success, result.PathIDs)
+		}
+		for _, v := range values[1:] {
+			tree.Insert(v)
+		}
+		links := make(treeLinks)
+		avlLinks(tree.Root, links)
+		checkDiameter(t, tree.Diameter(), links, tree.Root.ID)
+	})
+
+	t.Run("rbtree", func(t *testing.T) {
+		tree := NewRedBlackTree()
+		if result := tree.Diameter(); result.Success || result.Reason != ReasonEmptyTree {
+			t.Errorf("empty tree: success %v reason %q", result.Success, result.Reason)
+		}
+		for _, v := range values {
+			tree.Insert(v)
+		}
+		links := make(treeLinks)
+		rbLinks(tree, tree.Root, links)
+		checkDiameter(t, tree.Diameter(), links, tree.Root.ID)
+	})
+}
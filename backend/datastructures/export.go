@@ -0,0 +1,389 @@
+package datastructures
+
+import "fmt"
+
+// TreeNodeExport is the serialized form of a single tree node
+type TreeNodeExport struct {
+	ID      int       `json:"id"`
+	Value   int       `json:"value"`
+	Color   NodeColor `json:"color,omitempty"`
+	Height  int       `json:"height,omitempty"`
+	LeftID  *int      `json:"leftId,omitempty"`
+	RightID *int      `json:"rightId,omitempty"`
+}
+
+// TreeExport is the serialized form of a whole tree. It keeps node IDs and
+// shape so that importing reconstructs an identical tree.
+type TreeExport struct {
+	Type   string           `json:"type"`
+	NextID int              `json:"nextId"`
+	RootID *int             `json:"rootId,omitempty"`
+	Nodes  []TreeNodeExport `json:"nodes"`
+
+	// ValueIDs keeps the permanent IDs of values no longer in the tree
+	ValueIDs map[int]int `json:"valueIds,omitempty"`
+}
+
+// GraphExport is the serialized form of a graph. Undirected edges are
+// listed once.
+type GraphExport struct {
+	Nodes              []GraphNodeInput `json:"nodes"`
+	Edges              []GraphEdgeInput `json:"edges"`
+	AllowSelfLoops     bool             `json:"allowSelfLoops,omitempty"`
+	AllowParallelEdges bool             `json:"allowParallelEdges,omitempty"`
+	Directed           bool             `json:"directed,omitempty"`
+}
+
+// Export serializes the AVL Tree
+func (t *AVLTree) Export() TreeExport {
+	export := TreeExport{
+		Type:     "avltree",
+		NextID:   t.nextID,
+		Nodes:    make([]TreeNodeExport, 0),
+		ValueIDs: t.valueIDs,
+	}
+	if t.Root != nil {
+		rootID := t.Root.ID
+		export.RootID = &rootID
+	}
+
+	var walk func(node *AVLNode)
+	walk = func(node *AVLNode) {
+		if node == nil {
+			return
+		}
+		n := TreeNodeExport{ID: node.ID, Value: node.Value, Height: node.Height}
+		if node.Left != nil {
+			leftID := node.Left.ID
+			n.LeftID = &leftID
+		}
+		if node.Right != nil {
+			rightID := node.Right.ID
+			n.RightID = &rightID
+		}
+		export.Nodes = append(export.Nodes, n)
+		walk(node.Left)
+		walk(node.Right)
+	}
+	walk(t.Root)
+
+	return export
+}
+
+// ImportAVLTree reconstructs an AVL Tree from its exported form
+func ImportAVLTree(export TreeExport) (*AVLTree, error) {
+	t := NewAVLTree()
+	t.nextID = export.NextID
+	t.valueIDs = importValueIDs(export)
+
+	nodes := make(map[int]*AVLNode, len(export.Nodes))
+	for _, n := range export.Nodes {
+		if _, exists := nodes[n.ID]; exists {
+			return nil, fmt.Errorf("duplicate node id %d", n.ID)
+		}
+		nodes[n.ID] = &AVLNode{ID: n.ID, Value: n.Value, Height: n.Height}
+	}
+	for _, n := range export.Nodes {
+		node := nodes[n.ID]
+		if n.LeftID != nil {
+			child, ok := nodes[*n.LeftID]
+			if !ok {
+				return nil, fmt.Errorf("node %d references unknown left child %d", n.ID, *n.LeftID)
+			}
+			node.Left = child
+		}
+		if n.RightID != nil {
+			child, ok := nodes[*n.RightID]
+			if !ok {
+				return nil, fmt.Errorf("node %d references unknown right child %d", n.ID, *n.RightID)
+			}
+			node.Right = child
+		}
+	}
+
+	if export.RootID != nil {
+		root, ok := nodes[*export.RootID]
+		if !ok {
+			return nil, fmt.Errorf("unknown root id %d", *export.RootID)
+		}
+		t.Root = root
+	}
+
+	return t, nil
+}
+
+// importValueIDs restores the value-to-ID map of an exported tree, filling
+// in values of live nodes for exports that predate the map
+func importValueIDs(export TreeExport) map[int]int {
+	valueIDs := make(map[int]int, len(export.ValueIDs)+len(export.Nodes))
+	for value, id := range export.ValueIDs {
+		valueIDs[value] = id
+	}
+	for _, n := range export.Nodes {
+		if _, ok := valueIDs[n.Value]; !ok {
+			valueIDs[n.Value] = n.ID
+		}
+	}
+	return valueIDs
+}
+
+// Export serializes the Red-Black Tree
+func (t *RedBlackTree) Export() TreeExport {
+	export := TreeExport{
+		Type:     "rbtree",
+		NextID:   t.nextID,
+		Nodes:    make([]TreeNodeExport, 0),
+		ValueIDs: t.valueIDs,
+	}
+	if t.Root != t.NIL {
+		rootID := t.Root.ID
+		export.RootID = &rootID
+	}
+
+	var walk func(node *RBNode)
+	walk = func(node *RBNode) {
+		if node == t.NIL || node == nil {
+			return
+		}
+		n := TreeNodeExport{ID: node.ID, Value: node.Value, Color: node.Color}
+		if node.Left != t.NIL {
+			leftID := node.Left.ID
+			n.LeftID = &leftID
+		}
+		if node.Right != t.NIL {
+			rightID := node.Right.ID
+			n.RightID = &rightID
+		}
+		export.Nodes = append(export.Nodes, n)
+		walk(node.Left)
+		walk(node.Right)
+	}
+	walk(t.Root)
+
+	return export
+}
+
+// ImportRedBlackTree reconstructs a Red-Black Tree from its exported form
+func ImportRedBlackTree(export TreeExport) (*RedBlackTree, error) {
+	t := NewRedBlackTree()
+	t.nextID = export.NextID
+	t.valueIDs = importValueIDs(export)
+
+	nodes := make(map[int]*RBNode, len(export.Nodes))
+	for _, n := range export.Nodes {
+		if _, exists := nodes[n.ID]; exists {
+			return nil, fmt.Errorf("duplicate node id %d", n.ID)
+		}
+		color := n.Color
+		if color != Red {
+			color = Black
+		}
+		nodes[n.ID] = &RBNode{
+			ID:     n.ID,
+			Value:  n.Value,
+			Color:  color,
+			Left:   t.NIL,
+			Right:  t.NIL,
+			Parent: t.NIL,
+		}
+	}
+	for _, n := range export.Nodes {
+		node := nodes[n.ID]
+		if n.LeftID != nil {
+			child, ok := nodes[*n.LeftID]
+			if !ok {
+				return nil, fmt.Errorf("node %d references unknown left child %d", n.ID, *n.LeftID)
+			}
+			node.Left = child
+			child.Parent = node
+		}
+		if n.RightID != nil {
+			child, ok := nodes[*n.RightID]
+			if !ok {
+				return nil, fmt.Errorf("node %d references unknown right child %d", n.ID, *n.RightID)
+			}
+			node.Right = child
+			child.Parent = node
+		}
+	}
+
+	if export.RootID != nil {
+		root, ok := nodes[*export.RootID]
+		if !ok {
+			return nil, fmt.Errorf("unknown root id %d", *export.RootID)
+		}
+		t.Root = root
+	}
+	// The exported coloring may come from raw placement
+	t.placedRaw = true
+
+	return t, nil
+}
+
+// Export serializes the graph with nodes sorted by ID
+func (g *Graph) Export() GraphExport {
+	ids := g.sortedIDs()
+
+	export := GraphExport{
+		Nodes:              make([]GraphNodeInput, 0, len(ids)),
+		Edges:              make([]GraphEdgeInput, 0),
+		AllowSelfLoops:     g.AllowSelfLoops,
+		AllowParallelEdges: g.AllowParallelEdges,
+		Directed:           g.Directed,
+	}
+	for _, id := range ids {
+		coords := g.NodeCoords[id]
+		x, y := coords[0], coords[1]
+		export.Nodes = append(export.Nodes, GraphNodeInput{ID: id, X: &x, Y: &y, Weight: g.NodeWeight[id]})
+
+		// Each undirected edge is stored on both endpoints, so it is emitted
+		// from the smaller one; a self-loop is stored once
+		for _, e := range g.Nodes[id] {
+			if !g.Directed && e.To < id {
+				continue
+			}
+			export.Edges = append(export.Edges, GraphEdgeInput{From: id, To: e.To, Weight: e.Weight})
+		}
+	}
+
+	return export
+}
+
+// ImportGraph reconstructs a graph from its exported form
+func ImportGraph(export GraphExport) (*Graph, error) {
+	g := NewGraph()
+	g.AllowSelfLoops = export.AllowSelfLoops
+	g.AllowParallelEdges = export.AllowParallelEdges
+	g.Directed = export.Directed
+	unplaced := make([]string, 0)
+	for _, n := range export.Nodes {
+		x, y, missing := coordsOrZero(n)
+		g.AddNode(n.ID, x, y)
+		if missing {
+			unplaced = append(unplaced, n.ID)
+		}
+		if n.Weight != 0 {
+			g.NodeWeight[n.ID] = n.Weight
+		}
+	}
+	g.layoutCircle(unplaced)
+	for _, e := range export.Edges {
+		for _, id := range []string{e.From, e.To} {
+			if !g.HasNode(id) {
+				return nil, fmt.Errorf("edge %s-%s references unknown node %s", e.From, e.To, id)
+			}
+		}
+		if err := g.AddEdge(e.From, e.To, e.Weight); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Node234Export is the serialized form of a 2-3-4 tree node and its subtree
+type Node234Export struct {
+	ID       int             `json:"id"`
+	Keys     []int           `json:"keys"`
+	Children []Node234Export `json:"children,omitempty"`
+}
+
+// Tree234Export is the serialized form of a 2-3-4 tree
+type Tree234Export struct {
+	NextID int            `json:"nextId"`
+	Root   *Node234Export `json:"root,omitempty"`
+}
+
+// Export serializes the 2-3-4 tree
+func (t *Tree234) Export() Tree234Export {
+	var walk func(node *Node234) Node234Export
+	walk = func(node *Node234) Node234Export {
+		n := Node234Export{ID: node.ID, Keys: append([]int(nil), node.Keys...)}
+		for _, child := range node.Children {
+			n.Children = append(n.Children, walk(child))
+		}
+		return n
+	}
+
+	export := Tree234Export{NextID: t.nextID}
+	if t.Root != nil {
+		root := walk(t.Root)
+		export.Root = &root
+	}
+	return export
+}
+
+// ImportTree234 reconstructs a 2-3-4 tree from its exported form
+func ImportTree234(export Tree234Export) (*Tree234, error) {
+	t := NewTree234()
+	t.nextID = export.NextID
+
+	var build func(n Node234Export) (*Node234, error)
+	build = func(n Node234Export) (*Node234, error) {
+		if len(n.Keys) < 1 || len(n.Keys) > 3 {
+			return nil, fmt.Errorf("node %d has %d keys, expected 1 to 3", n.ID, len(n.Keys))
+		}
+		if len(n.Children) != 0 && len(n.Children) != len(n.Keys)+1 {
+			return nil, fmt.Errorf("node %d has %d keys but %d children", n.ID, len(n.Keys), len(n.Children))
+		}
+		node := &Node234{ID: n.ID, Keys: append([]int(nil), n.Keys...)}
+		for _, c := range n.Children {
+			child, err := build(c)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+		}
+		return node, nil
+	}
+
+	if export.Root != nil {
+		root, err := build(*export.Root)
+		if err != nil {
+			return nil, err
+		}
+		t.Root = root
+	}
+	return t, nil
+}
+
+// HeapExport is the serialized form of a binary heap, its array slots in
+// order
+type HeapExport struct {
+	Items []int `json:"items"`
+}
+
+// Export serializes the binary heap
+func (h *BinaryHeap) Export() HeapExport {
+	return HeapExport{Items: append([]int{}, h.Items...)}
+}
+
+// ImportBinaryHeap reconstructs a binary heap from its exported form. The
+// slots must already satisfy the heap property.
+func ImportBinaryHeap(export HeapExport) (*BinaryHeap, error) {
+	for i := 1; i < len(export.Items); i++ {
+		if parent := (i - 1) / 2; export.Items[parent] > export.Items[i] {
+			return nil, fmt.Errorf("slot %d holds %d, smaller than its parent %d", i, export.Items[i], export.Items[parent])
+		}
+	}
+	h := NewBinaryHeap()
+	h.Items = append(h.Items, export.Items...)
+	return h, nil
+}
+
+// ArrayExport is the serialized form of the array sorter: the array its
+// last sort left behind
+type ArrayExport struct {
+	Values []int `json:"values"`
+}
+
+// Export serializes the array sorter
+func (s *ArraySorter) Export() ArrayExport {
+	return ArrayExport{Values: s.Values()}
+}
+
+// ImportArraySorter reconstructs an array sorter from its exported form
+func ImportArraySorter(export ArrayExport) *ArraySorter {
+	s := NewArraySorter()
+	s.items = append([]int(nil), export.Values...)
+	return s
+}
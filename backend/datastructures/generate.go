@@ -0,0 +1,75 @@
+package datastructures
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// MaxGeneratedNodes bounds the size of graphs created by GenerateGraph
+const MaxGeneratedNodes = 500
+
+// maxGeneratedWeight is the largest random edge weight
+const maxGeneratedWeight = 20
+
+// GenerateGraph replaces the graph with a random connected, undirected,
+// weighted graph of n nodes and about m edges. A random spanning tree is
+// laid down first to guarantee connectivity, then extra edges are added
+// between distinct unconnected pairs. m is clamped to the range
+// [n-1, n(n-1)/2]. The same seed always produces the same graph.
+func (g *Graph) GenerateGraph(n, m int, seed int64) OperationResult {
+	if n < 1 || n > MaxGeneratedNodes {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.generate.invalid", MaxGeneratedNodes),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}
+	}
+	if m < n-1 {
+		m = n - 1
+	}
+	if limit := n * (n - 1) / 2; m > limit {
+		m = limit
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	nodes := make([]GraphNodeInput, n)
+	for i := range nodes {
+		x := roundCoord(graphCenterX - 300 + r.Float64()*600)
+		y := roundCoord(graphCenterY - layoutRadius + r.Float64()*2*layoutRadius)
+		nodes[i] = GraphNodeInput{ID: strconv.Itoa(i), X: &x, Y: &y}
+	}
+
+	linked := make(map[[2]int]bool, m)
+	edges := make([]GraphEdgeInput, 0, m)
+	link := func(a, b int) {
+		if a > b {
+			a, b = b, a
+		}
+		linked[[2]int{a, b}] = true
+		edges = append(edges, GraphEdgeInput{From: nodes[a].ID, To: nodes[b].ID, Weight: float64(1 + r.Intn(maxGeneratedWeight))})
+	}
+
+	// Attach every node to a random earlier one, in a shuffled order
+	order := r.Perm(n)
+	for i := 1; i < n; i++ {
+		link(order[r.Intn(i)], order[i])
+	}
+
+	for len(edges) < m {
+		a, b := r.Intn(n), r.Intn(n)
+		if a > b {
+			a, b = b, a
+		}
+		if a == b || linked[[2]int{a, b}] {
+			continue
+		}
+		link(a, b)
+	}
+
+	result := g.BuildGraph(nodes, edges, false, false, false)
+	if result.Success {
+		result.Message = g.msg("graph.generate.success", n, len(edges), seed)
+	}
+	return result
+}
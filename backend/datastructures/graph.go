@@ -2,14 +2,33 @@ package datastructures
 
 import (
 	"container/heap"
+	"context"
 	"fmt"
 	"math"
+	"sort"
+	"time"
 )
 
 // Edge represents an edge in the graph
 type Edge struct {
 	To     string
-	Weight int
+	Weight float64
+}
+
+// GraphNodeInput describes a node of a user-built graph
+type GraphNodeInput struct {
+	ID string `json:"id"`
+	// X and Y are optional; nodes without them are laid out automatically
+	X      *float64 `json:"x,omitempty"`
+	Y      *float64 `json:"y,omitempty"`
+	Weight int      `json:"weight,omitempty"`
+}
+
+// GraphEdgeInput describes an edge of a user-built graph
+type GraphEdgeInput struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Weight float64 `json:"weight"`
 }
 
 // Graph represents a weighted graph with step tracking
@@ -17,27 +36,80 @@ type Graph struct {
 	Nodes      map[string][]Edge
 	NodeCoords map[string][2]float64
 	steps      []Step
+
+	// started is when the current operation began, the origin of its step
+	// timestamps
+	started time.Time
+
+	// NodeWeight holds optional per-node costs; missing nodes weigh 0
+	NodeWeight map[string]int
+
+	// AllowSelfLoops permits edges whose endpoints are the same node
+	AllowSelfLoops bool
+
+	// AllowParallelEdges keeps repeated edges between the same endpoints
+	// as separate edges instead of merging them into the lightest one
+	AllowParallelEdges bool
+
+	// Directed stores each edge only on its source node
+	Directed bool
+
+	// includeSnapshots controls whether every step embeds the full node and
+	// edge arrays. When disabled, steps carry a GraphDelta instead and only
+	// the final graph is complete.
+	includeSnapshots bool
+
+	// latest is the full snapshot of the most recent step
+	latest *GraphState
+
+	// locale selects the language of step descriptions and messages
+	locale Locale
+
+	// ctx aborts long-running operations when canceled
+	ctx context.Context
 }
 
 // NewGraph creates a new Graph
 func NewGraph() *Graph {
 	return &Graph{
-		Nodes:      make(map[string][]Edge),
-		NodeCoords: make(map[string][2]float64),
-		steps:      make([]Step, 0),
+		Nodes:            make(map[string][]Edge),
+		NodeCoords:       make(map[string][2]float64),
+		NodeWeight:       make(map[string]int),
+		steps:            make([]Step, 0),
+		includeSnapshots: true,
 	}
 }
 
+// SetIncludeSnapshots toggles the full per-step graph snapshots. The final
+// graph of an operation is always complete.
+func (g *Graph) SetIncludeSnapshots(include bool) {
+	g.includeSnapshots = include
+}
+
+// SetLocale selects the language of step descriptions and messages
+func (g *Graph) SetLocale(locale Locale) {
+	g.locale = locale
+}
+
+func (g *Graph) msg(key string, args ...interface{}) string {
+	return Localize(g.locale, key, args...)
+}
+
 func (g *Graph) clearSteps() {
 	g.steps = make([]Step, 0)
+	g.started = time.Now()
+	g.latest = nil
 }
 
-func (g *Graph) buildSnapshot(distances map[string]int, visited map[string]bool, path []string, currentEdge *[2]string) ([]GraphNodeSnapshot, []GraphEdgeSnapshot) {
-	nodes := make([]GraphNodeSnapshot, 0)
-	for id := range g.Nodes {
-		var distPtr *int
+// buildSnapshot lists nodes and edges in node ID order, so an unchanged
+// graph always produces the same snapshot
+func (g *Graph) buildSnapshot(distances map[string]float64, visited map[string]bool, path []string, currentEdge *[2]string) ([]GraphNodeSnapshot, []GraphEdgeSnapshot) {
+	ids := g.sortedIDs()
+	nodes := make([]GraphNodeSnapshot, 0, len(ids))
+	for _, id := range ids {
+		var distPtr *float64
 		if distances != nil {
-			if dist, ok := distances[id]; ok && dist != math.MaxInt32 {
+			if dist, ok := distances[id]; ok && !math.IsInf(dist, 1) {
 				d := dist
 				distPtr = &d
 			}
@@ -62,21 +134,22 @@ func (g *Graph) buildSnapshot(distances map[string]int, visited map[string]bool,
 			Distance: distPtr,
 			Visited:  visitedVal,
 			InPath:   inPath,
+			Weight:   g.NodeWeight[id],
 		})
 	}
 
 	edges := make([]GraphEdgeSnapshot, 0)
-	for from, neighbors := range g.Nodes {
-		for _, e := range neighbors {
+	for _, from := range ids {
+		for _, e := range g.Nodes[from] {
 			inPath := false
 			for i := 0; i < len(path)-1; i++ {
-				if (path[i] == from && path[i+1] == e.To) || (path[i] == e.To && path[i+1] == from) {
+				if g.sameEdge(path[i], path[i+1], from, e.To) {
 					inPath = true
 					break
 				}
 			}
 			selected := false
-			if currentEdge != nil && ((currentEdge[0] == from && currentEdge[1] == e.To) || (currentEdge[0] == e.To && currentEdge[1] == from)) {
+			if currentEdge != nil && g.sameEdge(currentEdge[0], currentEdge[1], from, e.To) {
 				selected = true
 			}
 			edges = append(edges, GraphEdgeSnapshot{
@@ -92,29 +165,238 @@ func (g *Graph) buildSnapshot(distances map[string]int, visited map[string]bool,
 	return nodes, edges
 }
 
-func (g *Graph) addStep(stepType StepType, desc string, distances map[string]int, visited map[string]bool, path []string, currentEdge *[2]string) {
+// sortedIDs returns the node IDs in ascending order
+func (g *Graph) sortedIDs() []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sameEdge reports whether a→b denotes the edge from→to, ignoring direction
+// in undirected graphs
+func (g *Graph) sameEdge(a, b, from, to string) bool {
+	if a == from && b == to {
+		return true
+	}
+	return !g.Directed && a == to && b == from
+}
+
+func (g *Graph) addStep(stepType StepType, desc string, distances map[string]float64, visited map[string]bool, path []string, currentEdge *[2]string) {
 	nodes, edges := g.buildSnapshot(distances, visited, path, currentEdge)
 	step := Step{
 		Type:        stepType,
 		Description: desc,
-		GraphNodes:  nodes,
-		GraphEdges:  edges,
 	}
+	if g.includeSnapshots {
+		step.GraphNodes = nodes
+		step.GraphEdges = edges
+	} else {
+		step.GraphDelta = g.delta(nodes, path, currentEdge)
+	}
+	g.latest = &GraphState{Nodes: nodes, Edges: edges}
+	step.stamp(len(g.steps), g.started)
 	g.steps = append(g.steps, step)
 }
 
+// HasNode reports whether the graph contains a node with the given ID
+func (g *Graph) HasNode(id string) bool {
+	_, exists := g.Nodes[id]
+	return exists
+}
+
 // AddNode adds a node to the graph
 func (g *Graph) AddNode(id string, x, y float64) {
-	if _, exists := g.Nodes[id]; !exists {
+	if !g.HasNode(id) {
 		g.Nodes[id] = make([]Edge, 0)
 	}
 	g.NodeCoords[id] = [2]float64{x, y}
 }
 
-// AddEdge adds an edge to the graph
-func (g *Graph) AddEdge(from, to string, weight int) {
+// hasEdge reports whether the graph has an edge from→to
+func (g *Graph) hasEdge(from, to string) bool {
+	for _, e := range g.Nodes[from] {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AddEdge adds an edge to the graph. Self-loops are rejected unless
+// AllowSelfLoops is set. Unless AllowParallelEdges is set, adding an edge
+// that already exists merges the two, keeping the lighter weight. An
+// undirected edge is stored on both endpoints, except a self-loop, which
+// is stored once.
+func (g *Graph) AddEdge(from, to string, weight float64) error {
+	if from == to && !g.AllowSelfLoops {
+		return fmt.Errorf("self-loop on node %s is not allowed", from)
+	}
+	mirror := !g.Directed && from != to
+	if !g.AllowParallelEdges && g.hasEdge(from, to) {
+		g.lowerWeight(from, to, weight)
+		if mirror {
+			g.lowerWeight(to, from, weight)
+		}
+		return nil
+	}
 	g.Nodes[from] = append(g.Nodes[from], Edge{To: to, Weight: weight})
-	g.Nodes[to] = append(g.Nodes[to], Edge{To: from, Weight: weight}) // Undirected
+	if mirror {
+		g.Nodes[to] = append(g.Nodes[to], Edge{To: from, Weight: weight})
+	}
+	return nil
+}
+
+// lowerWeight reduces the weight of every stored edge from→to to weight
+// where that is lighter
+func (g *Graph) lowerWeight(from, to string, weight float64) {
+	edges := g.Nodes[from]
+	for i := range edges {
+		if edges[i].To == to && weight < edges[i].Weight {
+			edges[i].Weight = weight
+		}
+	}
+}
+
+// ValidateEdge reports every problem with a prospective edge instead of
+// stopping at the first one
+func (g *Graph) ValidateEdge(from, to string, weight float64) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+	if from == to && !g.AllowSelfLoops {
+		issues = append(issues, ValidationIssue{
+			Severity: SeverityError,
+			Code:     "self_loop",
+			Message:  g.msg("graph.validate.self_loop", from, to),
+			From:     from,
+			To:       to,
+		})
+	}
+	if weight < 0 {
+		issues = append(issues, ValidationIssue{
+			Severity: SeverityWarning,
+			Code:     "negative_weight",
+			Message:  g.msg("graph.validate.negative_weight", from, to, weight),
+			From:     from,
+			To:       to,
+		})
+	}
+	if !g.AllowParallelEdges && g.hasEdge(from, to) {
+		issues = append(issues, ValidationIssue{
+			Severity: SeverityWarning,
+			Code:     "parallel_edge",
+			Message:  g.msg("graph.validate.parallel_edge", from, to),
+			From:     from,
+			To:       to,
+		})
+	}
+	return issues
+}
+
+// BuildGraph replaces the graph with the given nodes and edges. All input is
+// validated up front; if any error-level issue is found the graph is left
+// untouched and every issue is returned.
+func (g *Graph) BuildGraph(nodes []GraphNodeInput, edges []GraphEdgeInput, allowSelfLoops, allowParallelEdges, directed bool) OperationResult {
+	g.clearSteps()
+
+	candidate := NewGraph()
+	candidate.AllowSelfLoops = allowSelfLoops
+	candidate.AllowParallelEdges = allowParallelEdges
+	candidate.Directed = directed
+	candidate.locale = g.locale
+
+	issues := make([]ValidationIssue, 0)
+	unplaced := make([]string, 0)
+	for _, n := range nodes {
+		if n.ID == "" {
+			issues = append(issues, ValidationIssue{
+				Severity: SeverityError,
+				Code:     "empty_node_id",
+				Message:  g.msg("graph.validate.empty_id"),
+			})
+			continue
+		}
+		if _, exists := candidate.Nodes[n.ID]; exists {
+			issues = append(issues, ValidationIssue{
+				Severity: SeverityError,
+				Code:     "duplicate_node",
+				Message:  g.msg("graph.validate.duplicate_node", n.ID),
+				From:     n.ID,
+			})
+			continue
+		}
+		x, y, missing := coordsOrZero(n)
+		candidate.AddNode(n.ID, x, y)
+		if missing {
+			unplaced = append(unplaced, n.ID)
+		}
+		if n.Weight != 0 {
+			candidate.NodeWeight[n.ID] = n.Weight
+		}
+	}
+	candidate.layoutCircle(unplaced)
+
+	for _, e := range edges {
+		edgeIssues := candidate.ValidateEdge(e.From, e.To, e.Weight)
+		for _, id := range []string{e.From, e.To} {
+			if _, exists := candidate.Nodes[id]; !exists {
+				edgeIssues = append(edgeIssues, ValidationIssue{
+					Severity: SeverityError,
+					Code:     "unknown_node",
+					Message:  g.msg("graph.validate.unknown_node", e.From, e.To, id),
+					From:     e.From,
+					To:       e.To,
+				})
+			}
+		}
+		issues = append(issues, edgeIssues...)
+		if !hasValidationErrors(edgeIssues) {
+			candidate.AddEdge(e.From, e.To, e.Weight)
+		}
+	}
+
+	if hasValidationErrors(issues) {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.validate.failed"),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+			Issues:  issues,
+		}
+	}
+
+	g.Nodes = candidate.Nodes
+	g.NodeCoords = candidate.NodeCoords
+	g.NodeWeight = candidate.NodeWeight
+	g.AllowSelfLoops = allowSelfLoops
+	g.AllowParallelEdges = allowParallelEdges
+	g.Directed = directed
+
+	// Merged parallel edges make the edge count smaller than the input
+	g.addStep(StepInsert, g.msg("graph.build.step", len(nodes), g.edgeCount()), nil, nil, nil, nil)
+	g.addStep(StepComplete, g.msg("graph.build.done"), nil, nil, nil, nil)
+
+	finalNodes, finalEdges := g.buildSnapshot(nil, nil, nil, nil)
+	return OperationResult{
+		Success: true,
+		Message: g.msg("graph.build.success", len(nodes), g.edgeCount()),
+		Steps:   g.steps,
+		FinalGraph: &GraphState{
+			Nodes: finalNodes,
+			Edges: finalEdges,
+		},
+		Issues: issues,
+	}
+}
+
+func hasValidationErrors(issues []ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
 }
 
 // Insert adds a node into the graph for visualization.
@@ -123,16 +405,17 @@ func (g *Graph) Insert(value int) OperationResult {
 	g.clearSteps()
 
 	id := fmt.Sprintf("%d", value)
-	if _, exists := g.Nodes[id]; exists {
+	if g.HasNode(id) {
 		return OperationResult{
 			Success: false,
-			Message: fmt.Sprintf("节点 %s 已存在", id),
+			Message: g.msg("graph.insert.exists", id),
+			Code:    CodeInvalidParam,
 			Steps:   []Step{},
 		}
 	}
 
 	// Auto-layout: spread nodes using golden angle around a center.
-	centerX, centerY := 325.0, 150.0
+	centerX, centerY := graphCenterX, graphCenterY
 	radius := 220.0
 	idx := float64(len(g.Nodes))
 	angle := idx * 2.399963229728653 // golden angle in radians
@@ -140,27 +423,154 @@ func (g *Graph) Insert(value int) OperationResult {
 	y := centerY + radius*math.Sin(angle)
 
 	g.AddNode(id, x, y)
-	g.addStep(StepInsert, fmt.Sprintf("添加节点 %s", id), nil, nil, nil, nil)
-	g.addStep(StepComplete, "插入完成", nil, nil, nil, nil)
+	g.addStep(StepInsert, g.msg("graph.insert.step", id), nil, nil, nil, nil)
+	g.addStep(StepComplete, g.msg("graph.insert.done"), nil, nil, nil, nil)
+
+	nodes, edges := g.buildSnapshot(nil, nil, nil, nil)
+	return OperationResult{
+		Success: true,
+		Steps:   g.steps,
+		FinalGraph: &GraphState{
+			Nodes: nodes,
+			Edges: edges,
+		},
+	}
+}
+
+// edgeCount returns the number of edges in the graph
+func (g *Graph) edgeCount() int {
+	adjacencyEntries, selfLoops := 0, 0
+	for id, edges := range g.Nodes {
+		adjacencyEntries += len(edges)
+		for _, e := range edges {
+			if e.To == id {
+				selfLoops++
+			}
+		}
+	}
+	if g.Directed {
+		return adjacencyEntries
+	}
+	// Undirected edges are stored once on each endpoint, self-loops once
+	return selfLoops + (adjacencyEntries-selfLoops)/2
+}
+
+// State returns a snapshot of the graph without modifying it
+func (g *Graph) State() OperationResult {
+	nodes, edges := g.buildSnapshot(nil, nil, nil, nil)
+	return OperationResult{
+		Success: true,
+		Message: g.msg("graph.state", len(g.Nodes), g.edgeCount()),
+		Steps:   []Step{},
+		FinalGraph: &GraphState{
+			Nodes: nodes,
+			Edges: edges,
+		},
+	}
+}
+
+// inDegrees returns the number of edges ending at every node
+func (g *Graph) inDegrees() map[string]int {
+	inDegree := make(map[string]int, len(g.Nodes))
+	for id := range g.Nodes {
+		inDegree[id] = 0
+	}
+	for _, edges := range g.Nodes {
+		for _, e := range edges {
+			inDegree[e.To]++
+		}
+	}
+	return inDegree
+}
+
+// degrees returns the number of edges touching every node. A directed
+// graph counts incoming and outgoing edges together. A self-loop touches
+// its node twice, so it adds 2 either way.
+func (g *Graph) degrees() map[string]int {
+	degree := make(map[string]int, len(g.Nodes))
+	for id, edges := range g.Nodes {
+		degree[id] = len(edges)
+		if !g.Directed {
+			for _, e := range edges {
+				if e.To == id {
+					degree[id]++
+				}
+			}
+		}
+	}
+	if g.Directed {
+		for id, in := range g.inDegrees() {
+			degree[id] += in
+		}
+	}
+	return degree
+}
+
+// Info reports node/edge counts, per-node degrees and connectivity. A
+// directed graph reports in- and out-degrees as well and counts as
+// connected when it is weakly connected, i.e. connected once edge
+// directions are ignored.
+func (g *Graph) Info() OperationResult {
+	g.clearSteps()
+
+	info := &GraphInfo{
+		NodeCount: len(g.Nodes),
+		EdgeCount: g.edgeCount(),
+		Degrees:   g.degrees(),
+	}
+	if g.Directed {
+		info.InDegree = g.inDegrees()
+		info.OutDegree = make(map[string]int, len(g.Nodes))
+		for id, edges := range g.Nodes {
+			info.OutDegree[id] = len(edges)
+		}
+	}
+
+	// Connectivity via a BFS flood from the smallest node ID
+	ids := g.sortedIDs()
+	visited := make(map[string]bool, len(g.Nodes))
+	if len(ids) > 0 {
+		neighbors := g.undirectedNeighbors()
+		queue := []string{ids[0]}
+		visited[ids[0]] = true
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range neighbors[current] {
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	info.Connected = len(visited) == len(g.Nodes)
+
+	message := g.msg("graph.info.disconnected", info.NodeCount, info.EdgeCount)
+	if info.Connected && g.Directed {
+		message = g.msg("graph.info.weakly_connected", info.NodeCount, info.EdgeCount)
+	} else if info.Connected {
+		message = g.msg("graph.info.connected", info.NodeCount, info.EdgeCount)
+	}
+	g.addStep(StepComplete, message, nil, visited, nil, nil)
 
 	nodes, edges := g.buildSnapshot(nil, nil, nil, nil)
 	return OperationResult{
 		Success: true,
+		Message: message,
 		Steps:   g.steps,
-		FinalGraph: &struct {
-			Nodes []GraphNodeSnapshot `json:"nodes"`
-			Edges []GraphEdgeSnapshot `json:"edges"`
-		}{
+		FinalGraph: &GraphState{
 			Nodes: nodes,
 			Edges: edges,
 		},
+		GraphInfo: info,
 	}
 }
 
 // PriorityQueueItem for Dijkstra
 type PriorityQueueItem struct {
 	node     string
-	priority int
+	priority float64
 	index    int
 }
 
@@ -193,30 +603,46 @@ func (pq *PriorityQueue) Pop() interface{} {
 func (g *Graph) Dijkstra(start, end string) OperationResult {
 	g.clearSteps()
 
-	distances := make(map[string]int)
+	for _, id := range []string{start, end} {
+		if !g.HasNode(id) {
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+
+	distances := make(map[string]float64)
 	previous := make(map[string]string)
 	visited := make(map[string]bool)
 
 	for node := range g.Nodes {
-		distances[node] = math.MaxInt32
+		distances[node] = math.Inf(1)
 	}
 	distances[start] = 0
 
-	g.addStep(StepVisit, fmt.Sprintf("初始化：起点 %s 距离设为 0", start), distances, visited, nil, nil)
+	g.addStep(StepVisit, g.msg("graph.dijkstra.init", start), distances, visited, nil, nil)
 
 	pq := make(PriorityQueue, 0)
 	heap.Init(&pq)
 	heap.Push(&pq, &PriorityQueueItem{node: start, priority: 0})
 
 	for pq.Len() > 0 {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
 		current := heap.Pop(&pq).(*PriorityQueueItem)
 
 		if visited[current.node] {
 			continue
 		}
-		visited[current.node] = true
+		g.addStep(StepSelectNode, g.msg("graph.dijkstra.select", current.node, distances[current.node]), distances, visited, nil, nil)
 
-		g.addStep(StepSelectNode, fmt.Sprintf("选择距离最小的未访问节点: %s (距离: %d)", current.node, distances[current.node]), distances, visited, nil, nil)
+		// The node is settled: its distance is final from here on
+		visited[current.node] = true
+		g.addStep(StepMarkVisited, g.msg("graph.dijkstra.settled", current.node, distances[current.node]), distances, visited, nil, nil)
 
 		if current.node == end {
 			// Reconstruct path
@@ -224,19 +650,14 @@ func (g *Graph) Dijkstra(start, end string) OperationResult {
 			for at := end; at != ""; at = previous[at] {
 				path = append([]string{at}, path...)
 			}
-			g.addStep(StepComplete, fmt.Sprintf("找到最短路径: %v, 总距离: %d", path, distances[end]), distances, visited, path, nil)
+			g.addStep(StepComplete, g.msg("graph.dijkstra.found", path, distances[end]), distances, visited, path, nil)
 
 			return OperationResult{
-				Success: true,
-				Message: fmt.Sprintf("最短路径距离: %d", distances[end]),
-				Steps:   g.steps,
-				FinalGraph: &struct {
-					Nodes []GraphNodeSnapshot `json:"nodes"`
-					Edges []GraphEdgeSnapshot `json:"edges"`
-				}{
-					Nodes: g.steps[len(g.steps)-1].GraphNodes,
-					Edges: g.steps[len(g.steps)-1].GraphEdges,
-				},
+				Success:    true,
+				Message:    g.msg("graph.dijkstra.success", distances[end]),
+				Steps:      g.steps,
+				FinalGraph: g.latest,
+				Paths:      []PathResult{{Nodes: path, Cost: distances[end]}},
 			}
 		}
 
@@ -249,24 +670,125 @@ func (g *Graph) Dijkstra(start, end string) OperationResult {
 			edgePtr := &[2]string{current.node, edge.To}
 
 			if newDist < distances[edge.To] {
+				oldDist := distances[edge.To]
 				distances[edge.To] = newDist
 				previous[edge.To] = current.node
 				heap.Push(&pq, &PriorityQueueItem{node: edge.To, priority: newDist})
-				g.addStep(StepUpdateDist, fmt.Sprintf("更新节点 %s 距离: %d → %d (通过 %s)", edge.To, distances[edge.To], newDist, current.node), distances, visited, nil, edgePtr)
+				g.addStep(StepUpdateDist, g.msg("graph.dijkstra.update", edge.To, formatDistance(oldDist), newDist, current.node), distances, visited, nil, edgePtr)
 			} else {
-				g.addStep(StepCompare, fmt.Sprintf("边 %s→%s: 新距离 %d >= 当前距离 %d，不更新", current.node, edge.To, newDist, distances[edge.To]), distances, visited, nil, edgePtr)
+				g.addStep(StepCompare, g.msg("graph.dijkstra.no_update", current.node, edge.To, newDist, distances[edge.To]), distances, visited, nil, edgePtr)
 			}
 		}
 	}
 
-	g.addStep(StepNotFound, fmt.Sprintf("无法从 %s 到达 %s", start, end), distances, visited, nil, nil)
+	g.addStep(StepNotFound, g.msg("graph.unreachable_step", start, end), distances, visited, nil, nil)
 	return OperationResult{
 		Success: false,
-		Message: "无法到达目标节点",
+		Message: g.msg("graph.unreachable"),
+		Reason:  ReasonUnreachable,
+		NoOp:    true,
 		Steps:   g.steps,
 	}
 }
 
+// UnreachableDistance marks nodes that cannot be reached from the source
+const UnreachableDistance = -1
+
+// DijkstraAll runs Dijkstra from start to completion and returns the final
+// distance and predecessor of every node
+func (g *Graph) DijkstraAll(start string) OperationResult {
+	g.clearSteps()
+
+	if !g.HasNode(start) {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.node_missing", start),
+			Code:    CodeNodeNotFound,
+			Steps:   []Step{},
+		}
+	}
+
+	distances := make(map[string]float64)
+	previous := make(map[string]string)
+	visited := make(map[string]bool)
+
+	for node := range g.Nodes {
+		distances[node] = math.Inf(1)
+	}
+	distances[start] = 0
+
+	g.addStep(StepVisit, g.msg("graph.dijkstra.init", start), distances, visited, nil, nil)
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &PriorityQueueItem{node: start, priority: 0})
+
+	for pq.Len() > 0 {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		current := heap.Pop(&pq).(*PriorityQueueItem)
+
+		if visited[current.node] {
+			continue
+		}
+		g.addStep(StepSelectNode, g.msg("graph.dijkstra.select", current.node, distances[current.node]), distances, visited, nil, nil)
+
+		// The node is settled: its distance is final from here on
+		visited[current.node] = true
+		g.addStep(StepMarkVisited, g.msg("graph.dijkstra.settled", current.node, distances[current.node]), distances, visited, nil, nil)
+
+		for _, edge := range g.Nodes[current.node] {
+			if visited[edge.To] {
+				continue
+			}
+
+			newDist := distances[current.node] + edge.Weight
+			edgePtr := &[2]string{current.node, edge.To}
+
+			if newDist < distances[edge.To] {
+				oldDist := distances[edge.To]
+				distances[edge.To] = newDist
+				previous[edge.To] = current.node
+				heap.Push(&pq, &PriorityQueueItem{node: edge.To, priority: newDist})
+				g.addStep(StepUpdateDist, g.msg("graph.dijkstra.update", edge.To, formatDistance(oldDist), newDist, current.node), distances, visited, nil, edgePtr)
+			} else {
+				g.addStep(StepCompare, g.msg("graph.dijkstra.no_update", current.node, edge.To, newDist, distances[edge.To]), distances, visited, nil, edgePtr)
+			}
+		}
+	}
+
+	finalDistances := make(map[string]float64, len(distances))
+	unreachable := 0
+	for node, dist := range distances {
+		if math.IsInf(dist, 1) {
+			finalDistances[node] = UnreachableDistance
+			unreachable++
+		} else {
+			finalDistances[node] = dist
+		}
+	}
+
+	g.addStep(StepComplete, g.msg("graph.sssp.done", start, unreachable), distances, visited, nil, nil)
+
+	return OperationResult{
+		Success:      true,
+		Message:      g.msg("graph.sssp.success", start),
+		Steps:        g.steps,
+		FinalGraph:   g.latest,
+		Distances:    finalDistances,
+		Predecessors: previous,
+	}
+}
+
+// formatDistance renders a tentative distance, using ∞ for unset ones
+func formatDistance(dist float64) string {
+	if math.IsInf(dist, 1) {
+		return "∞"
+	}
+	return fmt.Sprintf("%g", dist)
+}
+
 // CreateSampleGraph creates a sample graph for demonstration
 func CreateSampleGraph() *Graph {
 	g := NewGraph()
@@ -0,0 +1,512 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// buildGraph builds a graph from "from", "to" pairs with the given weights
+func buildGraph(t *testing.T, directed bool, edges ...GraphEdgeInput) *Graph {
+	t.Helper()
+	seen := make(map[string]bool)
+	nodes := make([]GraphNodeInput, 0)
+	for _, e := range edges {
+		for _, id := range []string{e.From, e.To} {
+			if !seen[id] {
+				seen[id] = true
+				nodes = append(nodes, GraphNodeInput{ID: id})
+			}
+		}
+	}
+	g := NewGraph()
+	if result := g.BuildGraph(nodes, edges, false, false, directed); !result.Success {
+		t.Fatalf("build graph: %s", result.Message)
+	}
+	return g
+}
+
+func TestInfoDirectedIsWeaklyConnected(t *testing.T) {
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 1},
+		GraphEdgeInput{From: "C", To: "B", Weight: 1},
+	)
+	// Map iteration order varies between runs, so repeat the query
+	for i := 0; i < 20; i++ {
+		info := g.Info().GraphInfo
+		if !info.Connected {
+			t.Fatal("A→B←C reported as disconnected")
+		}
+		if info.InDegree["B"] != 2 || info.OutDegree["B"] != 0 || info.OutDegree["A"] != 1 {
+			t.Fatalf("in %v out %v", info.InDegree, info.OutDegree)
+		}
+		if info.Degrees["B"] != 2 {
+			t.Fatalf("degrees %v", info.Degrees)
+		}
+	}
+}
+
+func TestCentralityDirected(t *testing.T) {
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 1},
+		GraphEdgeInput{From: "B", To: "C", Weight: 1},
+	)
+	result := g.Centrality()
+	for _, n := range result.FinalGraph.Nodes {
+		if n.ID != "B" {
+			continue
+		}
+		if *n.BetweennessCentrality != 1 {
+			t.Errorf("betweenness of B = %v, want 1", *n.BetweennessCentrality)
+		}
+		if *n.DegreeCentrality != 1 {
+			t.Errorf("degree centrality of B = %v, want 1", *n.DegreeCentrality)
+		}
+	}
+}
+
+func TestDegreeCentralityOnSampleGraph(t *testing.T) {
+	// Neighbors counted by hand from CreateSampleGraph's edge list
+	degrees := map[string]int{"A": 2, "B": 3, "C": 4, "D": 4, "E": 3, "F": 2}
+	result := CreateSampleGraph().Centrality()
+	if len(result.FinalGraph.Nodes) != len(degrees) {
+		t.Fatalf("got %d nodes, want %d", len(result.FinalGraph.Nodes), len(degrees))
+	}
+	for _, n := range result.FinalGraph.Nodes {
+		want := float64(degrees[n.ID]) / float64(len(degrees)-1)
+		if got := *n.DegreeCentrality; math.Abs(got-want) > 1e-12 {
+			t.Errorf("degree centrality of %s = %v, want %v", n.ID, got, want)
+		}
+	}
+}
+
+func TestCentralityFractionalWeightsTie(t *testing.T) {
+	// A→B→C weighs 0.1+0.2, which is not exactly 0.3 in floating point
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 0.1},
+		GraphEdgeInput{From: "B", To: "C", Weight: 0.2},
+		GraphEdgeInput{From: "A", To: "D", Weight: 0.15},
+		GraphEdgeInput{From: "D", To: "C", Weight: 0.15},
+	)
+	_, predecessors, sigma := g.brandesShortestPaths("A")
+	if sigma["C"] != 2 || len(predecessors["C"]) != 2 {
+		t.Fatalf("sigma[C] = %v, predecessors %v, want 2 shortest paths", sigma["C"], predecessors["C"])
+	}
+
+	result := g.Centrality()
+	for _, n := range result.FinalGraph.Nodes {
+		if n.ID != "B" && n.ID != "D" {
+			continue
+		}
+		if *n.BetweennessCentrality != 0.5 {
+			t.Errorf("betweenness of %s = %v, want 0.5", n.ID, *n.BetweennessCentrality)
+		}
+	}
+}
+
+func TestLongestPathCriticalPath(t *testing.T) {
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 3},
+		GraphEdgeInput{From: "A", To: "C", Weight: 2},
+		GraphEdgeInput{From: "B", To: "D", Weight: 4},
+		GraphEdgeInput{From: "C", To: "D", Weight: 1},
+	)
+	result := g.LongestPath()
+	if !result.Success {
+		t.Fatalf("longest path failed: %s", result.Message)
+	}
+	path := result.Paths[0]
+	if path.Cost != 7 || len(path.Nodes) != 3 || path.Nodes[0] != "A" || path.Nodes[1] != "B" || path.Nodes[2] != "D" {
+		t.Errorf("critical path %v cost %v, want [A B D] cost 7", path.Nodes, path.Cost)
+	}
+}
+
+func TestGraphFailureCodes(t *testing.T) {
+	empty := NewGraph()
+	if result := empty.BuildGraph(nil, nil, false, false, true); !result.Success {
+		t.Fatalf("build graph: %s", result.Message)
+	}
+	undirected := buildGraph(t, false, GraphEdgeInput{From: "A", To: "B", Weight: 1})
+	cyclic := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 1},
+		GraphEdgeInput{From: "B", To: "A", Weight: 1},
+	)
+	numbered := buildGraph(t, false, GraphEdgeInput{From: "1", To: "2", Weight: 1})
+
+	tests := []struct {
+		name   string
+		result OperationResult
+		code   ErrorCode
+	}{
+		{"longest path undirected", undirected.LongestPath(), CodeInvalidState},
+		{"longest path cyclic", cyclic.LongestPath(), CodeInvalidState},
+		{"longest path empty", empty.LongestPath(), CodeInvalidState},
+		{"scc undirected", undirected.SCC(), CodeInvalidState},
+		{"insert existing node", numbered.Insert(1), CodeInvalidParam},
+		{"dijkstra unknown start", undirected.Dijkstra("Z", "B"), CodeNodeNotFound},
+		{"dijkstra unknown end", undirected.Dijkstra("A", "Z"), CodeNodeNotFound},
+		{"node cost unknown end", undirected.DijkstraNodeCost("A", "Z"), CodeNodeNotFound},
+	}
+	for _, tt := range tests {
+		if tt.result.Success || tt.result.Code != tt.code {
+			t.Errorf("%s: success %v code %q, want failure with %q", tt.name, tt.result.Success, tt.result.Code, tt.code)
+		}
+	}
+}
+
+// issueCodes returns the codes of issues in order
+func issueCodes(issues []ValidationIssue) []string {
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestBuildGraphValidation(t *testing.T) {
+	ab := []GraphNodeInput{{ID: "A"}, {ID: "B"}}
+	tests := []struct {
+		name           string
+		nodes          []GraphNodeInput
+		edges          []GraphEdgeInput
+		allowSelfLoops bool
+		success        bool
+		codes          []string
+	}{
+		{"self loop", ab, []GraphEdgeInput{{From: "A", To: "A", Weight: 1}}, false, false, []string{"self_loop"}},
+		{"allowed self loop", ab, []GraphEdgeInput{{From: "A", To: "A", Weight: 1}}, true, true, []string{}},
+		{"negative weight warns", ab, []GraphEdgeInput{{From: "A", To: "B", Weight: -2}}, false, true, []string{"negative_weight"}},
+		{"unknown node", ab, []GraphEdgeInput{{From: "A", To: "C", Weight: 1}}, false, false, []string{"unknown_node"}},
+		{"duplicate node", []GraphNodeInput{{ID: "A"}, {ID: "A"}}, nil, false, false, []string{"duplicate_node"}},
+		{"empty node id", []GraphNodeInput{{ID: ""}}, nil, false, false, []string{"empty_node_id"}},
+		{"every issue reported", ab, []GraphEdgeInput{
+			{From: "B", To: "B", Weight: -1},
+			{From: "A", To: "C", Weight: 1},
+		}, false, false, []string{"self_loop", "negative_weight", "unknown_node"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGraph()
+			before := len(g.Nodes)
+			result := g.BuildGraph(tt.nodes, tt.edges, tt.allowSelfLoops, false, false)
+			if result.Success != tt.success {
+				t.Fatalf("success %v, want %v: %s", result.Success, tt.success, result.Message)
+			}
+			if codes := issueCodes(result.Issues); !slices.Equal(codes, tt.codes) {
+				t.Errorf("issues %v, want %v", codes, tt.codes)
+			}
+			if !tt.success {
+				if result.Code != CodeInvalidParam {
+					t.Errorf("code %q, want %q", result.Code, CodeInvalidParam)
+				}
+				if len(g.Nodes) != before {
+					t.Errorf("rejected build changed the graph to %d nodes", len(g.Nodes))
+				}
+			}
+		})
+	}
+}
+
+func TestAddEdgeRejectsSelfLoop(t *testing.T) {
+	g := buildGraph(t, false, GraphEdgeInput{From: "A", To: "B", Weight: 1})
+	if err := g.AddEdge("A", "A", 1); err == nil {
+		t.Error("self-loop accepted")
+	}
+	g.AllowSelfLoops = true
+	if err := g.AddEdge("A", "A", 1); err != nil {
+		t.Errorf("allowed self-loop rejected: %v", err)
+	}
+
+	// The loop is stored once, counted as one edge and touches A twice
+	loops := 0
+	for _, e := range g.Nodes["A"] {
+		if e.To == "A" {
+			loops++
+		}
+	}
+	if loops != 1 {
+		t.Errorf("self-loop stored %d times", loops)
+	}
+	if g.edgeCount() != 2 || g.degrees()["A"] != 3 {
+		t.Errorf("%d edges, degree of A %d, want 2 edges and degree 3", g.edgeCount(), g.degrees()["A"])
+	}
+	if edges := g.Export().Edges; len(edges) != 2 {
+		t.Errorf("exported %d edges, want 2", len(edges))
+	}
+}
+
+func TestDijkstraAllSampleGraph(t *testing.T) {
+	g := CreateSampleGraph()
+	g.AddNode("G", 700, 150)
+	result := g.DijkstraAll("A")
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+
+	// Computed by hand: A→C 2, C→B 1, B→D 5, D→E 2, E→F 3
+	want := map[string]float64{"A": 0, "B": 3, "C": 2, "D": 8, "E": 10, "F": 13, "G": UnreachableDistance}
+	if !maps.Equal(result.Distances, want) {
+		t.Errorf("distances %v, want %v", result.Distances, want)
+	}
+	wantPrevious := map[string]string{"B": "C", "C": "A", "D": "B", "E": "D", "F": "E"}
+	if !maps.Equal(result.Predecessors, wantPrevious) {
+		t.Errorf("predecessors %v, want %v", result.Predecessors, wantPrevious)
+	}
+
+	if missing := g.DijkstraAll("Z"); missing.Success || missing.Code != CodeNodeNotFound {
+		t.Errorf("unknown source: success %v code %q", missing.Success, missing.Code)
+	}
+}
+
+func TestNodeCostChangesShortestPath(t *testing.T) {
+	g := NewGraph()
+	nodes := []GraphNodeInput{{ID: "A"}, {ID: "B", Weight: 10}, {ID: "C"}, {ID: "D"}}
+	edges := []GraphEdgeInput{
+		{From: "A", To: "B", Weight: 1},
+		{From: "B", To: "D", Weight: 1},
+		{From: "A", To: "C", Weight: 2},
+		{From: "C", To: "D", Weight: 2},
+	}
+	if result := g.BuildGraph(nodes, edges, false, false, false); !result.Success {
+		t.Fatal(result.Message)
+	}
+
+	plain := g.Dijkstra("A", "D").Paths[0]
+	if !slices.Equal(plain.Nodes, []string{"A", "B", "D"}) || plain.Cost != 2 {
+		t.Errorf("dijkstra path %v cost %v, want A B D cost 2", plain.Nodes, plain.Cost)
+	}
+	weighted := g.DijkstraNodeCost("A", "D").Paths[0]
+	if !slices.Equal(weighted.Nodes, []string{"A", "C", "D"}) || weighted.Cost != 4 {
+		t.Errorf("node-cost path %v cost %v, want A C D cost 4", weighted.Nodes, weighted.Cost)
+	}
+}
+
+func TestDijkstraKeepsFractionalWeights(t *testing.T) {
+	// Rounded to integers A→C (1) would beat A→B→C (1+1)
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 0.6},
+		GraphEdgeInput{From: "B", To: "C", Weight: 0.6},
+		GraphEdgeInput{From: "A", To: "C", Weight: 1.4},
+	)
+	path := g.Dijkstra("A", "C").Paths[0]
+	if !slices.Equal(path.Nodes, []string{"A", "B", "C"}) || math.Abs(path.Cost-1.2) > pathEpsilon {
+		t.Errorf("path %v cost %v, want A B C cost 1.2", path.Nodes, path.Cost)
+	}
+}
+
+func TestDijkstraMarksEverySettledNode(t *testing.T) {
+	g := CreateSampleGraph()
+	for name, result := range map[string]OperationResult{
+		"dijkstra":     g.Dijkstra("A", "F"),
+		"dijkstra_all": g.DijkstraAll("A"),
+	} {
+		settled := make([]string, 0)
+		seen := make(map[string]bool)
+		for _, step := range result.Steps {
+			if step.Type != StepMarkVisited {
+				continue
+			}
+			// Exactly one node becomes visited with each mark step
+			marked := ""
+			for _, node := range step.GraphNodes {
+				if node.Visited && !seen[node.ID] {
+					if marked != "" {
+						t.Fatalf("%s: step %d marks both %s and %s", name, step.Index, marked, node.ID)
+					}
+					marked = node.ID
+				}
+			}
+			if marked == "" {
+				t.Fatalf("%s: step %d marks no new node", name, step.Index)
+			}
+			seen[marked] = true
+			settled = append(settled, marked)
+		}
+		// Settled in order of distance: A 0, C 2, B 3, D 8, E 10, F 13
+		if want := []string{"A", "C", "B", "D", "E", "F"}; !slices.Equal(settled, want) {
+			t.Errorf("%s: settled %v, want %v", name, settled, want)
+		}
+	}
+}
+
+func TestSCCComponents(t *testing.T) {
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "A", To: "B", Weight: 1},
+		GraphEdgeInput{From: "B", To: "C", Weight: 1},
+		GraphEdgeInput{From: "C", To: "A", Weight: 1},
+		GraphEdgeInput{From: "C", To: "D", Weight: 1},
+		GraphEdgeInput{From: "D", To: "E", Weight: 1},
+		GraphEdgeInput{From: "E", To: "D", Weight: 1},
+		GraphEdgeInput{From: "E", To: "F", Weight: 1},
+	)
+	result := g.SCC()
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+
+	component := make(map[string]int)
+	for _, n := range result.FinalGraph.Nodes {
+		if n.Component == nil {
+			t.Fatalf("node %s has no component", n.ID)
+		}
+		component[n.ID] = *n.Component
+	}
+	groups := [][]string{{"A", "B", "C"}, {"D", "E"}, {"F"}}
+	seen := make(map[int]bool)
+	for _, group := range groups {
+		c := component[group[0]]
+		if seen[c] {
+			t.Errorf("component %d shared across groups: %v", c, component)
+		}
+		seen[c] = true
+		for _, id := range group[1:] {
+			if component[id] != c {
+				t.Errorf("%s in component %d, want %d with %s", id, component[id], c, group[0])
+			}
+		}
+	}
+}
+
+// generatedDijkstra runs DijkstraAll over a generated 100-node graph and
+// returns the result with its JSON size
+func generatedDijkstra(tb testing.TB, snapshots bool) (OperationResult, int) {
+	tb.Helper()
+	g := NewGraph()
+	if result := g.GenerateGraph(100, 400, 1); !result.Success {
+		tb.Fatal(result.Message)
+	}
+	g.SetIncludeSnapshots(snapshots)
+	result := g.DijkstraAll("0")
+	payload, err := json.Marshal(result)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return result, len(payload)
+}
+
+func TestGraphDeltasShrinkThePayload(t *testing.T) {
+	full, fullSize := generatedDijkstra(t, true)
+	lean, leanSize := generatedDijkstra(t, false)
+	if len(lean.Steps) != len(full.Steps) {
+		t.Fatalf("%d steps without snapshots, %d with", len(lean.Steps), len(full.Steps))
+	}
+	for i, step := range lean.Steps {
+		if step.GraphNodes != nil || step.GraphDelta == nil || step.Type != full.Steps[i].Type {
+			t.Fatalf("step %d: %s with %d nodes and delta %v", i, step.Type, len(step.GraphNodes), step.GraphDelta)
+		}
+	}
+	if !maps.Equal(lean.Distances, full.Distances) {
+		t.Error("distances differ without snapshots")
+	}
+	if lean.FinalGraph == nil || len(lean.FinalGraph.Nodes) != 100 {
+		t.Error("the final graph snapshot is missing without snapshots")
+	}
+	if leanSize*10 > fullSize {
+		t.Errorf("payload %d bytes without snapshots, %d with; want under a tenth", leanSize, fullSize)
+	}
+}
+
+func BenchmarkDijkstraAllGenerated(b *testing.B) {
+	for _, snapshots := range []bool{true, false} {
+		name := "no_snapshots"
+		if snapshots {
+			name = "snapshots"
+		}
+		b.Run(name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				_, size = generatedDijkstra(b, snapshots)
+			}
+			b.ReportMetric(float64(size), "payload_bytes")
+		})
+	}
+}
+
+func TestWaypointsJoinDijkstraSegments(t *testing.T) {
+	g := CreateSampleGraph()
+	first, second := g.Dijkstra("A", "C"), g.Dijkstra("C", "F")
+	result := g.PathThroughWaypoints([]string{"A", "C", "F"})
+	if !result.Success || len(result.Paths) != 3 {
+		t.Fatalf("success %v with %d paths: %s", result.Success, len(result.Paths), result.Message)
+	}
+
+	route := append(slices.Clone(first.Paths[0].Nodes), second.Paths[0].Nodes[1:]...)
+	if got := result.Paths[0]; !slices.Equal(got.Nodes, route) || got.Cost != first.Paths[0].Cost+second.Paths[0].Cost {
+		t.Errorf("route %v cost %v, want %v cost %v", got.Nodes, got.Cost, route, first.Paths[0].Cost+second.Paths[0].Cost)
+	}
+	// A→C 2, then C→B→D→E→F 11
+	if result.Paths[0].Cost != 13 {
+		t.Errorf("cost %v, want 13", result.Paths[0].Cost)
+	}
+	if len(result.Steps) != len(first.Steps)+len(second.Steps)+1 {
+		t.Errorf("%d steps, want both segments and the summary", len(result.Steps))
+	}
+	for i, step := range result.Steps {
+		if step.Index != i {
+			t.Fatalf("step %d has index %d", i, step.Index)
+		}
+	}
+
+	g.AddNode("G", 700, 150)
+	if result := g.PathThroughWaypoints([]string{"A", "G"}); result.Success || result.Reason != ReasonUnreachable {
+		t.Errorf("unreachable waypoint: success %v reason %q", result.Success, result.Reason)
+	}
+}
+
+func TestRerouteAroundTheOptimalEdge(t *testing.T) {
+	g := CreateSampleGraph()
+	before, _ := g.ToAdjacencyMatrix()
+
+	// C-B lies on the shortest route A→C→B→D→E→F of cost 13
+	result := g.Reroute("A", "F", "C", "B")
+	if !result.Success || len(result.Paths) != 2 {
+		t.Fatalf("success %v with %d paths: %s", result.Success, len(result.Paths), result.Message)
+	}
+	original, rerouted := result.Paths[0], result.Paths[1]
+	if !slices.Equal(original.Nodes, []string{"A", "C", "B", "D", "E", "F"}) || original.Cost != 13 {
+		t.Errorf("original %v cost %v", original.Nodes, original.Cost)
+	}
+	if !slices.Equal(rerouted.Nodes, []string{"A", "B", "D", "E", "F"}) || rerouted.Cost != 14 {
+		t.Errorf("rerouted %v cost %v, want A B D E F cost 14", rerouted.Nodes, rerouted.Cost)
+	}
+	if result.Steps[0].Type != StepDelete {
+		t.Errorf("first step %s, want the removal", result.Steps[0].Type)
+	}
+	if after, _ := g.ToAdjacencyMatrix(); !reflect.DeepEqual(after, before) {
+		t.Error("the removed edge was not restored")
+	}
+
+	bridge := buildGraph(t, false, GraphEdgeInput{From: "A", To: "B", Weight: 1})
+	if result := bridge.Reroute("A", "B", "A", "B"); result.Success || result.Reason != ReasonUnreachable {
+		t.Errorf("removing a bridge: success %v reason %q", result.Success, result.Reason)
+	}
+	if !bridge.hasEdge("A", "B") || !bridge.hasEdge("B", "A") {
+		t.Error("the bridge was not restored")
+	}
+}
+
+func TestDuplicateEdgesMergeToTheLightest(t *testing.T) {
+	g := buildGraph(t, false,
+		GraphEdgeInput{From: "A", To: "B", Weight: 5},
+		GraphEdgeInput{From: "A", To: "B", Weight: 3},
+	)
+	g.AddEdge("B", "A", 4)
+	for _, ends := range [][2]string{{"A", "B"}, {"B", "A"}} {
+		if edges := g.Nodes[ends[0]]; len(edges) != 1 || edges[0] != (Edge{To: ends[1], Weight: 3}) {
+			t.Errorf("%s edges %v, want a single edge to %s of weight 3", ends[0], edges, ends[1])
+		}
+	}
+	// Undirected snapshots list an edge once from each end
+	if _, edges := g.buildSnapshot(nil, nil, nil, nil); len(edges) != 2 || edges[0].Weight != 3 || edges[1].Weight != 3 {
+		t.Errorf("snapshot edges %+v, want A-B of weight 3 from both ends", edges)
+	}
+
+	g.AllowParallelEdges = true
+	g.AddEdge("A", "B", 7)
+	if len(g.Nodes["A"]) != 2 || len(g.Nodes["B"]) != 2 {
+		t.Errorf("parallel edges were merged: %v", g.Nodes)
+	}
+}
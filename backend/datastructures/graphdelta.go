@@ -0,0 +1,43 @@
+package datastructures
+
+import "sort"
+
+// GraphDelta is the lightweight form of a graph step recorded when full
+// snapshots are disabled. It lists only what changed since the previous
+// step of the same operation, plus the current highlights.
+type GraphDelta struct {
+	// Distances holds the nodes whose tentative distance changed
+	Distances map[string]float64 `json:"distances,omitempty"`
+	// Visited lists the nodes that became visited
+	Visited []string `json:"visited,omitempty"`
+	// Path is the highlighted path of the step
+	Path []string `json:"path,omitempty"`
+	// Edge is the edge the step works on
+	Edge *[2]string `json:"edge,omitempty"`
+}
+
+// delta compares a new snapshot with the latest one
+func (g *Graph) delta(nodes []GraphNodeSnapshot, path []string, currentEdge *[2]string) *GraphDelta {
+	previous := make(map[string]GraphNodeSnapshot)
+	if g.latest != nil {
+		for _, node := range g.latest.Nodes {
+			previous[node.ID] = node
+		}
+	}
+
+	d := &GraphDelta{Path: path, Edge: currentEdge}
+	for _, node := range nodes {
+		before, seen := previous[node.ID]
+		if node.Distance != nil && (!seen || before.Distance == nil || *before.Distance != *node.Distance) {
+			if d.Distances == nil {
+				d.Distances = make(map[string]float64)
+			}
+			d.Distances[node.ID] = *node.Distance
+		}
+		if node.Visited && !before.Visited {
+			d.Visited = append(d.Visited, node.ID)
+		}
+	}
+	sort.Strings(d.Visited)
+	return d
+}
@@ -0,0 +1,276 @@
+package datastructures
+
+import (
+	"context"
+	"time"
+)
+
+// BinaryHeap is an array-backed binary min-heap with step tracking. Snapshot
+// nodes are the array slots: node i has children 2i+1 and 2i+2, and its ID
+// is its index, so IDs follow positions rather than values.
+type BinaryHeap struct {
+	Items []int
+	steps []Step
+
+	// started is when the current operation began, the origin of its step
+	// timestamps
+	started time.Time
+
+	// layout is the canvas size snapshots are laid out for
+	layout TreeLayout
+
+	// includeSnapshots controls whether every step embeds a full TreeState
+	includeSnapshots bool
+
+	// operand is the value the current operation works on
+	operand *int
+
+	// locale selects the language of step descriptions and messages
+	locale Locale
+
+	// ctx aborts long-running operations when canceled
+	ctx context.Context
+}
+
+// NewBinaryHeap creates an empty binary heap
+func NewBinaryHeap() *BinaryHeap {
+	return &BinaryHeap{
+		Items:            make([]int, 0),
+		steps:            make([]Step, 0),
+		includeSnapshots: true,
+		layout:           newTreeLayout(0, 0),
+	}
+}
+
+// SetIncludeSnapshots toggles per-step tree snapshots. The final tree
+// snapshot of an operation is always produced.
+func (h *BinaryHeap) SetIncludeSnapshots(include bool) {
+	h.includeSnapshots = include
+}
+
+// SetLayout sets the canvas width and the distance between levels of the
+// snapshots. Non-positive values restore the defaults.
+func (h *BinaryHeap) SetLayout(width, levelHeight float64) {
+	h.layout = newTreeLayout(width, levelHeight)
+}
+
+// SetLocale selects the language of step descriptions and messages
+func (h *BinaryHeap) SetLocale(locale Locale) {
+	h.locale = locale
+}
+
+func (h *BinaryHeap) msg(key string, args ...interface{}) string {
+	return Localize(h.locale, key, args...)
+}
+
+func (h *BinaryHeap) clearSteps() {
+	h.steps = make([]Step, 0)
+	h.started = time.Now()
+	h.operand = nil
+}
+
+// newResult builds an OperationResult from the steps recorded so far
+func (h *BinaryHeap) newResult(success bool, message string) OperationResult {
+	return OperationResult{
+		Success:   success,
+		Message:   message,
+		Steps:     h.steps,
+		FinalTree: h.getTreeSnapshot(),
+	}
+}
+
+func (h *BinaryHeap) addStep(stepType StepType, desc string, nodeID *int, highlights ...int) {
+	step := Step{
+		Type:        stepType,
+		Description: desc,
+		NodeID:      nodeID,
+		Value:       h.operand,
+		Highlight:   highlights,
+	}
+	if h.includeSnapshots {
+		step.TreeState = h.getTreeSnapshot()
+	}
+	step.stamp(len(h.steps), h.started)
+	h.steps = append(h.steps, step)
+}
+
+func (h *BinaryHeap) getTreeSnapshot() []TreeNodeSnapshot {
+	var nodes []TreeNodeSnapshot
+	h.snapshot(0, &nodes, 0, 0, h.layout.Width)
+	return nodes
+}
+
+// snapshot lays out slot i in [xMin, xMax] in pre-order, like the binary
+// search trees
+func (h *BinaryHeap) snapshot(i int, nodes *[]TreeNodeSnapshot, depth int, xMin, xMax float64) {
+	if i >= len(h.Items) {
+		return
+	}
+
+	x := (xMin + xMax) / 2
+	s := TreeNodeSnapshot{
+		ID:    i,
+		Value: h.Items[i],
+		X:     x,
+		Y:     h.layout.levelY(depth),
+	}
+	if left := 2*i + 1; left < len(h.Items) {
+		s.LeftID = &left
+	}
+	if right := 2*i + 2; right < len(h.Items) {
+		s.RightID = &right
+	}
+	if i > 0 {
+		parent := (i - 1) / 2
+		s.ParentID = &parent
+	}
+	*nodes = append(*nodes, s)
+
+	h.snapshot(2*i+1, nodes, depth+1, xMin, x)
+	h.snapshot(2*i+2, nodes, depth+1, x, xMax)
+}
+
+// swap exchanges the values of slots i and j and records it
+func (h *BinaryHeap) swap(i, j int) {
+	h.Items[i], h.Items[j] = h.Items[j], h.Items[i]
+	h.addStep(StepSwap, h.msg("heap.swap", h.Items[j], h.Items[i]), &i, i, j)
+}
+
+// siftUp moves the value at slot i up until its parent is not larger
+func (h *BinaryHeap) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		h.addStep(StepCompare, h.msg("heap.compare_parent", h.Items[i], h.Items[parent]), &i, i, parent)
+		if h.Items[parent] <= h.Items[i] {
+			break
+		}
+		h.swap(parent, i)
+		i = parent
+	}
+	h.addStep(StepVisit, h.msg("heap.settled", h.Items[i]), &i, i)
+}
+
+// siftDown moves the value at slot i down while a child is smaller
+func (h *BinaryHeap) siftDown(i int) {
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(h.Items) {
+				h.addStep(StepCompare, h.msg("heap.compare_child", h.Items[child], h.Items[smallest]), &child, child, smallest)
+				if h.Items[child] < h.Items[smallest] {
+					smallest = child
+				}
+			}
+		}
+		if smallest == i {
+			break
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+	h.addStep(StepVisit, h.msg("heap.settled", h.Items[i]), &i, i)
+}
+
+// Insert appends value to the heap and sifts it up
+func (h *BinaryHeap) Insert(value int) OperationResult {
+	h.clearSteps()
+	h.operand = &value
+
+	h.Items = append(h.Items, value)
+	last := len(h.Items) - 1
+	h.addStep(StepInsert, h.msg("heap.insert.append", value, last), &last, last)
+	h.siftUp(last)
+
+	h.addStep(StepComplete, h.msg("tree.insert.done"), nil)
+	return h.newResult(true, "")
+}
+
+// extractMin removes the root, moves the last value into its place and
+// sifts it down. The heap must not be empty.
+func (h *BinaryHeap) extractMin() int {
+	root := 0
+	min := h.Items[0]
+	h.addStep(StepDelete, h.msg("heap.extract.min", min), &root, root)
+
+	last := len(h.Items) - 1
+	h.Items[0] = h.Items[last]
+	h.Items = h.Items[:last]
+	if len(h.Items) > 0 {
+		h.addStep(StepVisit, h.msg("heap.extract.move_last", h.Items[0]), &root, root)
+		h.siftDown(0)
+	}
+	return min
+}
+
+// ExtractMin removes and returns the smallest value of the heap
+func (h *BinaryHeap) ExtractMin() OperationResult {
+	h.clearSteps()
+
+	if len(h.Items) == 0 {
+		h.addStep(StepNotFound, h.msg("heap.empty"), nil)
+		result := h.newResult(false, h.msg("heap.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	min := h.extractMin()
+	h.addStep(StepComplete, h.msg("heap.extract.done", min), nil)
+	result := h.newResult(true, h.msg("heap.extract.success", min))
+	result.Values = []int{min}
+	return result
+}
+
+// buildHeap turns Items into a heap in place by sifting down every internal
+// slot, last one first
+func (h *BinaryHeap) buildHeap() {
+	h.addStep(StepInsert, h.msg("heap.build.start", len(h.Items)), nil)
+	for i := len(h.Items)/2 - 1; i >= 0; i-- {
+		h.siftDown(i)
+	}
+	h.addStep(StepComplete, h.msg("heap.build.done"), nil)
+}
+
+// HeapSort sorts values by building a heap from them and extracting the
+// minimum until it is empty. The steps show the scratch heap; the heap's
+// own contents are left unchanged. At most MaxArraySize values are sorted.
+func (h *BinaryHeap) HeapSort(values []int) OperationResult {
+	h.clearSteps()
+	if len(values) > MaxArraySize {
+		return OperationResult{
+			Success: false,
+			Message: h.msg("heap.sort.too_large", MaxArraySize),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}
+	}
+
+	saved := h.Items
+	h.Items = append([]int(nil), values...)
+
+	h.buildHeap()
+	sorted := make([]int, 0, len(values))
+	for len(h.Items) > 0 {
+		if contextDone(h.ctx) {
+			h.Items = saved
+			return h.canceledResult()
+		}
+		sorted = append(sorted, h.extractMin())
+	}
+
+	h.Items = saved
+	h.addStep(StepComplete, h.msg("heap.sort.done", sorted), nil)
+	result := h.newResult(true, h.msg("heap.sort.success", len(sorted)))
+	result.Values = sorted
+	return result
+}
+
+// State returns the current heap without recording steps
+func (h *BinaryHeap) State() OperationResult {
+	return OperationResult{
+		Success:   true,
+		Message:   h.msg("heap.state", len(h.Items)),
+		Steps:     []Step{},
+		FinalTree: h.getTreeSnapshot(),
+	}
+}
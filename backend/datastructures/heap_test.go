@@ -0,0 +1,59 @@
+package datastructures
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHeapSort(t *testing.T) {
+	h := NewBinaryHeap()
+	h.Insert(4)
+	values := []int{9, 3, 7, 1, 8, 2, 6, 5}
+	result := h.HeapSort(values)
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+	if want := []int{1, 2, 3, 5, 6, 7, 8, 9}; !slices.Equal(result.Values, want) {
+		t.Errorf("sorted %v, want %v", result.Values, want)
+	}
+	if !slices.Equal(h.Items, []int{4}) {
+		t.Errorf("heap contents changed to %v", h.Items)
+	}
+
+	// The build phase comes first, then one extraction per value
+	buildDone := Localize(LocaleZh, "heap.build.done")
+	end := slices.IndexFunc(result.Steps, func(s Step) bool { return s.Description == buildDone })
+	if end < 0 || result.Steps[0].Type != StepInsert {
+		t.Fatalf("steps do not open with the build phase (build ends at %d)", end)
+	}
+	extractions := 0
+	for i, step := range result.Steps {
+		if step.Type != StepDelete {
+			continue
+		}
+		if i < end {
+			t.Fatalf("extraction at step %d, before the heap was built", i)
+		}
+		extractions++
+	}
+	if extractions != len(values) {
+		t.Errorf("%d extractions, want %d", extractions, len(values))
+	}
+}
+
+func TestHeapSortLimits(t *testing.T) {
+	h := NewBinaryHeap()
+	if result := h.HeapSort(make([]int, MaxArraySize+1)); result.Success || result.Code != CodeInvalidParam {
+		t.Errorf("oversized input: success %v code %q", result.Success, result.Code)
+	}
+
+	h.Insert(4)
+	h.SetContext(cancelAfter(3))
+	result := h.HeapSort([]int{9, 3, 7, 1, 8, 2, 6, 5})
+	if result.Success || result.Reason != ReasonCanceled {
+		t.Fatalf("success %v reason %q, want canceled", result.Success, result.Reason)
+	}
+	if !slices.Equal(h.Items, []int{4}) {
+		t.Errorf("canceled sort left the heap as %v", h.Items)
+	}
+}
@@ -0,0 +1,323 @@
+package datastructures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale selects the language of step descriptions and result messages
+type Locale string
+
+const (
+	LocaleZh Locale = "zh"
+	LocaleEn Locale = "en"
+)
+
+// ParseLocale maps a language tag such as "en" or "en-US" to a supported
+// Locale. Anything unrecognized falls back to Chinese.
+func ParseLocale(tag string) Locale {
+	if strings.HasPrefix(strings.ToLower(tag), "en") {
+		return LocaleEn
+	}
+	return LocaleZh
+}
+
+// messages maps a message key to its format string in each locale
+var messages = map[string]map[Locale]string{
+	// Shared operation messages
+	"op.canceled": {LocaleZh: "操作已取消或超时", LocaleEn: "The operation was canceled or timed out"},
+
+	// Shared binary search tree messages
+	"tree.rotate_right":          {LocaleZh: "对节点 %d 进行右旋", LocaleEn: "Rotate right at node %d"},
+	"tree.rotate_left":           {LocaleZh: "对节点 %d 进行左旋", LocaleEn: "Rotate left at node %d"},
+	"tree.compare":               {LocaleZh: "比较 %d 与节点 %d", LocaleEn: "Compare %d with node %d"},
+	"tree.insert.start":          {LocaleZh: "开始插入值 %d", LocaleEn: "Start inserting value %d"},
+	"tree.insert.done":           {LocaleZh: "插入完成", LocaleEn: "Insertion complete"},
+	"tree.empty":                 {LocaleZh: "树为空", LocaleEn: "The tree is empty"},
+	"tree.search.empty":          {LocaleZh: "树为空，没有可查找的节点", LocaleEn: "The tree is empty, there is nothing to search"},
+	"tree.search.found_node":     {LocaleZh: "找到节点 %d", LocaleEn: "Found node %d"},
+	"tree.search.found":          {LocaleZh: "找到值 %d", LocaleEn: "Found value %d"},
+	"tree.search.missing_step":   {LocaleZh: "值 %d 不存在于树中", LocaleEn: "Value %d is not in the tree"},
+	"tree.search.missing":        {LocaleZh: "值 %d 不存在", LocaleEn: "Value %d does not exist"},
+	"tree.search_all.match":      {LocaleZh: "节点值等于 %d，这是第 %d 个匹配", LocaleEn: "The node holds %d, match #%d"},
+	"tree.search_all.success":    {LocaleZh: "找到 %d 个值为 %d 的节点", LocaleEn: "Found %d nodes holding %d"},
+	"tree.next.min_step":         {LocaleZh: "向左寻找最小值: 节点 %d", LocaleEn: "Go left toward the minimum: node %d"},
+	"tree.next.first":            {LocaleZh: "中序遍历的第一个值: %d", LocaleEn: "The first value in order is %d"},
+	"tree.next.found":            {LocaleZh: "%d 的中序后继是 %d", LocaleEn: "The in-order successor of %d is %d"},
+	"tree.next.done":             {LocaleZh: "%d 之后没有更大的值，中序遍历结束", LocaleEn: "No value follows %d, the in-order walk is done"},
+	"tree.delete.start":          {LocaleZh: "开始删除值 %d", LocaleEn: "Start deleting value %d"},
+	"tree.delete.found":          {LocaleZh: "找到要删除的节点 %d", LocaleEn: "Found node %d to delete"},
+	"tree.delete.no_left":        {LocaleZh: "节点 %d 没有左子节点，用右子节点替换", LocaleEn: "Node %d has no left child, replace it with its right child"},
+	"tree.delete.no_right":       {LocaleZh: "节点 %d 没有右子节点，用左子节点替换", LocaleEn: "Node %d has no right child, replace it with its left child"},
+	"tree.delete.successor":      {LocaleZh: "节点 %d 有两个子节点，找到后继节点 %d", LocaleEn: "Node %d has two children, found successor %d"},
+	"tree.delete.replace":        {LocaleZh: "用后继节点 %d 替换被删除节点", LocaleEn: "Replace the deleted node with successor %d"},
+	"tree.delete.missing_step":   {LocaleZh: "值 %d 不存在于树中，无法删除", LocaleEn: "Value %d is not in the tree, nothing to delete"},
+	"tree.delete.missing":        {LocaleZh: "值 %d 不存在，无法删除", LocaleEn: "Value %d does not exist, cannot delete"},
+	"tree.delete.done":           {LocaleZh: "删除节点 %d 完成", LocaleEn: "Deletion of node %d complete"},
+	"tree.path.found":            {LocaleZh: "从根到值 %d 的路径共 %d 个节点", LocaleEn: "The path from the root to value %d has %d nodes"},
+	"tree.path.missing":          {LocaleZh: "值 %d 不存在于树中，高亮已搜索的路径", LocaleEn: "Value %d is not in the tree, highlighting the searched path"},
+	"tree.diameter.visit":        {LocaleZh: "后序访问节点 %d: 左子树深度 %d，右子树深度 %d", LocaleEn: "Post-order visit of node %d: left depth %d, right depth %d"},
+	"tree.diameter.best":         {LocaleZh: "经过节点 %d 的路径更长，当前直径: %d", LocaleEn: "The path through node %d is longer, diameter so far: %d"},
+	"tree.diameter.found":        {LocaleZh: "直径为 %d 条边 (%d 个节点)", LocaleEn: "The diameter is %d edges (%d nodes)"},
+	"tree.diameter.success":      {LocaleZh: "树的直径: %d", LocaleEn: "Tree diameter: %d"},
+	"tree.depth.compare":         {LocaleZh: "比较 %d 与节点 %d (深度 %d)", LocaleEn: "Compare %d with node %d (depth %d)"},
+	"tree.depth.found":           {LocaleZh: "找到值 %d，深度为 %d", LocaleEn: "Found value %d at depth %d"},
+	"tree.depth.success":         {LocaleZh: "值 %d 的深度: %d", LocaleEn: "Depth of value %d: %d"},
+	"tree.lca.compare":           {LocaleZh: "比较区间 [%d, %d] 与节点 %d", LocaleEn: "Compare range [%d, %d] with node %d"},
+	"tree.lca.found":             {LocaleZh: "%d 与 %d 在节点 %d 处分叉", LocaleEn: "%d and %d split at node %d"},
+	"tree.lca.success":           {LocaleZh: "%d 与 %d 的最近公共祖先: %d", LocaleEn: "Lowest common ancestor of %d and %d: %d"},
+	"tree.lca.missing":           {LocaleZh: "值 %v 不存在", LocaleEn: "Values not in the tree: %v"},
+	"tree.node.found":            {LocaleZh: "节点 %d 的值为 %d", LocaleEn: "Node %d holds value %d"},
+	"tree.node.missing":          {LocaleZh: "不存在 ID 为 %d 的节点", LocaleEn: "No node has ID %d"},
+	"tree.levelorder.visit":      {LocaleZh: "访问节点 %d (第 %d 层)", LocaleEn: "Visit node %d (level %d)"},
+	"tree.levelorder.done":       {LocaleZh: "层序遍历结果: %v", LocaleEn: "Level-order traversal: %v"},
+	"tree.levelorder.success":    {LocaleZh: "层序遍历完成，共访问 %d 个节点", LocaleEn: "Level-order traversal visited %d nodes"},
+	"tree.check_bst.compare":     {LocaleZh: "比较中序相邻的 %d 与 %d", LocaleEn: "Compare in-order neighbors %d and %d"},
+	"tree.check_bst.ok":          {LocaleZh: "%d 个节点满足二叉搜索树顺序", LocaleEn: "All %d nodes are in binary search order"},
+	"tree.check_bst.left":        {LocaleZh: "顺序错误: 值 %d 位于节点 %d 的左子树中", LocaleEn: "Order violation: value %d is in the left subtree of node %d"},
+	"tree.check_bst.right":       {LocaleZh: "顺序错误: 值 %d 位于节点 %d 的右子树中", LocaleEn: "Order violation: value %d is in the right subtree of node %d"},
+	"tree.morris.visit":          {LocaleZh: "访问节点 %d", LocaleEn: "Visit node %d"},
+	"tree.morris.thread":         {LocaleZh: "创建线索: 前驱 %d 的右指针指向 %d", LocaleEn: "Create thread: right pointer of predecessor %d points to %d"},
+	"tree.morris.unthread":       {LocaleZh: "沿线索返回，删除 %d → %d 的线索", LocaleEn: "Return through the thread and remove %d → %d"},
+	"tree.morris.done":           {LocaleZh: "Morris 中序遍历结果: %v", LocaleEn: "Morris in-order traversal: %v"},
+	"tree.morris.success":        {LocaleZh: "Morris 中序遍历完成，共访问 %d 个节点，所有线索已移除", LocaleEn: "Morris in-order traversal visited %d nodes, all threads removed"},
+	"tree.fixup.start":           {LocaleZh: "开始修复", LocaleEn: "Fixup begins"},
+	"tree.fixup.done":            {LocaleZh: "修复完成", LocaleEn: "Fixup complete"},
+	"rb.invariant.red_red":       {LocaleZh: "恢复性质: 红色节点没有红色子节点，根节点为黑色", LocaleEn: "Restoring: no red node has a red child, and the root is black"},
+	"rb.invariant.black_height":  {LocaleZh: "恢复性质: 每条到叶子的路径上黑色节点数相同", LocaleEn: "Restoring: every path to a leaf has the same number of black nodes"},
+	"avl.invariant.balance":      {LocaleZh: "恢复性质: 每个节点左右子树高度差不超过 1", LocaleEn: "Restoring: the subtrees of every node differ in height by at most 1"},
+	"tree.state":                 {LocaleZh: "当前树共有 %d 个节点", LocaleEn: "The tree currently has %d nodes"},
+	"tree.export_nested.success": {LocaleZh: "已导出嵌套结构的树，共 %d 个节点", LocaleEn: "Exported the tree as a nested structure with %d nodes"},
+	"tree.values":                {LocaleZh: "树中共有 %d 个值（升序）", LocaleEn: "The tree holds %d values in ascending order"},
+	"tree.delete.success":        {LocaleZh: "成功删除值 %d", LocaleEn: "Deleted value %d"},
+
+	// 2-3-4 Tree
+	"t234.insert.root": {LocaleZh: "树为空，创建根节点 [%d]", LocaleEn: "The tree is empty, create root node [%d]"},
+	"t234.insert.leaf": {LocaleZh: "将 %d 插入叶子节点，该节点成为 %d-节点", LocaleEn: "Insert %d into the leaf, which becomes a %d-node"},
+	"t234.compare":     {LocaleZh: "比较 %d 与节点 %v", LocaleEn: "Compare %d with node %v"},
+	"t234.split":       {LocaleZh: "分裂 4-节点 %v，将中间键 %d 提升到父节点", LocaleEn: "Split 4-node %v and move middle key %d up to the parent"},
+	"t234.split_root":  {LocaleZh: "根节点是 4-节点，先创建新根再分裂", LocaleEn: "The root is a 4-node, create a new root before splitting it"},
+
+	// Binary Heap
+	"heap.insert.append":     {LocaleZh: "将 %d 追加到堆末尾 (下标 %d)", LocaleEn: "Append %d at the end of the heap (index %d)"},
+	"heap.compare_parent":    {LocaleZh: "比较 %d 与父节点 %d", LocaleEn: "Compare %d with its parent %d"},
+	"heap.compare_child":     {LocaleZh: "比较子节点 %d 与当前最小值 %d", LocaleEn: "Compare child %d with the current minimum %d"},
+	"heap.swap":              {LocaleZh: "交换 %d 与 %d", LocaleEn: "Swap %d and %d"},
+	"heap.settled":           {LocaleZh: "%d 已就位", LocaleEn: "%d is in place"},
+	"heap.empty":             {LocaleZh: "堆为空", LocaleEn: "The heap is empty"},
+	"heap.extract.min":       {LocaleZh: "取出堆顶最小值 %d", LocaleEn: "Remove the minimum %d from the top"},
+	"heap.extract.move_last": {LocaleZh: "将末尾元素 %d 移到堆顶并下沉", LocaleEn: "Move the last value %d to the top and sift it down"},
+	"heap.extract.done":      {LocaleZh: "取出完成: %d", LocaleEn: "Extraction complete: %d"},
+	"heap.extract.success":   {LocaleZh: "取出最小值 %d", LocaleEn: "Extracted the minimum %d"},
+	"heap.build.start":       {LocaleZh: "由 %d 个值建堆，从最后一个内部节点开始下沉", LocaleEn: "Build a heap from %d values, sifting down from the last internal node"},
+	"heap.build.done":        {LocaleZh: "建堆完成", LocaleEn: "Heap built"},
+	"heap.sort.done":         {LocaleZh: "堆排序结果: %v", LocaleEn: "Heap sort result: %v"},
+	"heap.sort.too_large":    {LocaleZh: "堆排序最多包含 %d 个值", LocaleEn: "Heap sort takes at most %d values"},
+	"heap.sort.success":      {LocaleZh: "堆排序完成，共 %d 个值", LocaleEn: "Heap sort finished, %d values sorted"},
+	"heap.state":             {LocaleZh: "堆中共有 %d 个元素", LocaleEn: "The heap holds %d values"},
+
+	// Array sorting
+	"array.too_large":     {LocaleZh: "数组最多包含 %d 个值", LocaleEn: "Arrays hold at most %d values"},
+	"array.start":         {LocaleZh: "开始排序 %d 个值", LocaleEn: "Start sorting %d values"},
+	"array.swap":          {LocaleZh: "交换下标 %d 与 %d 的值", LocaleEn: "Swap the values at indices %d and %d"},
+	"array.quick.pivot":   {LocaleZh: "选择 %d (下标 %d) 作为区间 [%d, %d] 的基准", LocaleEn: "Pick %d (index %d) as the pivot of range [%d, %d]"},
+	"array.quick.compare": {LocaleZh: "比较 %d 与基准 %d", LocaleEn: "Compare %d with pivot %d"},
+	"array.quick.placed":  {LocaleZh: "基准 %d 已放到最终位置 %d", LocaleEn: "Pivot %d is in its final position %d"},
+	"array.merge.split":   {LocaleZh: "拆分为 [%d, %d] 与 [%d, %d]", LocaleEn: "Split into [%d, %d] and [%d, %d]"},
+	"array.merge.compare": {LocaleZh: "比较 %d 与 %d", LocaleEn: "Compare %d with %d"},
+	"array.merge.write":   {LocaleZh: "将 %d 写入下标 %d", LocaleEn: "Write %d to index %d"},
+	"array.merge.merged":  {LocaleZh: "区间 [%d, %d] 合并完成", LocaleEn: "Range [%d, %d] merged"},
+	"array.done":          {LocaleZh: "排序结果: %v", LocaleEn: "Sorted result: %v"},
+	"array.success":       {LocaleZh: "%s 完成，共 %d 个值", LocaleEn: "%s finished, %d values sorted"},
+
+	// AVL Tree
+	"avl.insert.node":        {LocaleZh: "插入节点 %d", LocaleEn: "Insert node %d"},
+	"avl.case.ll":            {LocaleZh: "LL情况：需要右旋", LocaleEn: "LL case: rotate right"},
+	"avl.case.rr":            {LocaleZh: "RR情况：需要左旋", LocaleEn: "RR case: rotate left"},
+	"avl.case.lr":            {LocaleZh: "LR情况：先左旋后右旋", LocaleEn: "LR case: rotate left, then right"},
+	"avl.build.duplicate":    {LocaleZh: "值 %d 重复，无法构建平衡二叉搜索树", LocaleEn: "Value %d is duplicated, cannot build a balanced BST"},
+	"avl.build.start":        {LocaleZh: "排序后的输入: %v", LocaleEn: "Sorted input: %v"},
+	"avl.build.root":         {LocaleZh: "选择中位数 %d 作为区间 %v 的子树根", LocaleEn: "Choose median %d as the subtree root of range %v"},
+	"avl.build.done":         {LocaleZh: "平衡二叉搜索树构建完成", LocaleEn: "Balanced BST construction complete"},
+	"avl.balance.ok":         {LocaleZh: "%d 个节点的平衡因子均在 [-1, 1] 内", LocaleEn: "All %d nodes have balance factors within [-1, 1]"},
+	"avl.balance.violations": {LocaleZh: "%d 个节点违反 AVL 平衡条件", LocaleEn: "%d nodes violate the AVL balance invariant"},
+	"avl.build.success":      {LocaleZh: "由 %d 个值构建平衡二叉搜索树，高度为 %d", LocaleEn: "Built a balanced BST from %d values with height %d"},
+	"avl.case.rl":            {LocaleZh: "RL情况：先右旋后左旋", LocaleEn: "RL case: rotate right, then left"},
+
+	// Red-Black Tree
+	"rb.insert.create":               {LocaleZh: "创建新节点 %d (红色)", LocaleEn: "Create new node %d (red)"},
+	"rb.insert.root":                 {LocaleZh: "节点 %d 成为根节点", LocaleEn: "Node %d becomes the root"},
+	"rb.insert.left":                 {LocaleZh: "节点 %d 作为 %d 的左子节点", LocaleEn: "Node %d becomes the left child of %d"},
+	"rb.insert.right":                {LocaleZh: "节点 %d 作为 %d 的右子节点", LocaleEn: "Node %d becomes the right child of %d"},
+	"rb.insert.case1":                {LocaleZh: "情况1: 叔节点为红色，重新着色", LocaleEn: "Case 1: uncle is red, recolor"},
+	"rb.insert.case1_mirror":         {LocaleZh: "情况1(镜像): 叔节点为红色，重新着色", LocaleEn: "Case 1 (mirror): uncle is red, recolor"},
+	"rb.insert.case1_recolor":        {LocaleZh: "节点 %d, %d 变黑，%d 变红", LocaleEn: "Nodes %d, %d turn black, %d turns red"},
+	"rb.insert.case2":                {LocaleZh: "情况2: 叔节点为黑色，当前节点是右子节点", LocaleEn: "Case 2: uncle is black, current node is a right child"},
+	"rb.insert.case2_mirror":         {LocaleZh: "情况2(镜像): 叔节点为黑色，当前节点是左子节点", LocaleEn: "Case 2 (mirror): uncle is black, current node is a left child"},
+	"rb.insert.case3":                {LocaleZh: "情况3: 叔节点为黑色，当前节点是左子节点", LocaleEn: "Case 3: uncle is black, current node is a left child"},
+	"rb.insert.case3_mirror":         {LocaleZh: "情况3(镜像): 叔节点为黑色，当前节点是右子节点", LocaleEn: "Case 3 (mirror): uncle is black, current node is a right child"},
+	"rb.insert.case3_recolor":        {LocaleZh: "节点 %d 变黑，%d 变红", LocaleEn: "Node %d turns black, %d turns red"},
+	"rb.insert.root_black":           {LocaleZh: "根节点变黑", LocaleEn: "The root turns black"},
+	"rb.delete.fixup":                {LocaleZh: "删除了黑色节点，开始修复红黑树性质", LocaleEn: "A black node was removed, start restoring the Red-Black properties"},
+	"rb.delete.case1":                {LocaleZh: "情况1: 兄弟节点为红色", LocaleEn: "Case 1: sibling is red"},
+	"rb.delete.case1_mirror":         {LocaleZh: "情况1(镜像): 兄弟节点为红色", LocaleEn: "Case 1 (mirror): sibling is red"},
+	"rb.delete.case1_recolor":        {LocaleZh: "兄弟节点 %d 变黑，父节点 %d 变红", LocaleEn: "Sibling %d turns black, parent %d turns red"},
+	"rb.delete.case2":                {LocaleZh: "情况2: 兄弟节点为黑色，其两个子节点均为黑色", LocaleEn: "Case 2: sibling is black with two black children"},
+	"rb.delete.case2_mirror":         {LocaleZh: "情况2(镜像): 兄弟节点为黑色，其两个子节点均为黑色", LocaleEn: "Case 2 (mirror): sibling is black with two black children"},
+	"rb.delete.case2_recolor":        {LocaleZh: "兄弟节点 %d 变红", LocaleEn: "Sibling %d turns red"},
+	"rb.delete.case3":                {LocaleZh: "情况3: 兄弟节点为黑色，左子为红，右子为黑", LocaleEn: "Case 3: sibling is black, its left child is red and right child black"},
+	"rb.delete.case3_mirror":         {LocaleZh: "情况3(镜像): 兄弟节点为黑色，右子为红，左子为黑", LocaleEn: "Case 3 (mirror): sibling is black, its right child is red and left child black"},
+	"rb.delete.case3_recolor":        {LocaleZh: "兄弟左子节点变黑，兄弟 %d 变红", LocaleEn: "Sibling's left child turns black, sibling %d turns red"},
+	"rb.delete.case3_mirror_recolor": {LocaleZh: "兄弟右子节点变黑，兄弟 %d 变红", LocaleEn: "Sibling's right child turns black, sibling %d turns red"},
+	"rb.delete.case4":                {LocaleZh: "情况4: 兄弟节点为黑色，右子为红色", LocaleEn: "Case 4: sibling is black with a red right child"},
+	"rb.delete.case4_mirror":         {LocaleZh: "情况4(镜像): 兄弟节点为黑色，左子为红色", LocaleEn: "Case 4 (mirror): sibling is black with a red left child"},
+	"rb.delete.case4_recolor":        {LocaleZh: "重新着色完成", LocaleEn: "Recoloring complete"},
+	"rb.delete.finish":               {LocaleZh: "将当前节点变黑以完成修复", LocaleEn: "Turn the current node black to finish the fix-up"},
+	"rb.bulk_delete.start":           {LocaleZh: "开始批量删除 %d 个值", LocaleEn: "Start deleting %d values"},
+	"rb.bulk_delete.skip":            {LocaleZh: "值 %d 不存在于树中，跳过", LocaleEn: "Value %d is not in the tree, skipped"},
+	"rb.bulk_delete.done":            {LocaleZh: "批量删除完成：删除 %d 个，%d 个不存在", LocaleEn: "Bulk deletion complete: %d deleted, %d missing"},
+	"rb.bulk_delete.success":         {LocaleZh: "成功删除 %d 个值", LocaleEn: "Deleted %d values"},
+	"rb.balance.ok":                  {LocaleZh: "红黑性质成立，根的黑高为 %d", LocaleEn: "Red-Black properties hold, the root's black-height is %d"},
+	"rb.balance.violations":          {LocaleZh: "%d 个节点违反红黑性质", LocaleEn: "%d nodes violate the Red-Black properties"},
+	"rb.black_height.tree":           {LocaleZh: "沿最左路径统计黑色节点，黑高为 %d", LocaleEn: "Counting black nodes down the leftmost path, the black-height is %d"},
+	"rb.black_height.success":        {LocaleZh: "红黑树的黑高: %d", LocaleEn: "Black-height of the Red-Black Tree: %d"},
+	"rb.black_height.compare":        {LocaleZh: "比较 %d 与节点 %d (%s)，路径上已有 %d 个黑色节点", LocaleEn: "Compare %d with node %d (%s), %d black nodes on the path so far"},
+	"rb.black_height.path":           {LocaleZh: "从根到值 %d 的路径上有 %d 个黑色节点", LocaleEn: "The path from the root to value %d has %d black nodes"},
+	"rb.black_height.path_success":   {LocaleZh: "黑高 %d，到值 %d 的路径上有 %d 个黑色节点", LocaleEn: "Black-height %d, the path to value %d has %d black nodes"},
+	"rb.rebalance.start":             {LocaleZh: "按中序收集 %d 个节点，当前高度 %d", LocaleEn: "Collect %d nodes in order, current height %d"},
+	"rb.rebalance.root":              {LocaleZh: "节点 %d (%s) 作为第 %d 层的子树根", LocaleEn: "Node %d (%s) becomes a subtree root at depth %d"},
+	"rb.rebalance.done":              {LocaleZh: "重建完成，高度 %d → %d", LocaleEn: "Rebuild complete, height %d → %d"},
+	"rb.rebalance.success":           {LocaleZh: "已将 %d 个节点重建为平衡红黑树，高度 %d → %d", LocaleEn: "Rebuilt %d nodes into a balanced Red-Black Tree, height %d → %d"},
+	"rb.raw.invalid_color":           {LocaleZh: "无效的颜色 %q，应为 red 或 black", LocaleEn: "Invalid color %q, expected red or black"},
+	"rb.raw.invalid_side":            {LocaleZh: "无效的位置 %q，应为 left 或 right", LocaleEn: "Invalid side %q, expected left or right"},
+	"rb.raw.parent_required":         {LocaleZh: "树非空，必须指定父节点", LocaleEn: "The tree is not empty, a parent node is required"},
+	"rb.raw.red_root":                {LocaleZh: "根节点必须为黑色", LocaleEn: "The root must be black"},
+	"rb.raw.occupied":                {LocaleZh: "节点 %d 的 %s 子节点已存在", LocaleEn: "Node %d already has a %s child"},
+	"rb.raw.order":                   {LocaleZh: "值 %d 不能位于节点 %[3]d 的 %[2]s 子树中，违反二叉搜索树顺序", LocaleEn: "Value %d cannot go in the %s subtree of node %d without breaking the search order"},
+	"rb.raw.root":                    {LocaleZh: "直接放置节点 %d (%s) 作为根节点", LocaleEn: "Place node %d (%s) as the root"},
+	"rb.raw.left":                    {LocaleZh: "直接放置节点 %d (%s) 作为 %d 的左子节点，不做调整", LocaleEn: "Place node %d (%s) as the left child of %d without rebalancing"},
+	"rb.raw.right":                   {LocaleZh: "直接放置节点 %d (%s) 作为 %d 的右子节点，不做调整", LocaleEn: "Place node %d (%s) as the right child of %d without rebalancing"},
+	"rb.raw.success":                 {LocaleZh: "已放置节点 %d", LocaleEn: "Placed node %d"},
+	"rb.raw.fixup_black":             {LocaleZh: "节点 %d 为黑色，只能从红色节点开始修复", LocaleEn: "Node %d is black, the fixup can only start from a red node"},
+	"rb.raw.fixup":                   {LocaleZh: "从节点 %d 开始插入修复", LocaleEn: "Run the insertion fixup from node %d"},
+	"rb.raw.fixup_done":              {LocaleZh: "修复结束", LocaleEn: "Fixup finished"},
+	"rb.delete.unbalanced":           {LocaleZh: "各路径上的黑色节点数不相等，无法执行删除修复", LocaleEn: "Paths carry different numbers of black nodes, so the delete fixup cannot run"},
+	"rb.raw.fixup_success":           {LocaleZh: "从节点 %d 修复完成，旋转 %d 次，变色 %d 次", LocaleEn: "Fixup from node %d finished with %d rotations and %d color changes"},
+	"rb.bulk_delete.missing":         {LocaleZh: "，以下值不存在: %v", LocaleEn: "; missing values: %v"},
+
+	// Graph
+	"graph.node_missing":             {LocaleZh: "节点 %s 不存在", LocaleEn: "Node %s does not exist"},
+	"graph.unreachable_step":         {LocaleZh: "无法从 %s 到达 %s", LocaleEn: "There is no path from %s to %s"},
+	"graph.unreachable":              {LocaleZh: "无法到达目标节点", LocaleEn: "The target node is unreachable"},
+	"graph.state":                    {LocaleZh: "当前图共有 %d 个节点，%d 条边", LocaleEn: "The graph currently has %d nodes and %d edges"},
+	"graph.insert.exists":            {LocaleZh: "节点 %s 已存在", LocaleEn: "Node %s already exists"},
+	"graph.insert.step":              {LocaleZh: "添加节点 %s", LocaleEn: "Add node %s"},
+	"graph.insert.done":              {LocaleZh: "插入完成", LocaleEn: "Insertion complete"},
+	"graph.validate.self_loop":       {LocaleZh: "边 %s→%s 是自环，未允许自环", LocaleEn: "Edge %s→%s is a self-loop, which is not allowed"},
+	"graph.validate.negative_weight": {LocaleZh: "边 %s→%s 权重为负 (%g)，Dijkstra 结果可能错误，请考虑 Bellman-Ford", LocaleEn: "Edge %s→%s has a negative weight (%g); Dijkstra may give wrong results, consider Bellman-Ford"},
+	"graph.validate.parallel_edge":   {LocaleZh: "边 %s→%s 重复，将与已有的边合并并保留较小的权重", LocaleEn: "Edge %s→%s is repeated and will be merged with the existing one, keeping the lighter weight"},
+	"graph.validate.empty_id":        {LocaleZh: "节点 ID 不能为空", LocaleEn: "Node ID must not be empty"},
+	"graph.validate.duplicate_node":  {LocaleZh: "节点 %s 重复定义", LocaleEn: "Node %s is defined more than once"},
+	"graph.validate.unknown_node":    {LocaleZh: "边 %s→%s 引用了不存在的节点 %s", LocaleEn: "Edge %s→%s references unknown node %s"},
+	"graph.validate.failed":          {LocaleZh: "图数据校验失败", LocaleEn: "Graph validation failed"},
+	"graph.build.step":               {LocaleZh: "构建图：%d 个节点，%d 条边", LocaleEn: "Build graph: %d nodes, %d edges"},
+	"graph.build.done":               {LocaleZh: "构建完成", LocaleEn: "Build complete"},
+	"graph.build.success":            {LocaleZh: "成功构建图：%d 个节点，%d 条边", LocaleEn: "Built a graph with %d nodes and %d edges"},
+	"graph.generate.invalid":         {LocaleZh: "节点数必须在 1 到 %d 之间", LocaleEn: "The node count must be between 1 and %d"},
+	"graph.generate.success":         {LocaleZh: "已生成随机连通图：%d 个节点，%d 条边 (种子 %d)", LocaleEn: "Generated a random connected graph with %d nodes and %d edges (seed %d)"},
+	"graph.export.success":           {LocaleZh: "已导出图的邻接表", LocaleEn: "Exported the graph as an adjacency list"},
+	"graph.import.success":           {LocaleZh: "已导入图：%d 个节点，%d 条边", LocaleEn: "Imported a graph with %d nodes and %d edges"},
+	"graph.info.connected":           {LocaleZh: "%d 个节点，%d 条边，图是连通的", LocaleEn: "%d nodes, %d edges, the graph is connected"},
+	"graph.info.weakly_connected":    {LocaleZh: "%d 个节点，%d 条边，图是弱连通的", LocaleEn: "%d nodes, %d edges, the graph is weakly connected"},
+	"graph.info.disconnected":        {LocaleZh: "%d 个节点，%d 条边，图不连通", LocaleEn: "%d nodes, %d edges, the graph is not connected"},
+	"graph.dijkstra.init":            {LocaleZh: "初始化：起点 %s 距离设为 0", LocaleEn: "Initialize: distance of start node %s set to 0"},
+	"graph.dijkstra.select":          {LocaleZh: "选择距离最小的未访问节点: %s (距离: %g)", LocaleEn: "Select the unvisited node with the smallest distance: %s (distance: %g)"},
+	"graph.dijkstra.settled":         {LocaleZh: "节点 %s 已确定最短距离 %g，标记为已访问", LocaleEn: "Node %s is settled with final distance %g and marked visited"},
+	"graph.dijkstra.update":          {LocaleZh: "更新节点 %s 距离: %s → %g (通过 %s)", LocaleEn: "Update distance of node %s: %s → %g (via %s)"},
+	"graph.dijkstra.no_update":       {LocaleZh: "边 %s→%s: 新距离 %g >= 当前距离 %g，不更新", LocaleEn: "Edge %s→%s: new distance %g >= current distance %g, no update"},
+	"graph.dijkstra.found":           {LocaleZh: "找到最短路径: %v, 总距离: %g", LocaleEn: "Found shortest path: %v, total distance: %g"},
+	"graph.dijkstra.success":         {LocaleZh: "最短路径距离: %g", LocaleEn: "Shortest path distance: %g"},
+	"graph.nodecost.update":          {LocaleZh: "更新节点 %s 代价: %s → %g (通过 %s，边权 %g + 节点代价 %g)", LocaleEn: "Update cost of node %s: %s → %g (via %s, edge weight %g + node cost %g)"},
+	"graph.nodecost.no_update":       {LocaleZh: "边 %s→%s: 边权 %g + 节点代价 %g 得到 %g >= 当前代价 %g，不更新", LocaleEn: "Edge %s→%s: edge weight %g + node cost %g gives %g >= current cost %g, no update"},
+	"graph.nodecost.success":         {LocaleZh: "含节点代价的最短路径总代价: %g", LocaleEn: "Shortest path cost including node costs: %g"},
+	"graph.sssp.done":                {LocaleZh: "从 %s 出发的最短路径树计算完成，%d 个节点不可达", LocaleEn: "Shortest path tree from %s complete, %d nodes unreachable"},
+	"graph.sssp.success":             {LocaleZh: "已计算从 %s 到所有节点的最短距离", LocaleEn: "Computed shortest distances from %s to all nodes"},
+	"graph.kshortest.invalid_k":      {LocaleZh: "k 必须大于 0", LocaleEn: "k must be greater than 0"},
+	"graph.kshortest.path":           {LocaleZh: "第 %d 条最短路径: %v, 总距离: %g", LocaleEn: "Shortest path #%d: %v, total distance: %g"},
+	"graph.kshortest.no_spur":        {LocaleZh: "偏离节点 %s: 没有可用的偏离路径", LocaleEn: "Spur node %s: no spur path available"},
+	"graph.kshortest.candidate":      {LocaleZh: "偏离节点 %s: 候选路径 %v, 总距离: %g", LocaleEn: "Spur node %s: candidate path %v, total distance: %g"},
+	"graph.kshortest.done":           {LocaleZh: "共找到 %d 条路径", LocaleEn: "Found %d paths in total"},
+	"graph.kshortest.success":        {LocaleZh: "找到 %d 条最短路径", LocaleEn: "Found %d shortest paths"},
+	"graph.longest.undirected":       {LocaleZh: "最长路径仅支持有向图", LocaleEn: "Longest path requires a directed graph"},
+	"graph.longest.cyclic":           {LocaleZh: "图中存在环，无法计算最长路径", LocaleEn: "The graph contains a cycle, the longest path is undefined"},
+	"graph.longest.empty":            {LocaleZh: "图为空", LocaleEn: "The graph is empty"},
+	"graph.longest.order":            {LocaleZh: "拓扑序: %v，所有节点最长距离初始化为 0", LocaleEn: "Topological order: %v, all longest distances start at 0"},
+	"graph.longest.select":           {LocaleZh: "按拓扑序处理节点 %s (最长距离: %g)", LocaleEn: "Process node %s in topological order (longest distance: %g)"},
+	"graph.longest.update":           {LocaleZh: "更新节点 %s 最长距离: %g → %g (通过 %s)", LocaleEn: "Update longest distance of node %s: %g → %g (via %s)"},
+	"graph.longest.no_update":        {LocaleZh: "边 %s→%s: 新距离 %g <= 当前最长距离 %g，不更新", LocaleEn: "Edge %s→%s: new distance %g <= current longest distance %g, no update"},
+	"graph.longest.found":            {LocaleZh: "关键路径: %v, 总长度: %g", LocaleEn: "Critical path: %v, total length: %g"},
+	"graph.longest.success":          {LocaleZh: "最长路径长度: %g", LocaleEn: "Longest path length: %g"},
+	"graph.scc.undirected":           {LocaleZh: "强连通分量仅支持有向图", LocaleEn: "Strongly connected components require a directed graph"},
+	"graph.scc.visit":                {LocaleZh: "访问节点 %s，设置 index = low = %d 并入栈", LocaleEn: "Visit node %s, set index = low = %d and push it"},
+	"graph.scc.lowlink_child":        {LocaleZh: "更新节点 %s 的 low: %d → %d (来自子节点 %s)", LocaleEn: "Update low of node %s: %d → %d (from child %s)"},
+	"graph.scc.lowlink_back":         {LocaleZh: "更新节点 %s 的 low: %d → %d (回边指向栈中节点 %s)", LocaleEn: "Update low of node %s: %d → %d (back edge to stacked node %s)"},
+	"graph.scc.component":            {LocaleZh: "分量 %d: 节点 %s 的 low 等于 index，出栈 %v", LocaleEn: "Component %d: node %s has low equal to index, pop %v"},
+	"graph.scc.done":                 {LocaleZh: "强连通分量计算完成，共 %d 个", LocaleEn: "Strongly connected components done, %d found"},
+	"graph.scc.success":              {LocaleZh: "共有 %d 个强连通分量", LocaleEn: "Found %d strongly connected components"},
+	"graph.bipartite.root":           {LocaleZh: "从节点 %s 开始 BFS，染色为 0", LocaleEn: "Start BFS at node %s with color 0"},
+	"graph.bipartite.color":          {LocaleZh: "节点 %s 染色为 %d (与邻居 %s 相反)", LocaleEn: "Color node %s with %d (opposite of neighbor %s)"},
+	"graph.bipartite.conflict":       {LocaleZh: "冲突: 边 %s-%s 两端颜色均为 %d，奇环 %v", LocaleEn: "Conflict: both ends of edge %s-%s have color %d, odd cycle %v"},
+	"graph.bipartite.failure":        {LocaleZh: "图不是二分图，存在长度为 %d 的奇环", LocaleEn: "The graph is not bipartite, it has an odd cycle of length %d"},
+	"graph.bipartite.done":           {LocaleZh: "二分图划分: %v | %v", LocaleEn: "Bipartition: %v | %v"},
+	"graph.bipartite.success":        {LocaleZh: "图是二分图: %d + %d 个节点", LocaleEn: "The graph is bipartite: %d + %d nodes"},
+	"graph.maxflow.same_node":        {LocaleZh: "源点与汇点不能相同", LocaleEn: "Source and sink must be different nodes"},
+	"graph.maxflow.init":             {LocaleZh: "以边权作为容量，计算 %s 到 %s 的最大流", LocaleEn: "Compute the maximum flow from %s to %s using edge weights as capacities"},
+	"graph.maxflow.augment":          {LocaleZh: "增广路径 %v，瓶颈容量 %g，剩余容量 [%s]，当前总流量 %g", LocaleEn: "Augmenting path %v, bottleneck %g, residual capacities [%s], total flow %g"},
+	"graph.maxflow.done":             {LocaleZh: "不存在更多增广路径，共增广 %d 次，最大流为 %g", LocaleEn: "No augmenting path remains after %d augmentations, maximum flow is %g"},
+	"graph.maxflow.success":          {LocaleZh: "%s 到 %s 的最大流: %g", LocaleEn: "Maximum flow from %s to %s: %g"},
+	"graph.bfs.init":                 {LocaleZh: "BFS 初始化：起点 %s 入队，所有边视为权重 1", LocaleEn: "BFS init: enqueue start node %s, every edge counts as weight 1"},
+	"graph.bfs.dequeue":              {LocaleZh: "出队节点 %s (跳数: %g)", LocaleEn: "Dequeue node %s (hops: %g)"},
+	"graph.bfs.discover":             {LocaleZh: "发现节点 %s，跳数 %g (通过 %s)", LocaleEn: "Discover node %s at %g hops (via %s)"},
+	"graph.bfs.found":                {LocaleZh: "找到跳数最少的路径: %v, 跳数: %g", LocaleEn: "Found the path with the fewest hops: %v, hops: %g"},
+	"graph.bfs.success":              {LocaleZh: "最少跳数: %g", LocaleEn: "Fewest hops: %g"},
+	"graph.compare_paths.same":       {LocaleZh: "Dijkstra 与 BFS 选择了同一路径 %v (代价 %g)", LocaleEn: "Dijkstra and BFS chose the same path %v (cost %g)"},
+	"graph.compare_paths.differ":     {LocaleZh: "边权改变了最优路径: Dijkstra %v (代价 %g, %d 跳)，BFS %v (代价 %g, %d 跳)", LocaleEn: "Edge weights change the best route: Dijkstra %v (cost %g, %d hops), BFS %v (cost %g, %d hops)"},
+	"graph.waypoints.too_few":        {LocaleZh: "至少需要两个途经节点", LocaleEn: "At least two waypoints are required"},
+	"graph.waypoints.segment":        {LocaleZh: "第 %d/%d 段: 从 %s 到 %s 运行 Dijkstra，起点距离设为 0", LocaleEn: "Segment %d/%d: run Dijkstra from %s to %s, start distance set to 0"},
+	"graph.waypoints.unreachable":    {LocaleZh: "途经路径中断: 无法从 %s 到达 %s", LocaleEn: "The route is broken: there is no path from %s to %s"},
+	"graph.waypoints.found":          {LocaleZh: "途经路径: %v, 总距离: %g", LocaleEn: "Route through the waypoints: %v, total distance: %g"},
+	"graph.waypoints.success":        {LocaleZh: "共 %d 段，总距离: %g", LocaleEn: "%d segments, total distance: %g"},
+	"graph.reroute.no_edge":          {LocaleZh: "边 %s→%s 不存在", LocaleEn: "There is no edge %s→%s"},
+	"graph.reroute.remove":           {LocaleZh: "模拟边 %s→%s 故障并将其移除，原最短路径 %v, 总距离: %g", LocaleEn: "Simulate a failure of edge %s→%s and remove it; the original shortest path is %v, total distance: %g"},
+	"graph.reroute.restored":         {LocaleZh: "恢复边 %s→%s", LocaleEn: "Restore edge %s→%s"},
+	"graph.reroute.disconnected":     {LocaleZh: "移除边 %s→%s 后 %s 无法到达 %s", LocaleEn: "Without edge %s→%s, %s cannot reach %s"},
+	"graph.reroute.success":          {LocaleZh: "边 %s→%s 故障后重新路由: 距离 %g → %g (增加 %g)", LocaleEn: "Rerouted around the failed edge %s→%s: distance %g → %g (+%g)"},
+	"graph.centrality.source":        {LocaleZh: "以 %s 为源点累计最短路径依赖", LocaleEn: "Accumulate shortest-path dependencies from source %s"},
+	"graph.centrality.done":          {LocaleZh: "中心性计算完成", LocaleEn: "Centrality computation complete"},
+	"graph.centrality.success":       {LocaleZh: "已计算 %d 个节点的度中心性与介数中心性", LocaleEn: "Computed degree and betweenness centrality for %d nodes"},
+
+	// Handler messages
+	"reset.rbtree":  {LocaleZh: "Red-Black Tree 已重置", LocaleEn: "Red-Black Tree has been reset"},
+	"reset.avltree": {LocaleZh: "AVL Tree 已重置", LocaleEn: "AVL Tree has been reset"},
+	"reset.tree234": {LocaleZh: "2-3-4 Tree 已重置", LocaleEn: "2-3-4 Tree has been reset"},
+	"reset.graph":   {LocaleZh: "Graph 已重置", LocaleEn: "Graph has been reset"},
+	"reset.heap":    {LocaleZh: "Binary Heap 已重置", LocaleEn: "Binary Heap has been reset"},
+}
+
+// Localize formats the message for key in the given locale, falling back to
+// Chinese when the locale has no translation and to the key itself when the
+// key is unknown
+func Localize(locale Locale, key string, args ...interface{}) string {
+	formats, ok := messages[key]
+	if !ok {
+		return key
+	}
+	format, ok := formats[locale]
+	if !ok {
+		format = formats[LocaleZh]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
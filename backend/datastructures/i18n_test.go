@@ -0,0 +1,74 @@
+package datastructures
+
+import (
+	"maps"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// verbPattern matches a formatting verb with an optional argument index
+var verbPattern = regexp.MustCompile(`%[-+# 0]*(?:\[(\d+)\])?[0-9.]*([a-zA-Z%])`)
+
+// argumentVerbs maps each argument of format to the verb that formats it,
+// so translations may reorder arguments with explicit indices
+func argumentVerbs(format string) map[int]string {
+	verbs := make(map[int]string)
+	next := 1
+	for _, m := range verbPattern.FindAllStringSubmatch(format, -1) {
+		if m[2] == "%" {
+			continue
+		}
+		if m[1] != "" {
+			next, _ = strconv.Atoi(m[1])
+		}
+		verbs[next] = m[2]
+		next++
+	}
+	return verbs
+}
+
+func TestMessagesHaveMatchingTranslations(t *testing.T) {
+	for key, formats := range messages {
+		zh, en := formats[LocaleZh], formats[LocaleEn]
+		if zh == "" || en == "" {
+			t.Errorf("%s: missing translation", key)
+			continue
+		}
+		if zhVerbs, enVerbs := argumentVerbs(zh), argumentVerbs(en); !maps.Equal(zhVerbs, enVerbs) {
+			t.Errorf("%s: verbs %v in zh, %v in en", key, zhVerbs, enVerbs)
+		}
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := map[string]Locale{
+		"en":    LocaleEn,
+		"en-US": LocaleEn,
+		"EN":    LocaleEn,
+		"zh":    LocaleZh,
+		"zh-CN": LocaleZh,
+		"":      LocaleZh,
+		"fr":    LocaleZh,
+	}
+	for tag, want := range tests {
+		if got := ParseLocale(tag); got != want {
+			t.Errorf("ParseLocale(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestLocalizedStepDescriptions(t *testing.T) {
+	tree := NewAVLTree()
+	tree.SetLocale(LocaleEn)
+	if got := tree.Insert(5).Steps[0].Description; got != "Start inserting value 5" {
+		t.Errorf("en description %q", got)
+	}
+	tree.SetLocale(LocaleZh)
+	if got := tree.Insert(6).Steps[0].Description; got != "开始插入值 6" {
+		t.Errorf("zh description %q", got)
+	}
+	if got := Localize(LocaleEn, "no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key localized to %q", got)
+	}
+}
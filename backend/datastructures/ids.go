@@ -0,0 +1,40 @@
+package datastructures
+
+// Node IDs are stable per value: the first insert of a value assigns it a
+// permanent ID, re-inserting the value after a delete reuses that ID, and
+// deletes and rotations move nodes rather than copying values between them,
+// so a node never changes ID while it is in the tree. Front ends can
+// therefore track a value across operations by its ID. Red-Black Tree
+// duplicates are the one exception: a second live copy of a value gets a
+// fresh ID, since two nodes in one snapshot cannot share an ID.
+
+// idFor returns the permanent ID of value, allocating one on first use
+func (t *AVLTree) idFor(value int) int {
+	if t.valueIDs == nil {
+		t.valueIDs = make(map[int]int)
+	}
+	if id, ok := t.valueIDs[value]; ok {
+		return id
+	}
+	id := t.nextID
+	t.nextID++
+	t.valueIDs[value] = id
+	return id
+}
+
+// idFor returns the permanent ID of value, allocating one on first use. A
+// duplicate of a value already in the tree gets a fresh ID.
+func (t *RedBlackTree) idFor(value int) int {
+	if t.valueIDs == nil {
+		t.valueIDs = make(map[int]int)
+	}
+	if id, ok := t.valueIDs[value]; ok && !t.Contains(value) {
+		return id
+	}
+	id := t.nextID
+	t.nextID++
+	if _, ok := t.valueIDs[value]; !ok {
+		t.valueIDs[value] = id
+	}
+	return id
+}
@@ -0,0 +1,192 @@
+package datastructures
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// shortestPath runs Dijkstra from start to end without recording steps,
+// skipping blocked nodes and blocked directed edges
+func (g *Graph) shortestPath(start, end string, blockedNodes map[string]bool, blockedEdges map[[2]string]bool) ([]string, float64, bool) {
+	distances := make(map[string]float64)
+	previous := make(map[string]string)
+	visited := make(map[string]bool)
+	for node := range g.Nodes {
+		distances[node] = math.Inf(1)
+	}
+	distances[start] = 0
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &PriorityQueueItem{node: start, priority: 0})
+
+	for pq.Len() > 0 {
+		current := heap.Pop(&pq).(*PriorityQueueItem)
+		if visited[current.node] {
+			continue
+		}
+		visited[current.node] = true
+
+		if current.node == end {
+			path := make([]string, 0)
+			for at := end; at != ""; at = previous[at] {
+				path = append([]string{at}, path...)
+				if at == start {
+					break
+				}
+			}
+			return path, distances[end], true
+		}
+
+		for _, edge := range g.Nodes[current.node] {
+			if visited[edge.To] || blockedNodes[edge.To] || blockedEdges[[2]string{current.node, edge.To}] {
+				continue
+			}
+			newDist := distances[current.node] + edge.Weight
+			if newDist < distances[edge.To] {
+				distances[edge.To] = newDist
+				previous[edge.To] = current.node
+				heap.Push(&pq, &PriorityQueueItem{node: edge.To, priority: newDist})
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+// pathCost sums the cheapest edge between each consecutive pair of nodes
+func (g *Graph) pathCost(path []string) float64 {
+	cost := 0.0
+	for i := 0; i < len(path)-1; i++ {
+		best := math.Inf(1)
+		for _, e := range g.Nodes[path[i]] {
+			if e.To == path[i+1] && e.Weight < best {
+				best = e.Weight
+			}
+		}
+		cost += best
+	}
+	return cost
+}
+
+func samePath(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// KShortestPaths finds up to k loopless shortest paths from start to end
+// using Yen's algorithm on top of Dijkstra. Paths are ordered by cost.
+func (g *Graph) KShortestPaths(start, end string, k int) OperationResult {
+	g.clearSteps()
+
+	for _, id := range []string{start, end} {
+		if !g.HasNode(id) {
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+	if k < 1 {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.kshortest.invalid_k"),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}
+	}
+
+	first, cost, ok := g.shortestPath(start, end, nil, nil)
+	if !ok {
+		g.addStep(StepNotFound, g.msg("graph.unreachable_step", start, end), nil, nil, nil, nil)
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.unreachable"),
+			Reason:  ReasonUnreachable,
+			NoOp:    true,
+			Steps:   g.steps,
+		}
+	}
+
+	found := []PathResult{{Nodes: first, Cost: cost}}
+	g.addStep(StepSelectNode, g.msg("graph.kshortest.path", 1, first, cost), nil, nil, first, nil)
+
+	candidates := make([]PathResult, 0)
+	for len(found) < k {
+		prev := found[len(found)-1].Nodes
+
+		for i := 0; i < len(prev)-1; i++ {
+			if contextDone(g.ctx) {
+				return g.canceledResult()
+			}
+			spurNode := prev[i]
+			rootPath := prev[:i+1]
+
+			// Block the next edge of every accepted path sharing this root,
+			// and every root node except the spur node itself
+			blockedEdges := make(map[[2]string]bool)
+			for _, p := range found {
+				if len(p.Nodes) > i+1 && samePath(p.Nodes[:i+1], rootPath) {
+					blockedEdges[[2]string{p.Nodes[i], p.Nodes[i+1]}] = true
+					blockedEdges[[2]string{p.Nodes[i+1], p.Nodes[i]}] = true
+				}
+			}
+			blockedNodes := make(map[string]bool)
+			for _, n := range rootPath[:len(rootPath)-1] {
+				blockedNodes[n] = true
+			}
+
+			spurPath, spurCost, ok := g.shortestPath(spurNode, end, blockedNodes, blockedEdges)
+			if !ok {
+				g.addStep(StepCompare, g.msg("graph.kshortest.no_spur", spurNode), nil, nil, rootPath, nil)
+				continue
+			}
+
+			total := append(append([]string{}, rootPath[:len(rootPath)-1]...), spurPath...)
+			candidate := PathResult{Nodes: total, Cost: g.pathCost(rootPath) + spurCost}
+			g.addStep(StepCompare, g.msg("graph.kshortest.candidate", spurNode, total, candidate.Cost), nil, nil, total, nil)
+
+			duplicate := false
+			for _, p := range append(found, candidates...) {
+				if samePath(p.Nodes, total) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].Cost < candidates[b].Cost
+		})
+		best := candidates[0]
+		candidates = candidates[1:]
+		found = append(found, best)
+		g.addStep(StepSelectNode, g.msg("graph.kshortest.path", len(found), best.Nodes, best.Cost), nil, nil, best.Nodes, nil)
+	}
+
+	g.addStep(StepComplete, g.msg("graph.kshortest.done", len(found)), nil, nil, found[0].Nodes, nil)
+
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.kshortest.success", len(found)),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+		Paths:      found,
+	}
+}
@@ -0,0 +1,83 @@
+package datastructures
+
+import (
+	"math"
+	"sort"
+)
+
+// graphCenterX and graphCenterY are the center of the area nodes without
+// supplied coordinates are placed around
+const (
+	graphCenterX = 325.0
+	graphCenterY = 150.0
+
+	// layoutRadius keeps circular layouts inside the 0–300 band the sample
+	// graph occupies
+	layoutRadius = 130.0
+)
+
+// Tree snapshots are laid out on a canvas DefaultCanvasWidth wide by
+// default, with levels DefaultLevelHeight apart below a fixed top margin
+const (
+	DefaultCanvasWidth = 800.0
+	DefaultLevelHeight = 80.0
+	treeTopMargin      = 50.0
+)
+
+// TreeLayout is the canvas size tree snapshots are laid out for
+type TreeLayout struct {
+	Width       float64
+	LevelHeight float64
+}
+
+// newTreeLayout returns a layout of the given size, using the default for
+// any dimension that is not positive
+func newTreeLayout(width, levelHeight float64) TreeLayout {
+	if width <= 0 {
+		width = DefaultCanvasWidth
+	}
+	if levelHeight <= 0 {
+		levelHeight = DefaultLevelHeight
+	}
+	return TreeLayout{Width: width, LevelHeight: levelHeight}
+}
+
+// levelY is the y-coordinate of nodes at depth
+func (l TreeLayout) levelY(depth int) float64 {
+	return float64(depth)*l.LevelHeight + treeTopMargin
+}
+
+// layoutCircle spaces the given nodes evenly on a circle, starting at the
+// top and going clockwise in ID order, so the same node set always gets
+// the same layout
+func (g *Graph) layoutCircle(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+
+	step := 2 * math.Pi / float64(len(sorted))
+	for i, id := range sorted {
+		angle := float64(i)*step - math.Pi/2
+		g.NodeCoords[id] = [2]float64{
+			roundCoord(graphCenterX + layoutRadius*math.Cos(angle)),
+			roundCoord(graphCenterY + layoutRadius*math.Sin(angle)),
+		}
+	}
+}
+
+// roundCoord rounds v to hundredths of a pixel to keep float noise out of
+// snapshots and exports
+func roundCoord(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// coordsOrZero returns the supplied coordinates of n, or zero for missing
+// ones, and whether n needs an automatic position
+func coordsOrZero(n GraphNodeInput) (float64, float64, bool) {
+	if n.X == nil || n.Y == nil {
+		return 0, 0, true
+	}
+	return *n.X, *n.Y, false
+}
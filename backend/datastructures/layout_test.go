@@ -0,0 +1,67 @@
+package datastructures
+
+import "testing"
+
+func TestLayoutGivesUnplacedNodesDistinctCoordinates(t *testing.T) {
+	x, y := 40.0, 60.0
+	nodes := []GraphNodeInput{{ID: "placed", X: &x, Y: &y}}
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L"} {
+		nodes = append(nodes, GraphNodeInput{ID: id})
+	}
+	g := NewGraph()
+	if result := g.BuildGraph(nodes, nil, false, false, false); !result.Success {
+		t.Fatal(result.Message)
+	}
+
+	if got := g.NodeCoords["placed"]; got != [2]float64{x, y} {
+		t.Errorf("supplied coordinates moved to %v", got)
+	}
+	seen := make(map[[2]float64]string)
+	for id, coords := range g.NodeCoords {
+		if other, ok := seen[coords]; ok {
+			t.Errorf("%s and %s share coordinates %v", id, other, coords)
+		}
+		seen[coords] = id
+		if id == "placed" {
+			continue
+		}
+		// The layout circle stays inside the 0–300 band of the sample graph
+		if coords[0] < 0 || coords[0] > 2*graphCenterX || coords[1] < 0 || coords[1] > 300 {
+			t.Errorf("%s placed off the canvas at %v", id, coords)
+		}
+	}
+}
+
+func TestSetLayoutScalesTreeSnapshots(t *testing.T) {
+	trees := map[string]interface {
+		Insert(value int) OperationResult
+		SetLayout(width, levelHeight float64)
+		getTreeSnapshot() []TreeNodeSnapshot
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+		"tree234": NewTree234(),
+	}
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			for v := 1; v <= 20; v++ {
+				tree.Insert(v)
+			}
+			base := tree.getTreeSnapshot()
+
+			tree.SetLayout(2*DefaultCanvasWidth, 0)
+			for i, node := range tree.getTreeSnapshot() {
+				if node.X != 2*base[i].X || node.Y != base[i].Y {
+					t.Fatalf("node %d at (%v, %v) on a doubled canvas, was (%v, %v)", node.ID, node.X, node.Y, base[i].X, base[i].Y)
+				}
+			}
+
+			tree.SetLayout(0, 2*DefaultLevelHeight)
+			for i, node := range tree.getTreeSnapshot() {
+				if node.X != base[i].X || node.Y-treeTopMargin != 2*(base[i].Y-treeTopMargin) {
+					t.Fatalf("node %d at (%v, %v) with doubled levels, was (%v, %v)", node.ID, node.X, node.Y, base[i].X, base[i].Y)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package datastructures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// residualGraph holds the remaining capacity of every directed arc. Each
+// undirected edge contributes its weight as capacity in both directions.
+type residualGraph map[string]map[string]float64
+
+// newResidualGraph builds the residual network of g, treating edge weights
+// as capacities. Parallel edges add up and self-loops are ignored.
+func (g *Graph) newResidualGraph() residualGraph {
+	residual := make(residualGraph, len(g.Nodes))
+	for id := range g.Nodes {
+		residual[id] = make(map[string]float64)
+	}
+	for from, edges := range g.Nodes {
+		for _, e := range edges {
+			if e.To == from {
+				continue
+			}
+			residual[from][e.To] += e.Weight
+		}
+	}
+	return residual
+}
+
+// augmentingPath finds the shortest path (in arcs) from source to sink with
+// positive residual capacity. Neighbors are explored in sorted order so the
+// result is deterministic.
+func (r residualGraph) augmentingPath(source, sink string) ([]string, bool) {
+	previous := map[string]string{source: ""}
+	queue := []string{source}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == sink {
+			break
+		}
+
+		neighbors := make([]string, 0, len(r[current]))
+		for next, capacity := range r[current] {
+			if capacity > 0 {
+				neighbors = append(neighbors, next)
+			}
+		}
+		sort.Strings(neighbors)
+		for _, next := range neighbors {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	if _, reached := previous[sink]; !reached {
+		return nil, false
+	}
+	path := make([]string, 0)
+	for at := sink; at != ""; at = previous[at] {
+		path = append([]string{at}, path...)
+	}
+	return path, true
+}
+
+// markSaturated flags every edge of the latest step whose residual
+// capacity in its direction has dropped to zero
+func (g *Graph) markSaturated(residual residualGraph) {
+	last := g.latest
+	for i := range last.Edges {
+		e := &last.Edges[i]
+		e.Saturated = e.From != e.To && e.Weight > 0 && residual[e.From][e.To] == 0
+	}
+}
+
+// describeResidual renders the residual capacity of every arc of path
+func (r residualGraph) describeResidual(path []string) string {
+	parts := make([]string, 0, len(path)-1)
+	for i := 0; i < len(path)-1; i++ {
+		parts = append(parts, fmt.Sprintf("%s→%s:%g", path[i], path[i+1], r[path[i]][path[i+1]]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MaxFlow computes the maximum flow from source to sink with Edmonds-Karp,
+// treating edge weights as capacities. A step is recorded for every
+// augmenting path with its bottleneck and the residual capacities left on it.
+func (g *Graph) MaxFlow(source, sink string) OperationResult {
+	g.clearSteps()
+
+	for _, id := range []string{source, sink} {
+		if !g.HasNode(id) {
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+	if source == sink {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.maxflow.same_node"),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}
+	}
+
+	residual := g.newResidualGraph()
+	g.addStep(StepVisit, g.msg("graph.maxflow.init", source, sink), nil, nil, nil, nil)
+
+	flow := 0.0
+	augmentations := make([]PathResult, 0)
+	for {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		path, ok := residual.augmentingPath(source, sink)
+		if !ok {
+			break
+		}
+
+		bottleneck := residual[path[0]][path[1]]
+		bottleneckEdge := &[2]string{path[0], path[1]}
+		for i := 1; i < len(path)-1; i++ {
+			if capacity := residual[path[i]][path[i+1]]; capacity < bottleneck {
+				bottleneck = capacity
+				bottleneckEdge = &[2]string{path[i], path[i+1]}
+			}
+		}
+		for i := 0; i < len(path)-1; i++ {
+			residual[path[i]][path[i+1]] -= bottleneck
+			residual[path[i+1]][path[i]] += bottleneck
+		}
+		flow += bottleneck
+		augmentations = append(augmentations, PathResult{Nodes: path, Cost: bottleneck})
+
+		// The bottleneck arc is shown as the selected edge
+		g.addStep(StepUpdateDist, g.msg("graph.maxflow.augment", path, bottleneck, residual.describeResidual(path), flow), nil, nil, path, bottleneckEdge)
+		g.markSaturated(residual)
+	}
+
+	g.addStep(StepComplete, g.msg("graph.maxflow.done", len(augmentations), flow), nil, nil, nil, nil)
+	g.markSaturated(residual)
+
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.maxflow.success", source, sink, flow),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+		Paths:      augmentations,
+	}
+}
@@ -0,0 +1,47 @@
+package datastructures
+
+import "testing"
+
+func TestMaxFlowCLRSNetwork(t *testing.T) {
+	// The flow network of CLRS figure 26.1, with v1..v4 as a..d
+	g := buildGraph(t, true,
+		GraphEdgeInput{From: "s", To: "a", Weight: 16},
+		GraphEdgeInput{From: "s", To: "b", Weight: 13},
+		GraphEdgeInput{From: "b", To: "a", Weight: 4},
+		GraphEdgeInput{From: "a", To: "c", Weight: 12},
+		GraphEdgeInput{From: "c", To: "b", Weight: 9},
+		GraphEdgeInput{From: "b", To: "d", Weight: 14},
+		GraphEdgeInput{From: "d", To: "c", Weight: 7},
+		GraphEdgeInput{From: "c", To: "t", Weight: 20},
+		GraphEdgeInput{From: "d", To: "t", Weight: 4},
+	)
+	result := g.MaxFlow("s", "t")
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+	flow := 0.0
+	for _, path := range result.Paths {
+		if path.Nodes[0] != "s" || path.Nodes[len(path.Nodes)-1] != "t" || path.Cost <= 0 {
+			t.Errorf("augmenting path %v carries %v", path.Nodes, path.Cost)
+		}
+		flow += path.Cost
+	}
+	if flow != 23 {
+		t.Errorf("max flow %v, want 23", flow)
+	}
+
+	// The minimum cut {s, a, b, d} | {c, t} is saturated by every maximum flow
+	cut := map[[2]string]bool{{"a", "c"}: true, {"d", "c"}: true, {"d", "t"}: true}
+	for _, e := range result.FinalGraph.Edges {
+		if cut[[2]string{e.From, e.To}] && !e.Saturated {
+			t.Errorf("cut edge %s→%s is not saturated", e.From, e.To)
+		}
+	}
+
+	if result := g.MaxFlow("s", "s"); result.Success || result.Code != CodeInvalidParam {
+		t.Errorf("same source and sink: success %v code %q", result.Success, result.Code)
+	}
+	if result := g.MaxFlow("s", "z"); result.Success || result.Code != CodeNodeNotFound {
+		t.Errorf("unknown sink: success %v code %q", result.Success, result.Code)
+	}
+}
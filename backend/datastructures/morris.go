@@ -0,0 +1,98 @@
+package datastructures
+
+// MorrisInorder visits the AVL Tree in order using O(1) extra space. Before
+// descending left, the in-order predecessor's empty right pointer is
+// threaded back to the current node; the thread is removed when the
+// traversal returns through it, so the tree ends up unchanged.
+func (t *AVLTree) MorrisInorder() OperationResult {
+	t.clearSteps()
+	order := make([]int, 0)
+	t.threads = make(map[*AVLNode]bool)
+	defer func() { t.threads = nil }()
+
+	current := t.Root
+	for current != nil {
+		if contextDone(t.ctx) {
+			// Remove the threads still in place so the tree is left intact
+			for pred := range t.threads {
+				pred.Right = nil
+				delete(t.threads, pred)
+			}
+			return t.canceledResult()
+		}
+		if current.Left == nil {
+			order = append(order, current.Value)
+			t.addStep(StepVisit, t.msg("tree.morris.visit", current.Value), &current.ID, []int{current.ID})
+			current = current.Right
+			continue
+		}
+
+		pred := current.Left
+		for pred.Right != nil && pred.Right != current {
+			pred = pred.Right
+		}
+		if pred.Right == nil {
+			pred.Right = current
+			t.threads[pred] = true
+			t.addStep(StepThreadCreate, t.msg("tree.morris.thread", pred.Value, current.Value), &pred.ID, []int{pred.ID, current.ID})
+			current = current.Left
+			continue
+		}
+
+		pred.Right = nil
+		delete(t.threads, pred)
+		t.addStep(StepThreadRemove, t.msg("tree.morris.unthread", pred.Value, current.Value), &pred.ID, []int{pred.ID, current.ID})
+		order = append(order, current.Value)
+		t.addStep(StepVisit, t.msg("tree.morris.visit", current.Value), &current.ID, []int{current.ID})
+		current = current.Right
+	}
+
+	t.addStep(StepComplete, t.msg("tree.morris.done", order), nil)
+	result := t.newResult(true, t.msg("tree.morris.success", len(order)))
+	result.Traversal = order
+	return result
+}
+
+// MorrisInorder visits the Red-Black Tree in order using O(1) extra space,
+// threading and unthreading in-order predecessors like the AVL version.
+// Parent pointers are never touched.
+func (t *RedBlackTree) MorrisInorder() OperationResult {
+	t.clearSteps()
+	order := make([]int, 0)
+	t.threads = make(map[*RBNode]bool)
+	defer func() { t.threads = nil }()
+
+	current := t.Root
+	for current != t.NIL {
+		if current.Left == t.NIL {
+			order = append(order, current.Value)
+			t.addStep(StepVisit, t.msg("tree.morris.visit", current.Value), &current.ID, []int{current.ID})
+			current = current.Right
+			continue
+		}
+
+		pred := current.Left
+		for pred.Right != t.NIL && pred.Right != current {
+			pred = pred.Right
+		}
+		if pred.Right == t.NIL {
+			pred.Right = current
+			t.threads[pred] = true
+			t.addStep(StepThreadCreate, t.msg("tree.morris.thread", pred.Value, current.Value), &pred.ID, []int{pred.ID, current.ID})
+			current = current.Left
+			continue
+		}
+
+		pred.Right = t.NIL
+		delete(t.threads, pred)
+		t.addStep(StepThreadRemove, t.msg("tree.morris.unthread", pred.Value, current.Value), &pred.ID, []int{pred.ID, current.ID})
+		order = append(order, current.Value)
+		t.addStep(StepVisit, t.msg("tree.morris.visit", current.Value), &current.ID, []int{current.ID})
+		current = current.Right
+	}
+
+	t.addStep(StepComplete, t.msg("tree.morris.done", order), nil)
+	result := t.newResult(true, t.msg("tree.morris.success", len(order)))
+	result.Traversal = order
+	return result
+}
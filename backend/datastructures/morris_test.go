@@ -0,0 +1,62 @@
+package datastructures
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// morrisTree is a tree with a Morris traversal
+type morrisTree interface {
+	Insert(value int) OperationResult
+	MorrisInorder() OperationResult
+	getTreeSnapshot() []TreeNodeSnapshot
+}
+
+func TestMorrisInorderRestoresTheTree(t *testing.T) {
+	values := []int{40, 20, 60, 10, 30, 50, 70, 5, 15, 25, 35, 65, 80, 1}
+	sorted := slices.Sorted(slices.Values(values))
+	for name, tree := range map[string]morrisTree{"rbtree": NewRedBlackTree(), "avltree": NewAVLTree()} {
+		t.Run(name, func(t *testing.T) {
+			for _, v := range values {
+				tree.Insert(v)
+			}
+			before := tree.getTreeSnapshot()
+
+			result := tree.MorrisInorder()
+			if !slices.Equal(result.Traversal, sorted) {
+				t.Fatalf("traversal %v, want %v", result.Traversal, sorted)
+			}
+			threads := 0
+			for _, step := range result.Steps {
+				switch step.Type {
+				case StepThreadCreate:
+					threads++
+				case StepThreadRemove:
+					threads--
+				}
+			}
+			if threads != 0 {
+				t.Errorf("%d threads created but never removed", threads)
+			}
+			if after := tree.getTreeSnapshot(); !reflect.DeepEqual(after, before) {
+				t.Fatalf("tree changed by the traversal:\n%+v\nwant\n%+v", after, before)
+			}
+
+			avl, ok := tree.(*AVLTree)
+			if !ok {
+				return
+			}
+			// Cancel at every point of the AVL traversal, threads still in place
+			for n := 1; n < len(sorted); n++ {
+				avl.SetContext(cancelAfter(n))
+				if result := avl.MorrisInorder(); result.Reason != ReasonCanceled {
+					t.Fatalf("cancel after %d: reason %q", n, result.Reason)
+				}
+				if after := avl.getTreeSnapshot(); !reflect.DeepEqual(after, before) {
+					t.Fatalf("tree changed by a traversal canceled after %d checks", n)
+				}
+			}
+		})
+	}
+}
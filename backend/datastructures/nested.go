@@ -0,0 +1,65 @@
+package datastructures
+
+// NestedNode is a tree node in recursive form, for clients that render or
+// walk the tree themselves rather than following the IDs of the flat
+// snapshot. Missing children are null.
+type NestedNode struct {
+	ID     int         `json:"id"`
+	Value  int         `json:"value"`
+	Color  NodeColor   `json:"color,omitempty"`
+	Height int         `json:"height,omitempty"`
+	Left   *NestedNode `json:"left"`
+	Right  *NestedNode `json:"right"`
+}
+
+// ExportNested returns the AVL Tree as a recursive structure in
+// NestedTree, which is null for an empty tree. FinalTree keeps the flat
+// snapshot as usual.
+func (t *AVLTree) ExportNested() OperationResult {
+	t.clearSteps()
+	result := t.newResult(true, t.msg("tree.export_nested.success", t.Size()))
+	result.NestedTree = t.nested(t.Root)
+	return result
+}
+
+func (t *AVLTree) nested(node *AVLNode) *NestedNode {
+	if node == nil {
+		return nil
+	}
+	n := &NestedNode{
+		ID:     node.ID,
+		Value:  node.Value,
+		Height: node.Height,
+		Left:   t.nested(node.Left),
+	}
+	if !t.threads[node] {
+		n.Right = t.nested(node.Right)
+	}
+	return n
+}
+
+// ExportNested returns the Red-Black Tree as a recursive structure in
+// NestedTree, which is null for an empty tree. FinalTree keeps the flat
+// snapshot as usual.
+func (t *RedBlackTree) ExportNested() OperationResult {
+	t.clearSteps()
+	result := t.newResult(true, t.msg("tree.export_nested.success", t.Size()))
+	result.NestedTree = t.nested(t.Root)
+	return result
+}
+
+func (t *RedBlackTree) nested(node *RBNode) *NestedNode {
+	if node == t.NIL {
+		return nil
+	}
+	n := &NestedNode{
+		ID:    node.ID,
+		Value: node.Value,
+		Color: node.Color,
+		Left:  t.nested(node.Left),
+	}
+	if !t.threads[node] {
+		n.Right = t.nested(node.Right)
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// childID returns the ID of a nested child, or nil for a missing one
+func childID(node *NestedNode) *int {
+	if node == nil {
+		return nil
+	}
+	return &node.ID
+}
+
+func sameID(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func TestExportNestedMatchesTheFlatSnapshot(t *testing.T) {
+	trees := map[string]interface {
+		Insert(value int) OperationResult
+		ExportNested() OperationResult
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			if result := tree.ExportNested(); !result.Success || result.NestedTree != nil {
+				t.Fatalf("empty tree: success %v nested %v", result.Success, result.NestedTree)
+			}
+			for _, v := range []int{50, 30, 70, 20, 40, 60, 80, 10, 45} {
+				tree.Insert(v)
+			}
+			result := tree.ExportNested()
+			flat := make(map[int]TreeNodeSnapshot)
+			for _, node := range result.FinalTree {
+				flat[node.ID] = node
+			}
+
+			visited := 0
+			var walk func(node *NestedNode)
+			walk = func(node *NestedNode) {
+				if node == nil {
+					return
+				}
+				visited++
+				want, ok := flat[node.ID]
+				if !ok || node.Value != want.Value || node.Color != want.Color || node.Height != want.Height {
+					t.Fatalf("nested node %+v, flat %+v", node, want)
+				}
+				if !sameID(childID(node.Left), want.LeftID) || !sameID(childID(node.Right), want.RightID) {
+					t.Fatalf("node %d children differ from the flat snapshot", node.ID)
+				}
+				walk(node.Left)
+				walk(node.Right)
+			}
+			walk(result.NestedTree)
+			if visited != len(flat) {
+				t.Errorf("nested tree has %d nodes, flat snapshot %d", visited, len(flat))
+			}
+
+			data, err := json.Marshal(result.NestedTree)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `"left":null`) {
+				t.Errorf("missing children are not null: %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package datastructures
+
+// Next returns the in-order successor of after in the AVL Tree: the
+// smallest value greater than it, found by descending from the root and
+// remembering the last node where the walk turned left. A nil after starts
+// at the minimum. Done is set once no value follows. after need not be in
+// the tree.
+func (t *AVLTree) Next(after *int) OperationResult {
+	t.clearSteps()
+	t.operand = after
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		result.Done = true
+		return result
+	}
+
+	var next, from *AVLNode
+	for current := t.Root; current != nil; {
+		if after == nil {
+			t.addStep(StepVisit, t.msg("tree.next.min_step", current.Value), &current.ID, []int{current.ID})
+			next = current
+			current = current.Left
+			continue
+		}
+		t.addStep(StepCompare, t.msg("tree.compare", *after, current.Value), &current.ID, []int{current.ID})
+		if *after == current.Value {
+			from = current
+		}
+		if *after < current.Value {
+			next = current
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+
+	if next == nil {
+		message := t.msg("tree.next.done", *after)
+		t.addStep(StepComplete, message, nil)
+		result := t.newResult(true, message)
+		result.Done = true
+		return result
+	}
+	highlight := []int{next.ID}
+	if from != nil {
+		highlight = []int{from.ID, next.ID}
+	}
+	return t.nextResult(after, next.ID, next.Value, highlight)
+}
+
+func (t *AVLTree) nextResult(after *int, id, value int, highlight []int) OperationResult {
+	message := t.msg("tree.next.first", value)
+	if after != nil {
+		message = t.msg("tree.next.found", *after, value)
+	}
+	t.addStep(StepFound, message, &id, highlight)
+	result := t.newResult(true, message)
+	if node, ok := findSnapshotNode(result.FinalTree, id); ok {
+		result.Node = &node
+	}
+	return result
+}
+
+// Next returns the in-order successor of after in the Red-Black Tree: the
+// smallest value greater than it, found by descending from the root and
+// remembering the last node where the walk turned left. A nil after starts
+// at the minimum. Done is set once no value follows. after need not be in
+// the tree; copies of a duplicated value are stepped over together.
+func (t *RedBlackTree) Next(after *int) OperationResult {
+	t.clearSteps()
+	t.operand = after
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		result.Done = true
+		return result
+	}
+
+	next, from := t.NIL, t.NIL
+	for x := t.Root; x != t.NIL; {
+		if after == nil {
+			t.addStep(StepVisit, t.msg("tree.next.min_step", x.Value), &x.ID, []int{x.ID})
+			next = x
+			x = x.Left
+			continue
+		}
+		t.addStep(StepCompare, t.msg("tree.compare", *after, x.Value), &x.ID, []int{x.ID})
+		if *after == x.Value {
+			from = x
+		}
+		if *after < x.Value {
+			next = x
+			x = x.Left
+		} else {
+			x = x.Right
+		}
+	}
+
+	if next == t.NIL {
+		message := t.msg("tree.next.done", *after)
+		t.addStep(StepComplete, message, nil)
+		result := t.newResult(true, message)
+		result.Done = true
+		return result
+	}
+	return t.nextResult(after, next.ID, next.Value, t.linkedIDs(from, next))
+}
+
+func (t *RedBlackTree) nextResult(after *int, id, value int, highlight []int) OperationResult {
+	message := t.msg("tree.next.first", value)
+	if after != nil {
+		message = t.msg("tree.next.found", *after, value)
+	}
+	t.addStep(StepFound, message, &id, highlight)
+	result := t.newResult(true, message)
+	if node, ok := findSnapshotNode(result.FinalTree, id); ok {
+		result.Node = &node
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package datastructures
+
+import (
+	"container/heap"
+	"math"
+)
+
+// DijkstraNodeCost finds the cheapest path from start to end when entering a
+// node costs its NodeWeight in addition to the edge weight. The start node's
+// own weight is not charged.
+func (g *Graph) DijkstraNodeCost(start, end string) OperationResult {
+	g.clearSteps()
+
+	for _, id := range []string{start, end} {
+		if !g.HasNode(id) {
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+
+	distances := make(map[string]float64)
+	previous := make(map[string]string)
+	visited := make(map[string]bool)
+
+	for node := range g.Nodes {
+		distances[node] = math.Inf(1)
+	}
+	distances[start] = 0
+
+	g.addStep(StepVisit, g.msg("graph.dijkstra.init", start), distances, visited, nil, nil)
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &PriorityQueueItem{node: start, priority: 0})
+
+	for pq.Len() > 0 {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		current := heap.Pop(&pq).(*PriorityQueueItem)
+
+		if visited[current.node] {
+			continue
+		}
+		g.addStep(StepSelectNode, g.msg("graph.dijkstra.select", current.node, distances[current.node]), distances, visited, nil, nil)
+
+		// The node is settled: its distance is final from here on
+		visited[current.node] = true
+		g.addStep(StepMarkVisited, g.msg("graph.dijkstra.settled", current.node, distances[current.node]), distances, visited, nil, nil)
+
+		if current.node == end {
+			path := make([]string, 0)
+			for at := end; at != ""; at = previous[at] {
+				path = append([]string{at}, path...)
+			}
+			g.addStep(StepComplete, g.msg("graph.dijkstra.found", path, distances[end]), distances, visited, path, nil)
+
+			return OperationResult{
+				Success:    true,
+				Message:    g.msg("graph.nodecost.success", distances[end]),
+				Steps:      g.steps,
+				FinalGraph: g.latest,
+				Paths:      []PathResult{{Nodes: path, Cost: distances[end]}},
+			}
+		}
+
+		for _, edge := range g.Nodes[current.node] {
+			if visited[edge.To] {
+				continue
+			}
+
+			nodeCost := float64(g.NodeWeight[edge.To])
+			newDist := distances[current.node] + edge.Weight + nodeCost
+			edgePtr := &[2]string{current.node, edge.To}
+
+			if newDist < distances[edge.To] {
+				oldDist := distances[edge.To]
+				distances[edge.To] = newDist
+				previous[edge.To] = current.node
+				heap.Push(&pq, &PriorityQueueItem{node: edge.To, priority: newDist})
+				g.addStep(StepUpdateDist, g.msg("graph.nodecost.update", edge.To, formatDistance(oldDist), newDist, current.node, edge.Weight, nodeCost), distances, visited, nil, edgePtr)
+			} else {
+				g.addStep(StepCompare, g.msg("graph.nodecost.no_update", current.node, edge.To, edge.Weight, nodeCost, newDist, distances[edge.To]), distances, visited, nil, edgePtr)
+			}
+		}
+	}
+
+	g.addStep(StepNotFound, g.msg("graph.unreachable_step", start, end), distances, visited, nil, nil)
+	return OperationResult{
+		Success: false,
+		Message: g.msg("graph.unreachable"),
+		Reason:  ReasonUnreachable,
+		NoOp:    true,
+		Steps:   g.steps,
+	}
+}
@@ -0,0 +1,80 @@
+package datastructures
+
+// findSnapshotNode returns the node with the given ID from a tree snapshot
+func findSnapshotNode(nodes []TreeNodeSnapshot, id int) (TreeNodeSnapshot, bool) {
+	for _, node := range nodes {
+		if node.ID == id {
+			return node, true
+		}
+	}
+	return TreeNodeSnapshot{}, false
+}
+
+// GetNodeByID returns the current attributes and position of the AVL Tree
+// node with the given ID
+func (t *AVLTree) GetNodeByID(id int) (TreeNodeSnapshot, bool) {
+	return findSnapshotNode(t.getTreeSnapshot(), id)
+}
+
+// GetNode looks up a node by ID and highlights it
+func (t *AVLTree) GetNode(id int) OperationResult {
+	t.clearSteps()
+	node, found := t.GetNodeByID(id)
+	if !found {
+		result := t.newResult(false, t.msg("tree.node.missing", id))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		return result
+	}
+
+	t.addStep(StepFound, t.msg("tree.node.found", id, node.Value), &node.ID, []int{node.ID})
+	result := t.newResult(true, t.msg("tree.node.found", id, node.Value))
+	result.Node = &node
+	return result
+}
+
+// GetNodeByID returns the current attributes and position of the
+// Red-Black Tree node with the given ID
+func (t *RedBlackTree) GetNodeByID(id int) (TreeNodeSnapshot, bool) {
+	return findSnapshotNode(t.getTreeSnapshot(), id)
+}
+
+// GetNode looks up a node by ID and highlights it
+func (t *RedBlackTree) GetNode(id int) OperationResult {
+	t.clearSteps()
+	node, found := t.GetNodeByID(id)
+	if !found {
+		result := t.newResult(false, t.msg("tree.node.missing", id))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		return result
+	}
+
+	t.addStep(StepFound, t.msg("tree.node.found", id, node.Value), &node.ID, []int{node.ID})
+	result := t.newResult(true, t.msg("tree.node.found", id, node.Value))
+	result.Node = &node
+	return result
+}
+
+// GetNodeByID returns the current keys and position of the 2-3-4 tree node
+// with the given ID
+func (t *Tree234) GetNodeByID(id int) (TreeNodeSnapshot, bool) {
+	return findSnapshotNode(t.getTreeSnapshot(), id)
+}
+
+// GetNode looks up a node by ID and highlights it
+func (t *Tree234) GetNode(id int) OperationResult {
+	t.clearSteps()
+	node, found := t.GetNodeByID(id)
+	if !found {
+		result := t.newResult(false, t.msg("tree.node.missing", id))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		return result
+	}
+
+	t.addStep(StepFound, t.msg("tree.node.found", id, node.Value), &node.ID, node.ID)
+	result := t.newResult(true, t.msg("tree.node.found", id, node.Value))
+	result.Node = &node
+	return result
+}
@@ -0,0 +1,51 @@
+package datastructures
+
+import "testing"
+
+func TestGetNodeReturnsTheNodeWithThatID(t *testing.T) {
+	values := []int{50, 20, 80, 10, 30, 70, 90, 25, 35, 5}
+
+	t.Run("rbtree", func(t *testing.T) {
+		tree := NewRedBlackTree()
+		for _, v := range values {
+			tree.Insert(v)
+		}
+		var walk func(node *RBNode)
+		walk = func(node *RBNode) {
+			if node == tree.NIL {
+				return
+			}
+			result := tree.GetNode(node.ID)
+			if !result.Success || result.Node == nil || result.Node.Value != node.Value || result.Node.Color != node.Color {
+				t.Errorf("GetNode(%d): success %v node %+v, want value %d %s", node.ID, result.Success, result.Node, node.Value, node.Color)
+			}
+			walk(node.Left)
+			walk(node.Right)
+		}
+		walk(tree.Root)
+	})
+
+	t.Run("avltree", func(t *testing.T) {
+		tree := NewAVLTree()
+		for _, v := range values {
+			tree.Insert(v)
+		}
+		var walk func(node *AVLNode)
+		walk = func(node *AVLNode) {
+			if node == nil {
+				return
+			}
+			got, ok := tree.GetNodeByID(node.ID)
+			if !ok || got.Value != node.Value || got.Height != node.Height {
+				t.Errorf("GetNodeByID(%d): found %v node %+v, want value %d height %d", node.ID, ok, got, node.Value, node.Height)
+			}
+			walk(node.Left)
+			walk(node.Right)
+		}
+		walk(tree.Root)
+
+		if result := tree.GetNode(999); result.Success || result.Node != nil || result.Reason != ReasonNotFound {
+			t.Errorf("GetNode(999): success %v node %v reason %q", result.Success, result.Node, result.Reason)
+		}
+	})
+}
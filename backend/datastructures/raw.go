@@ -0,0 +1,181 @@
+package datastructures
+
+// Raw construction places nodes without rebalancing so lessons can set up a
+// particular, even invalid, coloring and then step through the fixup that
+// repairs it. Only the coloring may be wrong: the binary search order always
+// holds and the root is always black, since insertFixup relies on every red
+// node having a parent. A wrong coloring may leave paths with different
+// black counts; Delete refuses to run on such a tree, since its fixup relies
+// on the sibling of a doubly black node being a real node.
+
+// findByID returns the node with the given ID, or NIL if there is none
+func (t *RedBlackTree) findByID(node *RBNode, id int) *RBNode {
+	if node == t.NIL {
+		return t.NIL
+	}
+	if node.ID == id {
+		return node
+	}
+	if found := t.findByID(node.Left, id); found != t.NIL {
+		return found
+	}
+	if node.Right != t.NIL && t.threads[node] {
+		return t.NIL
+	}
+	return t.findByID(node.Right, id)
+}
+
+// blackBalanced reports whether every path from the root down to a leaf
+// passes the same number of black nodes
+func (t *RedBlackTree) blackBalanced() bool {
+	var height func(node *RBNode) (int, bool)
+	height = func(node *RBNode) (int, bool) {
+		if node == t.NIL {
+			return 0, true
+		}
+		left, ok := height(node.Left)
+		if !ok {
+			return 0, false
+		}
+		right, ok := height(node.Right)
+		if !ok || left != right {
+			return 0, false
+		}
+		if node.Color == Black {
+			left++
+		}
+		return left, true
+	}
+	_, ok := height(t.Root)
+	return ok
+}
+
+// rawUnbalanced reports whether raw placement left the black-heights
+// unequal. Only trees with raw-placed nodes are walked; the flag is cleared
+// once such a tree validates, so later deletes stay O(log n).
+func (t *RedBlackTree) rawUnbalanced() bool {
+	if !t.placedRaw {
+		return false
+	}
+	if !t.blackBalanced() {
+		return true
+	}
+	t.placedRaw = false
+	return false
+}
+
+// unbalancedResult is the result of a delete refused because raw placement
+// left the black-heights unequal
+func (t *RedBlackTree) unbalancedResult() OperationResult {
+	message := t.msg("rb.delete.unbalanced")
+	t.addStep(StepNotFound, message, nil)
+	result := t.newResult(false, message)
+	result.Code = CodeInvalidState
+	return result
+}
+
+// rawInvalid is the result of a rejected raw operation
+func (t *RedBlackTree) rawInvalid(message string) OperationResult {
+	result := t.newResult(false, message)
+	result.Code = CodeInvalidParam
+	return result
+}
+
+// InsertRaw links a new node of the given color as the left or right child
+// of the node with ID parentID, without any rebalancing. A nil parentID
+// makes the node the root of an empty tree. The node must fit the binary
+// search order of its ancestors and the free child slot must exist.
+func (t *RedBlackTree) InsertRaw(value int, color NodeColor, parentID *int, side string) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if color != Red && color != Black {
+		return t.rawInvalid(t.msg("rb.raw.invalid_color", color))
+	}
+
+	if parentID == nil {
+		if t.Root != t.NIL {
+			return t.rawInvalid(t.msg("rb.raw.parent_required"))
+		}
+		if color != Black {
+			return t.rawInvalid(t.msg("rb.raw.red_root"))
+		}
+		t.Root = t.newRawNode(value, color, t.NIL)
+		t.placedRaw = true
+		t.addStep(StepInsert, t.msg("rb.raw.root", value, color), &t.Root.ID, []int{t.Root.ID})
+		return t.newResult(true, t.msg("rb.raw.success", value))
+	}
+
+	parent := t.findByID(t.Root, *parentID)
+	if parent == t.NIL {
+		result := t.newResult(false, t.msg("tree.node.missing", *parentID))
+		result.Code = CodeNodeNotFound
+		return result
+	}
+	if side != "left" && side != "right" {
+		return t.rawInvalid(t.msg("rb.raw.invalid_side", side))
+	}
+	if (side == "left" && parent.Left != t.NIL) || (side == "right" && parent.Right != t.NIL) {
+		return t.rawInvalid(t.msg("rb.raw.occupied", parent.Value, side))
+	}
+
+	// Insert sends equal values right, so the same rule applies here at
+	// every ancestor on the way down
+	childSide := side
+	for node := parent; node != t.NIL; node = node.Parent {
+		if (childSide == "left" && value >= node.Value) || (childSide == "right" && value < node.Value) {
+			return t.rawInvalid(t.msg("rb.raw.order", value, childSide, node.Value))
+		}
+		if node.Parent != t.NIL {
+			childSide = "right"
+			if node == node.Parent.Left {
+				childSide = "left"
+			}
+		}
+	}
+
+	z := t.newRawNode(value, color, parent)
+	t.placedRaw = true
+	if side == "left" {
+		parent.Left = z
+		t.addStep(StepInsert, t.msg("rb.raw.left", value, color, parent.Value), &z.ID, []int{parent.ID, z.ID})
+	} else {
+		parent.Right = z
+		t.addStep(StepInsert, t.msg("rb.raw.right", value, color, parent.Value), &z.ID, []int{parent.ID, z.ID})
+	}
+	return t.newResult(true, t.msg("rb.raw.success", value))
+}
+
+func (t *RedBlackTree) newRawNode(value int, color NodeColor, parent *RBNode) *RBNode {
+	return &RBNode{
+		ID:     t.idFor(value),
+		Value:  value,
+		Color:  color,
+		Left:   t.NIL,
+		Right:  t.NIL,
+		Parent: parent,
+	}
+}
+
+// TriggerFixupAt runs the insertion fixup from the node with the given ID,
+// as if it had just been inserted. The node must be red.
+func (t *RedBlackTree) TriggerFixupAt(id int) OperationResult {
+	t.clearSteps()
+
+	z := t.findByID(t.Root, id)
+	if z == t.NIL {
+		result := t.newResult(false, t.msg("tree.node.missing", id))
+		result.Code = CodeNodeNotFound
+		return result
+	}
+	t.operand = &z.Value
+	if z.Color != Red {
+		return t.rawInvalid(t.msg("rb.raw.fixup_black", z.Value))
+	}
+
+	t.addStep(StepVisit, t.msg("rb.raw.fixup", z.Value), &z.ID, []int{z.ID})
+	t.placedRaw = true
+	t.insertFixup(z)
+	t.addStep(StepComplete, t.msg("rb.raw.fixup_done"), nil)
+	return t.newResult(true, t.msg("rb.raw.fixup_success", z.Value, t.rotations, t.colorChanges))
+}
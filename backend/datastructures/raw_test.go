@@ -0,0 +1,84 @@
+package datastructures
+
+import "testing"
+
+func intPtr(v int) *int { return &v }
+
+func TestTriggerFixupAtCase1(t *testing.T) {
+	tree := NewRedBlackTree()
+	tree.InsertRaw(10, Black, nil, "")
+	root := tree.Root.ID
+	tree.InsertRaw(5, Red, intPtr(root), "left")
+	tree.InsertRaw(15, Red, intPtr(root), "right")
+	tree.InsertRaw(1, Red, intPtr(tree.searchNode(5).ID), "left")
+
+	result := tree.TriggerFixupAt(tree.searchNode(1).ID)
+	if !result.Success {
+		t.Fatalf("fixup failed: %s", result.Message)
+	}
+	want := map[int]NodeColor{10: Black, 5: Black, 15: Black, 1: Red}
+	for value, color := range want {
+		if got := tree.searchNode(value).Color; got != color {
+			t.Errorf("node %d is %s, want %s", value, got, color)
+		}
+	}
+	if tree.rotations != 0 {
+		t.Errorf("case 1 rotated %d times, want 0", tree.rotations)
+	}
+}
+
+// A black leaf placed under a black root leaves the NIL sentinel as the
+// sibling of the node the delete fixup starts from
+func TestDeleteRefusesUnequalBlackHeights(t *testing.T) {
+	tree := NewRedBlackTree()
+	tree.InsertRaw(10, Black, nil, "")
+	if result := tree.InsertRaw(5, Black, intPtr(tree.Root.ID), "left"); !result.Success {
+		t.Fatalf("raw insert failed: %s", result.Message)
+	}
+
+	result := tree.Delete(5)
+	if result.Success || result.Code != CodeInvalidState {
+		t.Fatalf("delete: success=%v code=%q, want failure with %q", result.Success, result.Code, CodeInvalidState)
+	}
+	if tree.doubleBlack != nil {
+		t.Error("double-black marker left set")
+	}
+	if got := tree.Values(); len(got) != 2 || got[0] != 5 || got[1] != 10 {
+		t.Errorf("tree changed to %v", got)
+	}
+
+	if result := tree.DeleteMany([]int{5, 10}); result.Success || result.Code != CodeInvalidState {
+		t.Fatalf("bulk delete: success=%v code=%q", result.Success, result.Code)
+	}
+}
+
+func TestBalanceCheckOnlyFollowsRawPlacement(t *testing.T) {
+	tree := NewRedBlackTree()
+	for _, v := range []int{10, 5, 15} {
+		tree.Insert(v)
+	}
+	if tree.placedRaw {
+		t.Fatal("ordinary inserts flagged the tree as raw")
+	}
+
+	tree = NewRedBlackTree()
+	tree.InsertRaw(10, Black, nil, "")
+	tree.InsertRaw(5, Red, intPtr(tree.Root.ID), "left")
+	if !tree.placedRaw {
+		t.Fatal("raw insert did not flag the tree")
+	}
+	if result := tree.Delete(5); !result.Success {
+		t.Fatalf("delete from a balanced raw tree: %s", result.Message)
+	}
+	if tree.placedRaw {
+		t.Error("flag not cleared once the tree validated")
+	}
+
+	imported, err := ImportRedBlackTree(tree.Export())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imported.placedRaw {
+		t.Error("imported tree not flagged for the balance check")
+	}
+}
@@ -1,6 +1,9 @@
 package datastructures
 
-import "fmt"
+import (
+	"context"
+	"time"
+)
 
 // RBNode represents a node in the Red-Black Tree
 type RBNode struct {
@@ -18,22 +21,109 @@ type RedBlackTree struct {
 	NIL    *RBNode
 	nextID int
 	steps  []Step
+
+	// started is when the current operation began, the origin of its step
+	// timestamps
+	started time.Time
+
+	// valueIDs maps every value ever inserted to its permanent node ID
+	valueIDs map[int]int
+
+	// threads marks the nodes whose Right pointer is a temporary Morris
+	// thread; snapshots do not follow them
+	threads map[*RBNode]bool
+
+	// layout is the canvas size snapshots are laid out for
+	layout TreeLayout
+
+	// includeSnapshots controls whether every step embeds a full TreeState
+	includeSnapshots bool
+
+	// operand is the value the current insert/delete/search works on and is
+	// attached to every step it records
+	operand *int
+
+	// rotations and colorChanges count balancing work of the current operation
+	rotations    int
+	colorChanges int
+
+	// pendingColors collects recolorings until the next color-change step
+	pendingColors []ColorChange
+
+	// invariant is the property the running fixup restores; every step
+	// recorded meanwhile carries it
+	invariant string
+
+	// placedRaw is set once nodes were placed or imported without
+	// rebalancing, so the black-heights may differ; Delete checks them
+	// while it is set
+	placedRaw bool
+
+	// doubleBlack is the node x of a running delete fixup, which carries an
+	// extra black until the fixup absorbs it; nil otherwise
+	doubleBlack *RBNode
+
+	// locale selects the language of step descriptions and messages
+	locale Locale
+
+	// ctx aborts long-running operations when canceled
+	ctx context.Context
 }
 
 // NewRedBlackTree creates a new Red-Black Tree
 func NewRedBlackTree() *RedBlackTree {
 	nil := &RBNode{Color: Black, ID: -1}
 	return &RedBlackTree{
-		Root:   nil,
-		NIL:    nil,
-		nextID: 0,
-		steps:  make([]Step, 0),
+		Root:             nil,
+		NIL:              nil,
+		nextID:           0,
+		steps:            make([]Step, 0),
+		includeSnapshots: true,
+		layout:           newTreeLayout(0, 0),
 	}
 }
 
+// SetIncludeSnapshots toggles per-step tree snapshots. The final tree
+// snapshot of an operation is always produced.
+func (t *RedBlackTree) SetIncludeSnapshots(include bool) {
+	t.includeSnapshots = include
+}
+
+// SetLayout sets the canvas width and the distance between levels of the
+// snapshots. Non-positive values restore the defaults.
+func (t *RedBlackTree) SetLayout(width, levelHeight float64) {
+	t.layout = newTreeLayout(width, levelHeight)
+}
+
+// SetLocale selects the language of step descriptions and messages
+func (t *RedBlackTree) SetLocale(locale Locale) {
+	t.locale = locale
+}
+
+func (t *RedBlackTree) msg(key string, args ...interface{}) string {
+	return Localize(t.locale, key, args...)
+}
+
 // clearSteps resets the step tracking
 func (t *RedBlackTree) clearSteps() {
 	t.steps = make([]Step, 0)
+	t.started = time.Now()
+	t.rotations = 0
+	t.operand = nil
+	t.colorChanges = 0
+	t.pendingColors = nil
+}
+
+// newResult builds an OperationResult from the steps recorded so far
+func (t *RedBlackTree) newResult(success bool, message string) OperationResult {
+	return OperationResult{
+		Success:          success,
+		Message:          message,
+		Steps:            t.steps,
+		FinalTree:        t.getTreeSnapshot(),
+		RotationCount:    t.rotations,
+		ColorChangeCount: t.colorChanges,
+	}
 }
 
 // addStep records a step in the algorithm
@@ -42,20 +132,55 @@ func (t *RedBlackTree) addStep(stepType StepType, desc string, nodeID *int, extr
 		Type:        stepType,
 		Description: desc,
 		NodeID:      nodeID,
-		TreeState:   t.getTreeSnapshot(),
+		Value:       t.operand,
+		Invariant:   t.invariant,
+	}
+	if t.includeSnapshots {
+		step.TreeState = t.getTreeSnapshot()
 	}
 	if len(extra) > 0 {
 		if highlights, ok := extra[0].([]int); ok {
 			step.Highlight = highlights
 		}
 	}
+	step.stamp(len(t.steps), t.started)
 	t.steps = append(t.steps, step)
 }
 
+// setColor recolors a node and remembers the transition for the next
+// color-change step. Assigning a node its current color is not recorded.
+func (t *RedBlackTree) setColor(node *RBNode, color NodeColor) {
+	if node.Color == color {
+		return
+	}
+	t.pendingColors = append(t.pendingColors, ColorChange{
+		NodeID:   node.ID,
+		Value:    node.Value,
+		OldColor: node.Color,
+		NewColor: color,
+	})
+	node.Color = color
+}
+
+// addColorChangeStep records a recoloring step carrying every transition
+// made since the previous one, and counts it
+func (t *RedBlackTree) addColorChangeStep(desc string, nodeID *int) {
+	t.colorChanges++
+	t.addStep(StepColorChange, desc, nodeID)
+
+	step := &t.steps[len(t.steps)-1]
+	step.ColorChanges = t.pendingColors
+	if len(t.pendingColors) == 1 {
+		step.OldColor = t.pendingColors[0].OldColor
+		step.NewColor = t.pendingColors[0].NewColor
+	}
+	t.pendingColors = nil
+}
+
 // getTreeSnapshot creates a snapshot of the current tree state
 func (t *RedBlackTree) getTreeSnapshot() []TreeNodeSnapshot {
 	var nodes []TreeNodeSnapshot
-	t.inorderSnapshot(t.Root, &nodes, 0, 0, 800)
+	t.inorderSnapshot(t.Root, &nodes, 0, 0, t.layout.Width)
 	return nodes
 }
 
@@ -65,7 +190,7 @@ func (t *RedBlackTree) inorderSnapshot(node *RBNode, nodes *[]TreeNodeSnapshot,
 	}
 
 	x := (xMin + xMax) / 2
-	y := float64(depth*80 + 50)
+	y := t.layout.levelY(depth)
 
 	snapshot := TreeNodeSnapshot{
 		ID:    node.ID,
@@ -79,19 +204,36 @@ func (t *RedBlackTree) inorderSnapshot(node *RBNode, nodes *[]TreeNodeSnapshot,
 		leftID := node.Left.ID
 		snapshot.LeftID = &leftID
 	}
-	if node.Right != t.NIL && node.Right != nil {
-		rightID := node.Right.ID
+	right := node.Right
+	if t.threads[node] {
+		threadID := right.ID
+		snapshot.ThreadID = &threadID
+		right = t.NIL
+	}
+	if right != t.NIL && right != nil {
+		rightID := right.ID
 		snapshot.RightID = &rightID
 	}
 	if node.Parent != t.NIL && node.Parent != nil {
 		parentID := node.Parent.ID
 		snapshot.ParentID = &parentID
 	}
+	if t.doubleBlack == node {
+		snapshot.DoubleBlack = true
+	}
+	if t.doubleBlack == t.NIL && t.NIL.Parent == node {
+		// The fixup treats the sentinel as a left child whenever the left
+		// slot is empty, so the same rule picks the side shown here
+		snapshot.DoubleBlackLeaf = "right"
+		if node.Left == t.NIL {
+			snapshot.DoubleBlackLeaf = "left"
+		}
+	}
 
 	*nodes = append(*nodes, snapshot)
 
 	t.inorderSnapshot(node.Left, nodes, depth+1, xMin, x)
-	t.inorderSnapshot(node.Right, nodes, depth+1, x, xMax)
+	t.inorderSnapshot(right, nodes, depth+1, x, xMax)
 }
 
 // leftRotate performs a left rotation
@@ -112,7 +254,8 @@ func (t *RedBlackTree) leftRotate(x *RBNode) {
 	y.Left = x
 	x.Parent = y
 
-	t.addStep(StepRotateLeft, fmt.Sprintf("对节点 %d 进行左旋", x.Value), &x.ID, []int{x.ID, y.ID})
+	t.rotations++
+	t.addStep(StepRotateLeft, t.msg("tree.rotate_left", x.Value), &x.ID, []int{x.ID, y.ID})
 }
 
 // rightRotate performs a right rotation
@@ -133,25 +276,27 @@ func (t *RedBlackTree) rightRotate(y *RBNode) {
 	x.Right = y
 	y.Parent = x
 
-	t.addStep(StepRotateRight, fmt.Sprintf("对节点 %d 进行右旋", y.Value), &y.ID, []int{x.ID, y.ID})
+	t.rotations++
+	t.addStep(StepRotateRight, t.msg("tree.rotate_right", y.Value), &y.ID, []int{x.ID, y.ID})
 }
 
 // Insert inserts a value into the Red-Black Tree
 func (t *RedBlackTree) Insert(value int) OperationResult {
 	t.clearSteps()
+	t.operand = &value
 
 	// Create new node
 	z := &RBNode{
-		ID:     t.nextID,
+		ID:     t.idFor(value),
 		Value:  value,
 		Color:  Red,
 		Left:   t.NIL,
 		Right:  t.NIL,
 		Parent: t.NIL,
 	}
-	t.nextID++
 
-	t.addStep(StepInsert, fmt.Sprintf("创建新节点 %d (红色)", value), &z.ID)
+	// z is not linked yet, so the step cannot reference it
+	t.addStep(StepInsert, t.msg("rb.insert.create", value), nil)
 
 	// BST insert
 	var y *RBNode = t.NIL
@@ -159,7 +304,7 @@ func (t *RedBlackTree) Insert(value int) OperationResult {
 
 	for x != t.NIL {
 		y = x
-		t.addStep(StepCompare, fmt.Sprintf("比较 %d 与节点 %d", value, x.Value), &x.ID, []int{x.ID})
+		t.addStep(StepCompare, t.msg("tree.compare", value, x.Value), &x.ID, []int{x.ID})
 		if z.Value < x.Value {
 			x = x.Left
 		} else {
@@ -170,53 +315,60 @@ func (t *RedBlackTree) Insert(value int) OperationResult {
 	z.Parent = y
 	if y == t.NIL {
 		t.Root = z
-		t.addStep(StepInsert, fmt.Sprintf("节点 %d 成为根节点", value), &z.ID)
+		t.addStep(StepInsert, t.msg("rb.insert.root", value), &z.ID)
 	} else if z.Value < y.Value {
 		y.Left = z
-		t.addStep(StepInsert, fmt.Sprintf("节点 %d 作为 %d 的左子节点", value, y.Value), &z.ID, []int{y.ID, z.ID})
+		t.addStep(StepInsert, t.msg("rb.insert.left", value, y.Value), &z.ID, []int{y.ID, z.ID})
 	} else {
 		y.Right = z
-		t.addStep(StepInsert, fmt.Sprintf("节点 %d 作为 %d 的右子节点", value, y.Value), &z.ID, []int{y.ID, z.ID})
+		t.addStep(StepInsert, t.msg("rb.insert.right", value, y.Value), &z.ID, []int{y.ID, z.ID})
 	}
 
 	// Fix Red-Black properties
 	t.insertFixup(z)
 
-	t.addStep(StepComplete, "插入完成", nil)
+	t.addStep(StepComplete, t.msg("tree.insert.done"), nil)
 
-	return OperationResult{
-		Success:   true,
-		Steps:     t.steps,
-		FinalTree: t.getTreeSnapshot(),
-	}
+	return t.newResult(true, "")
 }
 
-// insertFixup fixes Red-Black Tree properties after insertion
+// insertFixup fixes Red-Black Tree properties after insertion. Its steps
+// are bracketed by fixup start and done steps without a node.
 func (t *RedBlackTree) insertFixup(z *RBNode) {
+	if z.Parent.Color != Red && t.Root.Color != Red {
+		return
+	}
+	t.invariant = t.msg("rb.invariant.red_red")
+	t.addStep(StepRebalance, t.msg("tree.fixup.start"), nil)
+	defer func() {
+		t.addStep(StepRebalance, t.msg("tree.fixup.done"), nil)
+		t.invariant = ""
+	}()
+
 	for z.Parent != t.NIL && z.Parent.Color == Red {
 		if z.Parent == z.Parent.Parent.Left {
 			y := z.Parent.Parent.Right // uncle
 			if y != t.NIL && y.Color == Red {
 				// Case 1: Uncle is red
-				t.addStep(StepRebalance, "情况1: 叔节点为红色，重新着色", &z.ID, []int{z.ID, z.Parent.ID, y.ID})
-				z.Parent.Color = Black
-				y.Color = Black
-				z.Parent.Parent.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("节点 %d, %d 变黑，%d 变红",
+				t.addStep(StepRebalance, t.msg("rb.insert.case1"), &z.ID, []int{z.ID, z.Parent.ID, y.ID})
+				t.setColor(z.Parent, Black)
+				t.setColor(y, Black)
+				t.setColor(z.Parent.Parent, Red)
+				t.addColorChangeStep(t.msg("rb.insert.case1_recolor",
 					z.Parent.Value, y.Value, z.Parent.Parent.Value), &z.Parent.Parent.ID)
 				z = z.Parent.Parent
 			} else {
 				if z == z.Parent.Right {
 					// Case 2: Uncle is black, z is right child
 					z = z.Parent
-					t.addStep(StepRebalance, "情况2: 叔节点为黑色，当前节点是右子节点", &z.ID)
+					t.addStep(StepRebalance, t.msg("rb.insert.case2"), &z.ID)
 					t.leftRotate(z)
 				}
 				// Case 3: Uncle is black, z is left child
-				t.addStep(StepRebalance, "情况3: 叔节点为黑色，当前节点是左子节点", &z.ID)
-				z.Parent.Color = Black
-				z.Parent.Parent.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("节点 %d 变黑，%d 变红",
+				t.addStep(StepRebalance, t.msg("rb.insert.case3"), &z.ID)
+				t.setColor(z.Parent, Black)
+				t.setColor(z.Parent.Parent, Red)
+				t.addColorChangeStep(t.msg("rb.insert.case3_recolor",
 					z.Parent.Value, z.Parent.Parent.Value), &z.Parent.ID)
 				t.rightRotate(z.Parent.Parent)
 			}
@@ -224,49 +376,53 @@ func (t *RedBlackTree) insertFixup(z *RBNode) {
 			// Mirror cases
 			y := z.Parent.Parent.Left // uncle
 			if y != t.NIL && y.Color == Red {
-				t.addStep(StepRebalance, "情况1(镜像): 叔节点为红色，重新着色", &z.ID, []int{z.ID, z.Parent.ID, y.ID})
-				z.Parent.Color = Black
-				y.Color = Black
-				z.Parent.Parent.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("节点 %d, %d 变黑，%d 变红",
+				t.addStep(StepRebalance, t.msg("rb.insert.case1_mirror"), &z.ID, []int{z.ID, z.Parent.ID, y.ID})
+				t.setColor(z.Parent, Black)
+				t.setColor(y, Black)
+				t.setColor(z.Parent.Parent, Red)
+				t.addColorChangeStep(t.msg("rb.insert.case1_recolor",
 					z.Parent.Value, y.Value, z.Parent.Parent.Value), &z.Parent.Parent.ID)
 				z = z.Parent.Parent
 			} else {
 				if z == z.Parent.Left {
 					z = z.Parent
-					t.addStep(StepRebalance, "情况2(镜像): 叔节点为黑色，当前节点是左子节点", &z.ID)
+					t.addStep(StepRebalance, t.msg("rb.insert.case2_mirror"), &z.ID)
 					t.rightRotate(z)
 				}
-				t.addStep(StepRebalance, "情况3(镜像): 叔节点为黑色，当前节点是右子节点", &z.ID)
-				z.Parent.Color = Black
-				z.Parent.Parent.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("节点 %d 变黑，%d 变红",
+				t.addStep(StepRebalance, t.msg("rb.insert.case3_mirror"), &z.ID)
+				t.setColor(z.Parent, Black)
+				t.setColor(z.Parent.Parent, Red)
+				t.addColorChangeStep(t.msg("rb.insert.case3_recolor",
 					z.Parent.Value, z.Parent.Parent.Value), &z.Parent.ID)
 				t.leftRotate(z.Parent.Parent)
 			}
 		}
 	}
 	if t.Root.Color == Red {
-		t.Root.Color = Black
-		t.addStep(StepColorChange, "根节点变黑", &t.Root.ID)
+		t.setColor(t.Root, Black)
+		t.addColorChangeStep(t.msg("rb.insert.root_black"), &t.Root.ID)
 	}
 }
 
 // Search searches for a value in the Red-Black Tree
 func (t *RedBlackTree) Search(value int) OperationResult {
 	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
 
 	x := t.Root
 	for x != t.NIL {
-		t.addStep(StepCompare, fmt.Sprintf("比较 %d 与节点 %d", value, x.Value), &x.ID, []int{x.ID})
+		t.addStep(StepCompare, t.msg("tree.compare", value, x.Value), &x.ID, []int{x.ID})
 		if value == x.Value {
-			t.addStep(StepFound, fmt.Sprintf("找到节点 %d", value), &x.ID, []int{x.ID})
-			return OperationResult{
-				Success:   true,
-				Message:   fmt.Sprintf("找到值 %d", value),
-				Steps:     t.steps,
-				FinalTree: t.getTreeSnapshot(),
-			}
+			t.addStep(StepFound, t.msg("tree.search.found_node", value), &x.ID, []int{x.ID})
+			return t.newResult(true, t.msg("tree.search.found", value))
 		} else if value < x.Value {
 			x = x.Left
 		} else {
@@ -274,13 +430,11 @@ func (t *RedBlackTree) Search(value int) OperationResult {
 		}
 	}
 
-	t.addStep(StepNotFound, fmt.Sprintf("值 %d 不存在于树中", value), nil)
-	return OperationResult{
-		Success:   false,
-		Message:   fmt.Sprintf("值 %d 不存在", value),
-		Steps:     t.steps,
-		FinalTree: t.getTreeSnapshot(),
-	}
+	t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
 }
 
 // transplant replaces subtree rooted at u with subtree rooted at v
@@ -318,24 +472,89 @@ func (t *RedBlackTree) searchNode(value int) *RBNode {
 	return t.NIL
 }
 
+// Contains reports whether value is in the Red-Black Tree without
+// recording steps
+func (t *RedBlackTree) Contains(value int) bool {
+	return t.searchNode(value) != t.NIL
+}
+
 // Delete deletes a value from the Red-Black Tree
 func (t *RedBlackTree) Delete(value int) OperationResult {
 	t.clearSteps()
+	t.operand = &value
 
 	// Search for the node to delete
 	z := t.searchNode(value)
 
 	if z == t.NIL {
-		t.addStep(StepNotFound, fmt.Sprintf("值 %d 不存在于树中，无法删除", value), nil)
-		return OperationResult{
-			Success:   false,
-			Message:   fmt.Sprintf("值 %d 不存在，无法删除", value),
-			Steps:     t.steps,
-			FinalTree: t.getTreeSnapshot(),
+		t.addStep(StepNotFound, t.msg("tree.delete.missing_step", value), nil)
+		result := t.newResult(false, t.msg("tree.delete.missing", value))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		if t.Root == t.NIL {
+			result.Reason = ReasonEmptyTree
 		}
+		return result
+	}
+	if t.rawUnbalanced() {
+		return t.unbalancedResult()
 	}
 
-	t.addStep(StepDelete, fmt.Sprintf("找到要删除的节点 %d", value), &z.ID, []int{z.ID})
+	t.deleteNode(z)
+
+	t.addStep(StepComplete, t.msg("tree.delete.done", value), nil)
+
+	return t.newResult(true, t.msg("tree.delete.success", value))
+}
+
+// DeleteMany deletes several values in sequence, recording every removal
+// and its rebalancing in a single step log. Values that are not present
+// are reported in the message and skipped.
+func (t *RedBlackTree) DeleteMany(values []int) OperationResult {
+	t.clearSteps()
+	if t.rawUnbalanced() {
+		return t.unbalancedResult()
+	}
+	t.addStep(StepDelete, t.msg("rb.bulk_delete.start", len(values)), nil)
+
+	deleted := 0
+	missing := make([]int, 0)
+	for _, value := range values {
+		if contextDone(t.ctx) {
+			return t.canceledResult()
+		}
+		v := value
+		t.operand = &v
+
+		z := t.searchNode(value)
+		if z == t.NIL {
+			t.addStep(StepNotFound, t.msg("rb.bulk_delete.skip", value), nil)
+			missing = append(missing, value)
+			continue
+		}
+		t.deleteNode(z)
+		deleted++
+	}
+	t.operand = nil
+
+	t.addStep(StepComplete, t.msg("rb.bulk_delete.done", deleted, len(missing)), nil)
+
+	message := t.msg("rb.bulk_delete.success", deleted)
+	if len(missing) > 0 {
+		message += t.msg("rb.bulk_delete.missing", missing)
+	}
+	result := t.newResult(deleted > 0, message)
+	if deleted == 0 {
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+	}
+	return result
+}
+
+// deleteNode removes z from the tree and restores the Red-Black properties,
+// recording each step
+func (t *RedBlackTree) deleteNode(z *RBNode) {
+	t.addStep(StepDelete, t.msg("tree.delete.found", z.Value), &z.ID, []int{z.ID})
 
 	y := z
 	yOriginalColor := y.Color
@@ -343,12 +562,12 @@ func (t *RedBlackTree) Delete(value int) OperationResult {
 
 	if z.Left == t.NIL {
 		// Case 1: No left child
-		t.addStep(StepDelete, fmt.Sprintf("节点 %d 没有左子节点，用右子节点替换", z.Value), &z.ID)
+		t.addStep(StepDelete, t.msg("tree.delete.no_left", z.Value), &z.ID)
 		x = z.Right
 		t.transplant(z, z.Right)
 	} else if z.Right == t.NIL {
 		// Case 2: No right child
-		t.addStep(StepDelete, fmt.Sprintf("节点 %d 没有右子节点，用左子节点替换", z.Value), &z.ID)
+		t.addStep(StepDelete, t.msg("tree.delete.no_right", z.Value), &z.ID)
 		x = z.Left
 		t.transplant(z, z.Left)
 	} else {
@@ -356,7 +575,7 @@ func (t *RedBlackTree) Delete(value int) OperationResult {
 		y = t.minimum(z.Right)
 		yOriginalColor = y.Color
 		x = y.Right
-		t.addStep(StepDelete, fmt.Sprintf("节点 %d 有两个子节点，找到后继节点 %d", z.Value, y.Value), &y.ID, []int{z.ID, y.ID})
+		t.addStep(StepDelete, t.msg("tree.delete.successor", z.Value, y.Value), &y.ID, []int{z.ID, y.ID})
 
 		if y.Parent == z {
 			x.Parent = y
@@ -369,61 +588,78 @@ func (t *RedBlackTree) Delete(value int) OperationResult {
 		y.Left = z.Left
 		y.Left.Parent = y
 		y.Color = z.Color
-		t.addStep(StepDelete, fmt.Sprintf("用后继节点 %d 替换被删除节点", y.Value), &y.ID)
+		t.addStep(StepDelete, t.msg("tree.delete.replace", y.Value), &y.ID)
 	}
 
 	// Fix Red-Black Tree properties if needed
 	if yOriginalColor == Black {
-		t.addStep(StepRebalance, "删除了黑色节点，需要修复红黑树性质", nil)
+		t.invariant = t.msg("rb.invariant.black_height")
+		t.markDoubleBlack(x)
+		t.addStep(StepRebalance, t.msg("rb.delete.fixup"), nil)
 		t.deleteFixup(x)
+		t.addStep(StepRebalance, t.msg("tree.fixup.done"), nil)
+		t.invariant = ""
 	}
+}
 
-	t.addStep(StepComplete, fmt.Sprintf("删除节点 %d 完成", value), nil)
+// linkedIDs returns the IDs of the given nodes, leaving out the NIL sentinel,
+// which never appears in snapshots
+func (t *RedBlackTree) linkedIDs(nodes ...*RBNode) []int {
+	ids := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		if node != t.NIL {
+			ids = append(ids, node.ID)
+		}
+	}
+	return ids
+}
 
-	return OperationResult{
-		Success:   true,
-		Message:   fmt.Sprintf("成功删除值 %d", value),
-		Steps:     t.steps,
-		FinalTree: t.getTreeSnapshot(),
+// markDoubleBlack marks x as the node carrying the extra black, or clears
+// the marker once x is red or the root and the extra black is absorbed
+func (t *RedBlackTree) markDoubleBlack(x *RBNode) {
+	t.doubleBlack = nil
+	if x != t.Root && x.Color == Black {
+		t.doubleBlack = x
 	}
 }
 
 // deleteFixup fixes Red-Black Tree properties after deletion
 func (t *RedBlackTree) deleteFixup(x *RBNode) {
 	for x != t.Root && x.Color == Black {
+		t.markDoubleBlack(x)
 		if x == x.Parent.Left {
 			w := x.Parent.Right // sibling
 			if w.Color == Red {
 				// Case 1: Sibling is red
-				t.addStep(StepRebalance, "情况1: 兄弟节点为红色", &w.ID, []int{x.ID, w.ID})
-				w.Color = Black
-				x.Parent.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("兄弟节点 %d 变黑，父节点 %d 变红", w.Value, x.Parent.Value), &x.Parent.ID)
+				t.addStep(StepRebalance, t.msg("rb.delete.case1"), &w.ID, t.linkedIDs(x, w))
+				t.setColor(w, Black)
+				t.setColor(x.Parent, Red)
+				t.addColorChangeStep(t.msg("rb.delete.case1_recolor", w.Value, x.Parent.Value), &x.Parent.ID)
 				t.leftRotate(x.Parent)
 				w = x.Parent.Right
 			}
 			if w.Left.Color == Black && w.Right.Color == Black {
 				// Case 2: Sibling is black with two black children
-				t.addStep(StepRebalance, "情况2: 兄弟节点为黑色，其两个子节点均为黑色", &w.ID, []int{w.ID})
-				w.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("兄弟节点 %d 变红", w.Value), &w.ID)
+				t.addStep(StepRebalance, t.msg("rb.delete.case2"), &w.ID, []int{w.ID})
+				t.setColor(w, Red)
+				t.addColorChangeStep(t.msg("rb.delete.case2_recolor", w.Value), &w.ID)
 				x = x.Parent
 			} else {
 				if w.Right.Color == Black {
 					// Case 3: Sibling is black, left child is red, right child is black
-					t.addStep(StepRebalance, "情况3: 兄弟节点为黑色，左子为红，右子为黑", &w.ID)
-					w.Left.Color = Black
-					w.Color = Red
-					t.addStep(StepColorChange, fmt.Sprintf("兄弟左子节点变黑，兄弟 %d 变红", w.Value), &w.ID)
+					t.addStep(StepRebalance, t.msg("rb.delete.case3"), &w.ID)
+					t.setColor(w.Left, Black)
+					t.setColor(w, Red)
+					t.addColorChangeStep(t.msg("rb.delete.case3_recolor", w.Value), &w.ID)
 					t.rightRotate(w)
 					w = x.Parent.Right
 				}
 				// Case 4: Sibling is black with red right child
-				t.addStep(StepRebalance, "情况4: 兄弟节点为黑色，右子为红色", &w.ID)
-				w.Color = x.Parent.Color
-				x.Parent.Color = Black
-				w.Right.Color = Black
-				t.addStep(StepColorChange, fmt.Sprintf("重新着色完成"), &w.ID)
+				t.addStep(StepRebalance, t.msg("rb.delete.case4"), &w.ID)
+				t.setColor(w, x.Parent.Color)
+				t.setColor(x.Parent, Black)
+				t.setColor(w.Right, Black)
+				t.addColorChangeStep(t.msg("rb.delete.case4_recolor"), &w.ID)
 				t.leftRotate(x.Parent)
 				x = t.Root
 			}
@@ -431,39 +667,74 @@ func (t *RedBlackTree) deleteFixup(x *RBNode) {
 			// Mirror cases
 			w := x.Parent.Left // sibling
 			if w.Color == Red {
-				t.addStep(StepRebalance, "情况1(镜像): 兄弟节点为红色", &w.ID, []int{x.ID, w.ID})
-				w.Color = Black
-				x.Parent.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("兄弟节点 %d 变黑，父节点 %d 变红", w.Value, x.Parent.Value), &x.Parent.ID)
+				t.addStep(StepRebalance, t.msg("rb.delete.case1_mirror"), &w.ID, t.linkedIDs(x, w))
+				t.setColor(w, Black)
+				t.setColor(x.Parent, Red)
+				t.addColorChangeStep(t.msg("rb.delete.case1_recolor", w.Value, x.Parent.Value), &x.Parent.ID)
 				t.rightRotate(x.Parent)
 				w = x.Parent.Left
 			}
 			if w.Right.Color == Black && w.Left.Color == Black {
-				t.addStep(StepRebalance, "情况2(镜像): 兄弟节点为黑色，其两个子节点均为黑色", &w.ID, []int{w.ID})
-				w.Color = Red
-				t.addStep(StepColorChange, fmt.Sprintf("兄弟节点 %d 变红", w.Value), &w.ID)
+				t.addStep(StepRebalance, t.msg("rb.delete.case2_mirror"), &w.ID, []int{w.ID})
+				t.setColor(w, Red)
+				t.addColorChangeStep(t.msg("rb.delete.case2_recolor", w.Value), &w.ID)
 				x = x.Parent
 			} else {
 				if w.Left.Color == Black {
-					t.addStep(StepRebalance, "情况3(镜像): 兄弟节点为黑色，右子为红，左子为黑", &w.ID)
-					w.Right.Color = Black
-					w.Color = Red
-					t.addStep(StepColorChange, fmt.Sprintf("兄弟右子节点变黑，兄弟 %d 变红", w.Value), &w.ID)
+					t.addStep(StepRebalance, t.msg("rb.delete.case3_mirror"), &w.ID)
+					t.setColor(w.Right, Black)
+					t.setColor(w, Red)
+					t.addColorChangeStep(t.msg("rb.delete.case3_mirror_recolor", w.Value), &w.ID)
 					t.leftRotate(w)
 					w = x.Parent.Left
 				}
-				t.addStep(StepRebalance, "情况4(镜像): 兄弟节点为黑色，左子为红色", &w.ID)
-				w.Color = x.Parent.Color
-				x.Parent.Color = Black
-				w.Left.Color = Black
-				t.addStep(StepColorChange, fmt.Sprintf("重新着色完成"), &w.ID)
+				t.addStep(StepRebalance, t.msg("rb.delete.case4_mirror"), &w.ID)
+				t.setColor(w, x.Parent.Color)
+				t.setColor(x.Parent, Black)
+				t.setColor(w.Left, Black)
+				t.addColorChangeStep(t.msg("rb.delete.case4_recolor"), &w.ID)
 				t.rightRotate(x.Parent)
 				x = t.Root
 			}
 		}
 	}
+	t.markDoubleBlack(x)
 	if x.Color == Red {
-		x.Color = Black
-		t.addStep(StepColorChange, "将当前节点变黑以完成修复", &x.ID)
+		t.setColor(x, Black)
+		t.addColorChangeStep(t.msg("rb.delete.finish"), &x.ID)
+	}
+}
+
+// State returns a snapshot of the tree without modifying it
+func (t *RedBlackTree) State() OperationResult {
+	return OperationResult{
+		Success:   true,
+		Message:   t.msg("tree.state", t.Size()),
+		Steps:     []Step{},
+		FinalTree: t.getTreeSnapshot(),
+	}
+}
+
+// Height returns the height of the Red-Black Tree (0 for an empty tree)
+func (t *RedBlackTree) Height() int {
+	return t.subtreeHeight(t.Root)
+}
+
+func (t *RedBlackTree) subtreeHeight(node *RBNode) int {
+	if node == t.NIL || node == nil {
+		return 0
+	}
+	return 1 + max(t.subtreeHeight(node.Left), t.subtreeHeight(node.Right))
+}
+
+// Size returns the number of nodes in the Red-Black Tree
+func (t *RedBlackTree) Size() int {
+	return t.subtreeSize(t.Root)
+}
+
+func (t *RedBlackTree) subtreeSize(node *RBNode) int {
+	if node == t.NIL || node == nil {
+		return 0
 	}
+	return 1 + t.subtreeSize(node.Left) + t.subtreeSize(node.Right)
 }
@@ -0,0 +1,302 @@
+package datastructures
+
+import (
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestRedBlackRecolorInsertDoesNotRotate(t *testing.T) {
+	tree := NewRedBlackTree()
+	for _, v := range []int{10, 5, 15} {
+		tree.Insert(v)
+	}
+	// 1 has a red parent and a red uncle, so the fixup only recolors
+	result := tree.Insert(1)
+	if result.RotationCount != 0 {
+		t.Errorf("rotation count %d, want 0", result.RotationCount)
+	}
+	if result.ColorChangeCount == 0 {
+		t.Error("no color changes reported")
+	}
+}
+
+func TestRedBlackRecolorStepsCarryColors(t *testing.T) {
+	tree := NewRedBlackTree()
+	for _, v := range []int{10, 5, 15} {
+		tree.Insert(v)
+	}
+	result := tree.Insert(1)
+
+	recolors := 0
+	for _, step := range result.Steps {
+		if step.Type != StepColorChange {
+			continue
+		}
+		recolors++
+		if len(step.ColorChanges) == 0 {
+			t.Fatalf("step %d records no color changes", step.Index)
+		}
+		for _, change := range step.ColorChanges {
+			if change.OldColor == change.NewColor {
+				t.Errorf("step %d: node %d recolored %s to %s", step.Index, change.Value, change.OldColor, change.NewColor)
+			}
+			for _, node := range step.TreeState {
+				if node.ID == change.NodeID && node.Color != change.NewColor {
+					t.Errorf("step %d: node %d is %s in the snapshot, want %s", step.Index, change.Value, node.Color, change.NewColor)
+				}
+			}
+		}
+		if len(step.ColorChanges) == 1 && (step.OldColor != step.ColorChanges[0].OldColor || step.NewColor != step.ColorChanges[0].NewColor) {
+			t.Errorf("step %d: colors %s→%s, want %s→%s", step.Index, step.OldColor, step.NewColor, step.ColorChanges[0].OldColor, step.ColorChanges[0].NewColor)
+		}
+	}
+	if recolors == 0 {
+		t.Fatal("no recolor steps")
+	}
+
+	// Recoloring 5 and 15 black and 10 red is a single step
+	first := result.Steps[slices.IndexFunc(result.Steps, func(s Step) bool { return s.Type == StepColorChange })]
+	want := map[int][2]NodeColor{5: {Red, Black}, 15: {Red, Black}, 10: {Black, Red}}
+	for _, change := range first.ColorChanges {
+		if got := [2]NodeColor{change.OldColor, change.NewColor}; got != want[change.Value] {
+			t.Errorf("node %d recolored %v, want %v", change.Value, got, want[change.Value])
+		}
+	}
+	if len(first.ColorChanges) != len(want) {
+		t.Errorf("first recolor step changes %d nodes, want %d", len(first.ColorChanges), len(want))
+	}
+}
+
+// checkRedBlack fails t unless tree is a valid Red-Black tree: a BST with
+// consistent parent links, a black root, no red node with a red child and
+// equal black-heights on every path
+func checkRedBlack(t *testing.T, tree *RedBlackTree) {
+	t.Helper()
+	if tree.Root.Color != Black {
+		t.Fatalf("root %d is %s", tree.Root.Value, tree.Root.Color)
+	}
+	var walk func(node *RBNode, lo, hi *int) int
+	walk = func(node *RBNode, lo, hi *int) int {
+		if node == tree.NIL {
+			return 1
+		}
+		if (lo != nil && node.Value < *lo) || (hi != nil && node.Value > *hi) {
+			t.Fatalf("node %d breaks the BST order", node.Value)
+		}
+		for _, child := range []*RBNode{node.Left, node.Right} {
+			if child == tree.NIL {
+				continue
+			}
+			if child.Parent != node {
+				t.Fatalf("node %d does not link back to parent %d", child.Value, node.Value)
+			}
+			if node.Color == Red && child.Color == Red {
+				t.Fatalf("red node %d has red child %d", node.Value, child.Value)
+			}
+		}
+		left, right := walk(node.Left, lo, &node.Value), walk(node.Right, &node.Value, hi)
+		if left != right {
+			t.Fatalf("node %d has black-heights %d and %d", node.Value, left, right)
+		}
+		if node.Color == Black {
+			left++
+		}
+		return left
+	}
+	walk(tree.Root, nil, nil)
+}
+
+func TestRedBlackDeleteManyKeepsInvariants(t *testing.T) {
+	tree := NewRedBlackTree()
+	tree.SetIncludeSnapshots(false)
+	for v := 1; v <= 40; v++ {
+		tree.Insert(v)
+	}
+
+	result := tree.DeleteMany([]int{3, 99, 17, 18, 0, 40, 1, 25, 26, 27, -5})
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+	checkRedBlack(t, tree)
+	if tree.Size() != 32 {
+		t.Errorf("size %d, want 32", tree.Size())
+	}
+	for _, v := range []int{3, 17, 18, 40, 1, 25, 26, 27} {
+		if tree.Contains(v) {
+			t.Errorf("%d still present", v)
+		}
+	}
+	skipped := 0
+	for _, step := range result.Steps {
+		if step.Type == StepNotFound {
+			skipped++
+		}
+	}
+	if skipped != 3 {
+		t.Errorf("%d absent values skipped, want 3", skipped)
+	}
+
+	if none := tree.DeleteMany([]int{100, 200}); none.Success || !none.NoOp || none.Reason != ReasonNotFound {
+		t.Errorf("all absent: success %v noOp %v reason %q", none.Success, none.NoOp, none.Reason)
+	}
+}
+
+func TestRedBlackRebalanceReachesMinimalHeight(t *testing.T) {
+	for n := 1; n <= 40; n++ {
+		tree := NewRedBlackTree()
+		for v := 1; v <= n; v++ {
+			tree.Insert(v)
+		}
+		ids := make(map[int]int)
+		for _, node := range tree.getTreeSnapshot() {
+			ids[node.Value] = node.ID
+		}
+		before, values := tree.Height(), tree.Values()
+
+		if result := tree.Rebalance(); !result.Success {
+			t.Fatalf("n=%d: %s", n, result.Message)
+		}
+		if h := tree.Height(); h > before || h != bits.Len(uint(n)) {
+			t.Errorf("n=%d: height %d after rebalancing, was %d", n, h, before)
+		}
+		if got := tree.Values(); !slices.Equal(got, values) {
+			t.Fatalf("n=%d: values %v, want %v", n, got, values)
+		}
+		for _, node := range tree.getTreeSnapshot() {
+			if ids[node.Value] != node.ID {
+				t.Fatalf("n=%d: value %d moved from node %d to %d", n, node.Value, ids[node.Value], node.ID)
+			}
+		}
+		checkRedBlack(t, tree)
+	}
+}
+
+func TestRedBlackFixupStepsStateTheirInvariant(t *testing.T) {
+	tree := NewRedBlackTree()
+	redRed := Localize(LocaleZh, "rb.invariant.red_red")
+	blackHeight := Localize(LocaleZh, "rb.invariant.black_height")
+	check := func(result OperationResult, want string) (recolors, rotations int) {
+		t.Helper()
+		for _, step := range result.Steps {
+			switch step.Type {
+			case StepColorChange:
+				recolors++
+			case StepRotateLeft, StepRotateRight:
+				rotations++
+			case StepCompare:
+				if step.Invariant != "" {
+					t.Fatalf("search step %d states invariant %q", step.Index, step.Invariant)
+				}
+				continue
+			default:
+				continue
+			}
+			if step.Invariant != want {
+				t.Fatalf("%s step %d states %q, want %q", step.Type, step.Index, step.Invariant, want)
+			}
+		}
+		return recolors, rotations
+	}
+
+	recolors, rotations := 0, 0
+	for _, v := range rand.New(rand.NewSource(2)).Perm(64) {
+		c, r := check(tree.Insert(v), redRed)
+		recolors, rotations = recolors+c, rotations+r
+	}
+	if recolors == 0 || rotations == 0 {
+		t.Fatalf("inserts recolored %d and rotated %d times", recolors, rotations)
+	}
+
+	rotations = 0
+	for v := 0; v < 48; v++ {
+		_, r := check(tree.Delete(v), blackHeight)
+		rotations += r
+	}
+	if rotations == 0 {
+		t.Fatal("deletes never rotated")
+	}
+}
+
+// doubleBlackMarkers counts the nodes and leaves of nodes marked as
+// carrying the extra black
+func doubleBlackMarkers(nodes []TreeNodeSnapshot) int {
+	count := 0
+	for _, node := range nodes {
+		if node.DoubleBlack {
+			count++
+		}
+		if node.DoubleBlackLeaf != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRedBlackDeleteMarksOneDoubleBlack(t *testing.T) {
+	tree := NewRedBlackTree()
+	values := rand.New(rand.NewSource(4)).Perm(64)
+	for _, v := range values {
+		tree.Insert(v)
+	}
+
+	cases := 0
+	for _, v := range values {
+		result := tree.Delete(v)
+		for _, step := range result.Steps {
+			markers := doubleBlackMarkers(step.TreeState)
+			if markers > 1 {
+				t.Fatalf("delete %d step %d marks %d double blacks", v, step.Index, markers)
+			}
+			// Every case of the fixup loop works on the double black
+			if step.Type == StepRebalance && step.NodeID != nil {
+				cases++
+				if markers != 1 {
+					t.Fatalf("delete %d step %d (%s) marks %d double blacks", v, step.Index, step.Description, markers)
+				}
+			}
+		}
+		if len(result.Steps) > 0 && doubleBlackMarkers(result.Steps[len(result.Steps)-1].TreeState) != 0 {
+			t.Fatalf("delete %d ends with a double black marked", v)
+		}
+		if doubleBlackMarkers(result.FinalTree) != 0 {
+			t.Fatalf("delete %d leaves a double black in the final tree", v)
+		}
+	}
+	if cases == 0 {
+		t.Fatal("no delete entered the fixup loop")
+	}
+}
+
+func TestRedBlackBlackHeightMatchesHandCount(t *testing.T) {
+	tree := NewRedBlackTree()
+	for _, v := range []int{4, 2, 6, 1, 3, 5, 7, 8} {
+		tree.Insert(v)
+	}
+	// Inserting 8 recolors 5 and 7 black and 6 red, leaving
+	//
+	//	      4B
+	//	   /      \
+	//	  2B       6R
+	//	 /  \     /  \
+	//	1R  3R   5B   7B
+	//	                \
+	//	                8R
+	//
+	// Below the root every path to a leaf crosses one black node (2, 5 or
+	// 7); NIL leaves are not counted.
+	result := tree.BlackHeight(nil)
+	if result.BlackHeight == nil || *result.BlackHeight != 1 {
+		t.Fatalf("black height %v, want 1", result.BlackHeight)
+	}
+
+	value := 8
+	result = tree.BlackHeight(&value)
+	if !result.Success || result.BlackCount == nil || *result.BlackCount != 2 {
+		t.Fatalf("path to 8: success %v, black count %v, want 2 (4 and 7)", result.Success, result.BlackCount)
+	}
+	if len(result.PathIDs) != 4 {
+		t.Errorf("path to 8 has %d nodes, want 4", len(result.PathIDs))
+	}
+}
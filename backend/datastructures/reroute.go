@@ -0,0 +1,100 @@
+package datastructures
+
+// removeEdge drops every edge from→to, and to→from in an undirected graph,
+// returning the adjacency lists it replaced so the edge can be restored
+func (g *Graph) removeEdge(from, to string) map[string][]Edge {
+	saved := map[string][]Edge{from: g.Nodes[from]}
+	if !g.Directed {
+		saved[to] = g.Nodes[to]
+	}
+	for id, edges := range saved {
+		kept := make([]Edge, 0, len(edges))
+		for _, e := range edges {
+			if !g.sameEdge(id, e.To, from, to) {
+				kept = append(kept, e)
+			}
+		}
+		g.Nodes[id] = kept
+	}
+	return saved
+}
+
+// Reroute simulates a failure of the edge from→to. It finds the shortest
+// path from start to end, removes the edge along with any parallel copies,
+// runs Dijkstra again and then restores the edge. The steps show the
+// removal followed by the recomputed search; Paths holds the original
+// route first and the rerouted one second.
+func (g *Graph) Reroute(start, end, from, to string) OperationResult {
+	g.clearSteps()
+	started := g.started
+
+	for _, id := range []string{start, end, from, to} {
+		if !g.HasNode(id) {
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+	if !g.hasEdge(from, to) {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.reroute.no_edge", from, to),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}
+	}
+
+	originalPath, originalCost, ok := g.shortestPath(start, end, nil, nil)
+	if !ok {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.unreachable"),
+			Reason:  ReasonUnreachable,
+			NoOp:    true,
+			Steps:   []Step{},
+		}
+	}
+	original := PathResult{Nodes: originalPath, Cost: originalCost}
+
+	failed := &[2]string{from, to}
+	g.addStep(StepDelete, g.msg("graph.reroute.remove", from, to, originalPath, originalCost), nil, nil, originalPath, failed)
+	removal := g.steps
+
+	saved := g.removeEdge(from, to)
+	result := g.Dijkstra(start, end)
+	g.resumeClock(started, result.Steps)
+	for id, edges := range saved {
+		g.Nodes[id] = edges
+	}
+
+	g.steps = restampSteps(append(removal, result.Steps...))
+	if result.Reason == ReasonCanceled {
+		result.Steps = g.steps
+		return result
+	}
+	if !result.Success {
+		g.addStep(StepComplete, g.msg("graph.reroute.restored", from, to), nil, nil, originalPath, failed)
+		return OperationResult{
+			Success:    false,
+			Message:    g.msg("graph.reroute.disconnected", from, to, start, end),
+			Reason:     ReasonUnreachable,
+			NoOp:       true,
+			Steps:      g.steps,
+			FinalGraph: g.latest,
+			Paths:      []PathResult{original},
+		}
+	}
+
+	rerouted := result.Paths[0]
+	g.addStep(StepComplete, g.msg("graph.reroute.restored", from, to), nil, nil, rerouted.Nodes, failed)
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.reroute.success", from, to, originalCost, rerouted.Cost, rerouted.Cost-originalCost),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+		Paths:      []PathResult{original, rerouted},
+	}
+}
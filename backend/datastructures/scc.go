@@ -0,0 +1,114 @@
+package datastructures
+
+import "sort"
+
+// annotateComponents sets the component index of every node of the latest
+// step that has already been assigned to a component
+func (g *Graph) annotateComponents(component map[string]int) {
+	last := g.latest
+	for i := range last.Nodes {
+		if c, ok := component[last.Nodes[i].ID]; ok {
+			last.Nodes[i].Component = &c
+		}
+	}
+}
+
+// SCC computes the strongly connected components of a directed graph with
+// Tarjan's algorithm. Steps show every DFS visit, every low-link update and
+// every component popped off the stack. Nodes and neighbors are visited in
+// ID order so the component indices are deterministic.
+func (g *Graph) SCC() OperationResult {
+	g.clearSteps()
+
+	if !g.Directed {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.scc.undirected"),
+			Code:    CodeInvalidState,
+			Steps:   []Step{},
+		}
+	}
+
+	ids := g.sortedIDs()
+
+	index := make(map[string]int, len(ids))
+	low := make(map[string]int, len(ids))
+	onStack := make(map[string]bool, len(ids))
+	visited := make(map[string]bool, len(ids))
+	component := make(map[string]int, len(ids))
+	stack := make([]string, 0, len(ids))
+	counter, count := 0, 0
+
+	record := func(stepType StepType, desc string, path []string, edge *[2]string) {
+		g.addStep(stepType, desc, nil, visited, path, edge)
+		g.annotateComponents(component)
+	}
+
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		index[v], low[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		visited[v] = true
+		record(StepVisit, g.msg("graph.scc.visit", v, index[v]), nil, nil)
+
+		neighbors := make([]string, 0, len(g.Nodes[v]))
+		for _, e := range g.Nodes[v] {
+			neighbors = append(neighbors, e.To)
+		}
+		sort.Strings(neighbors)
+
+		for _, w := range neighbors {
+			edge := &[2]string{v, w}
+			if _, seen := index[w]; !seen {
+				strongConnect(w)
+				if low[w] < low[v] {
+					old := low[v]
+					low[v] = low[w]
+					record(StepCompare, g.msg("graph.scc.lowlink_child", v, old, low[v], w), nil, edge)
+				}
+			} else if onStack[w] && index[w] < low[v] {
+				old := low[v]
+				low[v] = index[w]
+				record(StepCompare, g.msg("graph.scc.lowlink_back", v, old, low[v], w), nil, edge)
+			}
+		}
+
+		if low[v] != index[v] {
+			return
+		}
+		members := make([]string, 0)
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component[top] = count
+			members = append(members, top)
+			if top == v {
+				break
+			}
+		}
+		sort.Strings(members)
+		record(StepFound, g.msg("graph.scc.component", count, v, members), members, nil)
+		count++
+	}
+
+	for _, id := range ids {
+		if contextDone(g.ctx) {
+			return g.canceledResult()
+		}
+		if _, seen := index[id]; !seen {
+			strongConnect(id)
+		}
+	}
+
+	record(StepComplete, g.msg("graph.scc.done", count), nil, nil)
+
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.scc.success", count),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+	}
+}
@@ -0,0 +1,99 @@
+package datastructures
+
+// SearchAll finds every node holding value. The AVL Tree rejects duplicate
+// inserts, so at most one node matches and the search is an ordinary
+// descent; it exists so both trees answer search_all the same way.
+func (t *AVLTree) SearchAll(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	matches := make([]int, 0, 1)
+	current := t.Root
+	for current != nil {
+		t.addStep(StepCompare, t.msg("tree.compare", value, current.Value), &current.ID, []int{current.ID})
+		if value == current.Value {
+			matches = append(matches, current.ID)
+			t.addStep(StepFound, t.msg("tree.search_all.match", value, len(matches)), &current.ID, matches)
+			break
+		} else if value < current.Value {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+	return t.searchAllResult(value, matches)
+}
+
+func (t *AVLTree) searchAllResult(value int, matches []int) OperationResult {
+	if len(matches) == 0 {
+		t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+		result := t.newResult(false, t.msg("tree.search.missing", value))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		return result
+	}
+	result := t.newResult(true, t.msg("tree.search_all.success", len(matches), value))
+	result.MatchIDs = matches
+	result.MatchCount = len(matches)
+	return result
+}
+
+// SearchAll finds every node holding value. Insert sends duplicates right,
+// but rotations can later lift a copy above an earlier one, so an equal
+// node may have copies in either subtree and both are searched.
+func (t *RedBlackTree) SearchAll(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	matches := make([]int, 0)
+	var search func(x *RBNode)
+	search = func(x *RBNode) {
+		if x == t.NIL {
+			return
+		}
+		t.addStep(StepCompare, t.msg("tree.compare", value, x.Value), &x.ID, []int{x.ID})
+		switch {
+		case value < x.Value:
+			search(x.Left)
+		case value > x.Value:
+			search(x.Right)
+		default:
+			matches = append(matches, x.ID)
+			t.addStep(StepFound, t.msg("tree.search_all.match", value, len(matches)), &x.ID, append([]int(nil), matches...))
+			search(x.Left)
+			search(x.Right)
+		}
+	}
+	search(t.Root)
+	return t.searchAllResult(value, matches)
+}
+
+func (t *RedBlackTree) searchAllResult(value int, matches []int) OperationResult {
+	if len(matches) == 0 {
+		t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+		result := t.newResult(false, t.msg("tree.search.missing", value))
+		result.Reason = ReasonNotFound
+		result.NoOp = true
+		return result
+	}
+	result := t.newResult(true, t.msg("tree.search_all.success", len(matches), value))
+	result.MatchIDs = matches
+	result.MatchCount = len(matches)
+	return result
+}
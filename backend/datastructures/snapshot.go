@@ -1,5 +1,11 @@
 package datastructures
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 // NodeColor represents the color of a node in Red-Black Tree
 type NodeColor string
 
@@ -12,22 +18,83 @@ const (
 type StepType string
 
 const (
-	StepInsert       StepType = "insert"
-	StepDelete       StepType = "delete"
-	StepRotateLeft   StepType = "rotate_left"
-	StepRotateRight  StepType = "rotate_right"
-	StepColorChange  StepType = "color_change"
-	StepCompare      StepType = "compare"
-	StepVisit        StepType = "visit"
-	StepFound        StepType = "found"
-	StepNotFound     StepType = "not_found"
-	StepUpdateDist   StepType = "update_distance"
-	StepSelectNode   StepType = "select_node"
-	StepMarkVisited  StepType = "mark_visited"
-	StepRebalance    StepType = "rebalance"
+	StepInsert      StepType = "insert"
+	StepDelete      StepType = "delete"
+	StepRotateLeft  StepType = "rotate_left"
+	StepRotateRight StepType = "rotate_right"
+	StepColorChange StepType = "color_change"
+	StepCompare     StepType = "compare"
+	StepVisit       StepType = "visit"
+	StepFound       StepType = "found"
+	StepNotFound    StepType = "not_found"
+	StepUpdateDist  StepType = "update_distance"
+	StepSelectNode  StepType = "select_node"  // Dijkstra picks the closest unvisited node
+	StepMarkVisited StepType = "mark_visited" // the selected node is settled; its snapshot shows Visited
+	StepRebalance   StepType = "rebalance"
+	StepSplit       StepType = "split" // a full 2-3-4 node is split and its middle key moves up
+	StepSwap        StepType = "swap"  // two heap slots or array elements exchange their values
+	StepPivot       StepType = "pivot" // quicksort picks the pivot of a range
+	StepWrite       StepType = "write" // mergesort writes a merged value into the array
+
+	StepThreadCreate StepType = "thread_create" // Morris traversal threads a predecessor to its successor
+	StepThreadRemove StepType = "thread_remove" // the thread is followed back and removed
 	StepComplete     StepType = "complete"
 )
 
+// stepTypes lists every defined StepType. New step types must be added here
+// or they will be rejected by IsValid and UnmarshalJSON.
+var stepTypes = []StepType{
+	StepInsert,
+	StepDelete,
+	StepRotateLeft,
+	StepRotateRight,
+	StepColorChange,
+	StepCompare,
+	StepVisit,
+	StepFound,
+	StepNotFound,
+	StepUpdateDist,
+	StepSelectNode,
+	StepMarkVisited,
+	StepRebalance,
+	StepSplit,
+	StepSwap,
+	StepPivot,
+	StepWrite,
+	StepThreadCreate,
+	StepThreadRemove,
+	StepComplete,
+}
+
+// AllStepTypes returns every defined StepType
+func AllStepTypes() []StepType {
+	return append([]StepType(nil), stepTypes...)
+}
+
+// IsValid reports whether s is one of the defined step types
+func (s StepType) IsValid() bool {
+	for _, known := range stepTypes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON decodes a step type, rejecting values that are not defined
+func (s *StepType) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	stepType := StepType(raw)
+	if !stepType.IsValid() {
+		return fmt.Errorf("unknown step type %q", raw)
+	}
+	*s = stepType
+	return nil
+}
+
 // TreeNodeSnapshot represents a snapshot of a tree node
 type TreeNodeSnapshot struct {
 	ID       int       `json:"id"`
@@ -39,51 +106,332 @@ type TreeNodeSnapshot struct {
 	Height   int       `json:"height,omitempty"`
 	X        float64   `json:"x,omitempty"`
 	Y        float64   `json:"y,omitempty"`
+
+	// BalanceFactor and BlackHeight are filled in by balance_info, and
+	// Violation by balance_info and check_bst
+	BalanceFactor *int `json:"balanceFactor,omitempty"`
+	BlackHeight   *int `json:"blackHeight,omitempty"`
+	Violation     bool `json:"violation,omitempty"`
+
+	// ThreadID is the node a temporary Morris thread from this node's empty
+	// right pointer leads back to. Threads are not children, so RightID
+	// stays empty.
+	ThreadID *int `json:"threadId,omitempty"`
+
+	// DoubleBlack marks the node carrying the extra black during a
+	// Red-Black delete fixup. When that node is an empty leaf, its parent
+	// is marked instead with DoubleBlackLeaf naming the side of the leaf.
+	DoubleBlack     bool   `json:"doubleBlack,omitempty"`
+	DoubleBlackLeaf string `json:"doubleBlackLeaf,omitempty"`
+
+	// Keys and ChildIDs describe multi-key nodes (2-3-4 Tree only)
+	Keys     []int `json:"keys,omitempty"`
+	ChildIDs []int `json:"childIds,omitempty"`
 }
 
 // GraphNodeSnapshot represents a snapshot of a graph node
 type GraphNodeSnapshot struct {
-	ID       string  `json:"id"`
-	Label    string  `json:"label"`
-	X        float64 `json:"x"`
-	Y        float64 `json:"y"`
-	Distance *int    `json:"distance,omitempty"`
-	Visited  bool    `json:"visited"`
-	InPath   bool    `json:"inPath"`
+	ID       string   `json:"id"`
+	Label    string   `json:"label"`
+	X        float64  `json:"x"`
+	Y        float64  `json:"y"`
+	Distance *float64 `json:"distance,omitempty"`
+	Visited  bool     `json:"visited"`
+	InPath   bool     `json:"inPath"`
+	Weight   int      `json:"weight,omitempty"`
+	// Component is the strongly connected component index assigned by scc
+	Component *int `json:"component,omitempty"`
+	// ColorClass is the side (0 or 1) a node is colored by bipartite
+	ColorClass *int `json:"colorClass,omitempty"`
+
+	DegreeCentrality      *float64 `json:"degreeCentrality,omitempty"`
+	BetweennessCentrality *float64 `json:"betweennessCentrality,omitempty"`
 }
 
 // GraphEdgeSnapshot represents a snapshot of a graph edge
 type GraphEdgeSnapshot struct {
-	From     string `json:"from"`
-	To       string `json:"to"`
-	Weight   int    `json:"weight"`
-	InPath   bool   `json:"inPath"`
-	Selected bool   `json:"selected"`
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Weight   float64 `json:"weight"`
+	InPath   bool    `json:"inPath"`
+	Selected bool    `json:"selected"`
+	// Saturated marks an edge whose capacity is used up (max flow only)
+	Saturated bool `json:"saturated,omitempty"`
+}
+
+// ColorChange records one node's color transition within a recolor step
+type ColorChange struct {
+	NodeID   int       `json:"nodeId"`
+	Value    int       `json:"value"`
+	OldColor NodeColor `json:"oldColor"`
+	NewColor NodeColor `json:"newColor"`
 }
 
 // Step represents a single step in the algorithm execution
 type Step struct {
-	Type        StepType            `json:"type"`
-	Description string              `json:"description"`
-	NodeID      *int                `json:"nodeId,omitempty"`
-	TargetID    *int                `json:"targetId,omitempty"`
-	Value       *int                `json:"value,omitempty"`
-	OldColor    NodeColor           `json:"oldColor,omitempty"`
-	NewColor    NodeColor           `json:"newColor,omitempty"`
-	TreeState   []TreeNodeSnapshot  `json:"treeState,omitempty"`
-	GraphNodes  []GraphNodeSnapshot `json:"graphNodes,omitempty"`
-	GraphEdges  []GraphEdgeSnapshot `json:"graphEdges,omitempty"`
-	Highlight   []int               `json:"highlight,omitempty"`
+	Type         StepType            `json:"type"`
+	Description  string              `json:"description"`
+	NodeID       *int                `json:"nodeId,omitempty"`
+	TargetID     *int                `json:"targetId,omitempty"`
+	Value        *int                `json:"value,omitempty"`
+	OldColor     NodeColor           `json:"oldColor,omitempty"`
+	NewColor     NodeColor           `json:"newColor,omitempty"`
+	ColorChanges []ColorChange       `json:"colorChanges,omitempty"`
+	TreeState    []TreeNodeSnapshot  `json:"treeState,omitempty"`
+	GraphNodes   []GraphNodeSnapshot `json:"graphNodes,omitempty"`
+	GraphEdges   []GraphEdgeSnapshot `json:"graphEdges,omitempty"`
+	GraphDelta   *GraphDelta         `json:"graphDelta,omitempty"`
+	ArrayState   *ArraySnapshot      `json:"arrayState,omitempty"`
+	Highlight    []int               `json:"highlight,omitempty"`
+
+	// Index is the position of the step within its operation and Timestamp
+	// the time it was recorded, in microseconds since the operation began
+	Index     int   `json:"index"`
+	Timestamp int64 `json:"timestamp"`
+	// Invariant states the property a fixup step is restoring
+	Invariant string `json:"invariant,omitempty"`
+	// DurationHint is how long playback should dwell on the step, in
+	// milliseconds. It is only set when timing hints are requested.
+	DurationHint int `json:"durationHint,omitempty"`
+}
+
+// defaultDurationHint is the playback time of step types without their own
+const defaultDurationHint = 400
+
+// durationHints gives structural changes more playback time than the
+// comparisons and visits between them
+var durationHints = map[StepType]int{
+	StepInsert:       600,
+	StepDelete:       600,
+	StepRotateLeft:   800,
+	StepRotateRight:  800,
+	StepColorChange:  500,
+	StepCompare:      300,
+	StepVisit:        300,
+	StepFound:        600,
+	StepNotFound:     600,
+	StepUpdateDist:   500,
+	StepRebalance:    600,
+	StepSplit:        800,
+	StepSwap:         500,
+	StepWrite:        300,
+	StepThreadCreate: 600,
+	StepThreadRemove: 600,
+	StepComplete:     800,
+}
+
+// annotateDurations sets the DurationHint of every step from its type
+func annotateDurations(steps []Step) {
+	for i := range steps {
+		hint, ok := durationHints[steps[i].Type]
+		if !ok {
+			hint = defaultDurationHint
+		}
+		steps[i].DurationHint = hint
+	}
+}
+
+// stamp records the step's position in the sequence and the time elapsed
+// since started, the start of its operation
+func (s *Step) stamp(index int, started time.Time) {
+	s.Index = index
+	s.Timestamp = time.Since(started).Microseconds()
+}
+
+// FilterSteps returns the steps whose type is one of types, preserving
+// their order and original indices
+func FilterSteps(steps []Step, types []StepType) []Step {
+	keep := make(map[StepType]bool, len(types))
+	for _, st := range types {
+		keep[st] = true
+	}
+	filtered := make([]Step, 0)
+	for _, step := range steps {
+		if keep[step.Type] {
+			filtered = append(filtered, step)
+		}
+	}
+	return filtered
 }
 
+// GraphState represents a complete snapshot of a graph
+type GraphState struct {
+	Nodes []GraphNodeSnapshot `json:"nodes"`
+	Edges []GraphEdgeSnapshot `json:"edges"`
+}
+
+// IssueSeverity represents how serious a validation issue is
+type IssueSeverity string
+
+const (
+	SeverityError   IssueSeverity = "error"
+	SeverityWarning IssueSeverity = "warning"
+)
+
+// ValidationIssue describes a single problem found while validating input
+type ValidationIssue struct {
+	Severity IssueSeverity `json:"severity"`
+	Code     string        `json:"code"`
+	Message  string        `json:"message"`
+	From     string        `json:"from,omitempty"`
+	To       string        `json:"to,omitempty"`
+}
+
+// GraphInfo summarizes the shape of a graph without running an algorithm
+type GraphInfo struct {
+	NodeCount int            `json:"nodeCount"`
+	EdgeCount int            `json:"edgeCount"`
+	Degrees   map[string]int `json:"degrees"`
+	// InDegree and OutDegree split the degrees of a directed graph
+	InDegree  map[string]int `json:"inDegree,omitempty"`
+	OutDegree map[string]int `json:"outDegree,omitempty"`
+	// Connected means weakly connected for a directed graph
+	Connected bool `json:"connected"`
+}
+
+// PathResult is a single path through a graph with its total cost
+type PathResult struct {
+	Nodes []string `json:"nodes"`
+	Cost  float64  `json:"cost"`
+}
+
+// ResultReason explains why an operation did not succeed
+type ResultReason string
+
+const (
+	// ReasonNotFound means the structure has content but not the value
+	ReasonNotFound ResultReason = "not_found"
+	// ReasonEmptyTree means the tree has no nodes at all
+	ReasonEmptyTree ResultReason = "empty_tree"
+	// ReasonCanceled means the operation was aborted by cancellation or timeout
+	ReasonCanceled ResultReason = "canceled"
+	// ReasonUnreachable means no path connects the requested nodes
+	ReasonUnreachable ResultReason = "unreachable"
+)
+
+// StructureKind tells clients which renderer a result's snapshots are for
+type StructureKind string
+
+const (
+	KindRBTree  StructureKind = "rbtree"
+	KindAVLTree StructureKind = "avltree"
+	KindTree234 StructureKind = "tree234"
+	KindHeap    StructureKind = "heap"
+	KindArray   StructureKind = "array"
+	KindGraph   StructureKind = "graph"
+)
+
+// ErrorCode is a machine-readable identifier of a failure. Legitimate empty
+// outcomes use ResultReason instead.
+type ErrorCode string
+
+const (
+	CodeInvalidRequest   ErrorCode = "INVALID_REQUEST"
+	CodeInvalidParam     ErrorCode = "INVALID_PARAM"
+	CodeUnknownStructure ErrorCode = "UNKNOWN_STRUCTURE"
+	CodeUnknownOperation ErrorCode = "UNKNOWN_OPERATION"
+	CodeNodeNotFound     ErrorCode = "NODE_NOT_FOUND"
+	CodeInvalidState     ErrorCode = "INVALID_STATE"
+	CodeBenchmarkRunning ErrorCode = "BENCHMARK_RUNNING"
+	CodeNotFound         ErrorCode = "NOT_FOUND"
+	CodeNotFinished      ErrorCode = "NOT_FINISHED"
+	CodeInternal         ErrorCode = "INTERNAL_ERROR"
+)
+
 // OperationResult represents the result of a data structure operation
 type OperationResult struct {
-	Success   bool   `json:"success"`
-	Message   string `json:"message,omitempty"`
-	Steps     []Step `json:"steps"`
-	FinalTree []TreeNodeSnapshot  `json:"finalTree,omitempty"`
-	FinalGraph *struct {
-		Nodes []GraphNodeSnapshot `json:"nodes"`
-		Edges []GraphEdgeSnapshot `json:"edges"`
-	} `json:"finalGraph,omitempty"`
+	Success bool `json:"success"`
+	// Kind is the structure the result belongs to
+	Kind    StructureKind `json:"kind,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Reason  ResultReason  `json:"reason,omitempty"`
+	// Code identifies the failure of an operation rejected for bad input
+	Code ErrorCode `json:"code,omitempty"`
+	// NoOp marks an unsuccessful result that is a legitimate empty outcome,
+	// such as an absent value, rather than a failure caused by bad input
+	NoOp       bool               `json:"noOp,omitempty"`
+	Steps      []Step             `json:"steps"`
+	FinalTree  []TreeNodeSnapshot `json:"finalTree,omitempty"`
+	FinalGraph *GraphState        `json:"finalGraph,omitempty"`
+	Issues     []ValidationIssue  `json:"issues,omitempty"`
+	GraphInfo  *GraphInfo         `json:"graphInfo,omitempty"`
+	// AdjacencyMatrix is the weight matrix of the graph, included on request
+	AdjacencyMatrix *AdjacencyMatrix `json:"adjacencyMatrix,omitempty"`
+	Paths           []PathResult     `json:"paths,omitempty"`
+	Traversal       []int            `json:"traversal,omitempty"`
+	// Values holds the sorted contents of a tree returned by values, or the
+	// output of heapsort, extract_min and the array sorts
+	Values []int `json:"values,omitempty"`
+	// Comparison holds the separate runs of compare_paths
+	Comparison []PathComparison `json:"comparison,omitempty"`
+	// Partition holds the two color classes of a bipartite graph
+	Partition [][]string `json:"partition,omitempty"`
+	// Document holds a serialized structure returned by export operations
+	Document json.RawMessage `json:"document,omitempty"`
+	// PathIDs lists the node IDs from the root to the target of path_to
+	PathIDs []int `json:"pathIds,omitempty"`
+	// MatchIDs lists the nodes holding the value of search_all, and
+	// MatchCount their number
+	MatchIDs   []int `json:"matchIds,omitempty"`
+	MatchCount int   `json:"matchCount,omitempty"`
+	// Done is set by next once the in-order walk has no value left
+	Done bool `json:"done,omitempty"`
+	// Depth is the depth of the value found by depth, with the root at 0
+	Depth *int `json:"depth,omitempty"`
+	// BlackHeight is the Red-Black Tree's black-height and BlackCount the
+	// number of black nodes from the root to the queried value
+	BlackHeight *int `json:"blackHeight,omitempty"`
+	BlackCount  *int `json:"blackCount,omitempty"`
+	// OrderViolation is the first out-of-order pair found by check_bst
+	OrderViolation *OrderViolation `json:"orderViolation,omitempty"`
+	// NestedTree is the recursive form of the tree returned by export_nested
+	NestedTree *NestedNode `json:"nestedTree,omitempty"`
+	// Node is the node returned by get_node
+	Node *TreeNodeSnapshot `json:"node,omitempty"`
+	// TotalSteps and NextOffset are set when Steps holds one page of the step
+	// log. NextOffset is absent on the last page.
+	TotalSteps int  `json:"totalSteps,omitempty"`
+	NextOffset *int `json:"nextOffset,omitempty"`
+	// StepToken identifies the cached step log for fetching single steps
+	StepToken string `json:"stepToken,omitempty"`
+
+	// Distances and Predecessors hold single-source shortest path results.
+	// Unreachable nodes have distance -1 and no predecessor.
+	Distances    map[string]float64 `json:"distances,omitempty"`
+	Predecessors map[string]string  `json:"predecessors,omitempty"`
+
+	// RotationCount is the number of rotations the operation triggered
+	RotationCount int `json:"rotationCount"`
+	// ColorChangeCount is the number of recoloring steps (Red-Black Tree only)
+	ColorChangeCount int `json:"colorChangeCount,omitempty"`
+	// ComparisonCount is the number of compare steps the operation recorded
+	ComparisonCount int `json:"comparisonCount"`
+	// StepCounts tallies the steps of the operation by type. It covers the
+	// full log even when the returned steps are filtered or paged.
+	StepCounts map[StepType]int `json:"stepCounts,omitempty"`
+}
+
+// CountSteps tallies steps by type
+func CountSteps(steps []Step) map[StepType]int {
+	counts := make(map[StepType]int)
+	for _, step := range steps {
+		counts[step.Type]++
+	}
+	return counts
+}
+
+// AnnotateDurations sets the playback duration hints of the result's steps,
+// including the separate runs of compare_paths
+func (r *OperationResult) AnnotateDurations() {
+	annotateDurations(r.Steps)
+	for i := range r.Comparison {
+		annotateDurations(r.Comparison[i].Steps)
+	}
+}
+
+// Finalize fills in the summary fields derived from the step log. It runs
+// once an operation has finished recording steps.
+func (r *OperationResult) Finalize() {
+	r.StepCounts = CountSteps(r.Steps)
+	r.ComparisonCount = r.StepCounts[StepCompare]
 }
@@ -0,0 +1,144 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStepTypeIsValid(t *testing.T) {
+	for _, stepType := range AllStepTypes() {
+		if !stepType.IsValid() {
+			t.Errorf("%q reported invalid", stepType)
+		}
+	}
+	for _, stepType := range []StepType{"", "teleport", "Insert"} {
+		if stepType.IsValid() {
+			t.Errorf("%q reported valid", stepType)
+		}
+	}
+}
+
+func TestStepTypeUnmarshal(t *testing.T) {
+	var types []StepType
+	if err := json.Unmarshal([]byte(`["insert", "complete"]`), &types); err != nil {
+		t.Fatalf("known types rejected: %v", err)
+	}
+	if len(types) != 2 || types[0] != StepInsert || types[1] != StepComplete {
+		t.Errorf("decoded %v", types)
+	}
+	if err := json.Unmarshal([]byte(`["insert", "teleport"]`), &types); err == nil {
+		t.Error("unknown step type accepted")
+	}
+}
+
+// representativeResults runs a multi-step operation on every structure
+func representativeResults() map[string]OperationResult {
+	rb := NewRedBlackTree()
+	avl := NewAVLTree()
+	t234 := NewTree234()
+	heap := NewBinaryHeap()
+	for _, v := range []int{5, 3, 8, 1, 4} {
+		rb.Insert(v)
+		avl.Insert(v)
+		t234.Insert(v)
+		heap.Insert(v)
+	}
+	return map[string]OperationResult{
+		"rbtree":  rb.Delete(3),
+		"avltree": avl.Insert(2),
+		"tree234": t234.Insert(2),
+		"heap":    heap.Insert(2),
+		"array":   NewArraySorter().QuickSort([]int{4, 1, 3, 2}),
+		"graph":   CreateSampleGraph().Dijkstra("A", "F"),
+	}
+}
+
+func TestStepsAreIndexedInOrder(t *testing.T) {
+	for name, result := range representativeResults() {
+		if len(result.Steps) < 2 {
+			t.Fatalf("%s emitted %d steps", name, len(result.Steps))
+		}
+		for i, step := range result.Steps {
+			if step.Index != i {
+				t.Fatalf("%s step %d has index %d", name, i, step.Index)
+			}
+			if i > 0 && step.Timestamp < result.Steps[i-1].Timestamp {
+				t.Fatalf("%s step %d is timestamped before step %d", name, i, i-1)
+			}
+		}
+	}
+}
+
+func TestStepTimestampsCountFromTheOperationStart(t *testing.T) {
+	results := representativeResults()
+	// Both join the logs of nested Dijkstra runs, each of which restarts
+	// the clock
+	results["waypoints"] = CreateSampleGraph().PathThroughWaypoints([]string{"A", "D", "F"})
+	results["reroute"] = CreateSampleGraph().Reroute("A", "F", "C", "B")
+	for name, result := range results {
+		if first := result.Steps[0].Timestamp; first < 0 || first > time.Second.Microseconds() {
+			t.Errorf("%s: first step at %dµs, want an offset from the start of the operation", name, first)
+		}
+		for i := 1; i < len(result.Steps); i++ {
+			if result.Steps[i].Timestamp < result.Steps[i-1].Timestamp {
+				t.Fatalf("%s step %d is timestamped before step %d", name, i, i-1)
+			}
+		}
+	}
+}
+
+func TestEmittedStepTypesAreValid(t *testing.T) {
+	for name, result := range representativeResults() {
+		for _, step := range result.Steps {
+			if !step.Type.IsValid() {
+				t.Errorf("%s emitted invalid step type %q", name, step.Type)
+			}
+		}
+	}
+}
+
+func TestTimingModeAnnotatesEveryStep(t *testing.T) {
+	for name, result := range representativeResults() {
+		for _, step := range result.Steps {
+			if step.DurationHint != 0 {
+				t.Fatalf("%s step %d has a duration hint without timing mode", name, step.Index)
+			}
+		}
+		result.AnnotateDurations()
+		for _, step := range result.Steps {
+			if step.DurationHint <= 0 {
+				t.Errorf("%s %s step %d has duration hint %d", name, step.Type, step.Index, step.DurationHint)
+			}
+		}
+	}
+}
+
+func TestStateLeavesTheStepLogAlone(t *testing.T) {
+	rb, avl, t234, heap, g := NewRedBlackTree(), NewAVLTree(), NewTree234(), NewBinaryHeap(), CreateSampleGraph()
+	rb.Insert(1)
+	avl.Insert(1)
+	t234.Insert(1)
+	heap.Insert(1)
+	g.Dijkstra("A", "F")
+
+	for name, s := range map[string]struct {
+		steps *[]Step
+		state func() OperationResult
+	}{
+		"rbtree":  {&rb.steps, rb.State},
+		"avltree": {&avl.steps, avl.State},
+		"tree234": {&t234.steps, t234.State},
+		"heap":    {&heap.steps, heap.State},
+		"graph":   {&g.steps, g.State},
+	} {
+		before := len(*s.steps)
+		result := s.state()
+		if len(result.Steps) != 0 {
+			t.Errorf("%s: State returned %d steps", name, len(result.Steps))
+		}
+		if len(*s.steps) != before || before == 0 {
+			t.Errorf("%s: step log went from %d to %d steps", name, before, len(*s.steps))
+		}
+	}
+}
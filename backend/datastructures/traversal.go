@@ -0,0 +1,124 @@
+package datastructures
+
+// LevelOrder visits the AVL Tree breadth-first, one level at a time. Each
+// visit step highlights every node of the current level.
+func (t *AVLTree) LevelOrder() OperationResult {
+	t.clearSteps()
+	order := make([]int, 0)
+
+	level := make([]*AVLNode, 0)
+	if t.Root != nil {
+		level = append(level, t.Root)
+	}
+	for depth := 0; len(level) > 0; depth++ {
+		ids := make([]int, len(level))
+		for i, node := range level {
+			ids[i] = node.ID
+		}
+
+		if contextDone(t.ctx) {
+			return t.canceledResult()
+		}
+		next := make([]*AVLNode, 0)
+		for _, node := range level {
+			order = append(order, node.Value)
+			t.addStep(StepVisit, t.msg("tree.levelorder.visit", node.Value, depth), &node.ID, ids)
+			if node.Left != nil {
+				next = append(next, node.Left)
+			}
+			if node.Right != nil {
+				next = append(next, node.Right)
+			}
+		}
+		level = next
+	}
+
+	t.addStep(StepComplete, t.msg("tree.levelorder.done", order), nil)
+	result := t.newResult(true, t.msg("tree.levelorder.success", len(order)))
+	result.Traversal = order
+	return result
+}
+
+// LevelOrder visits the Red-Black Tree breadth-first, one level at a time.
+// Each visit step highlights every node of the current level.
+func (t *RedBlackTree) LevelOrder() OperationResult {
+	t.clearSteps()
+	order := make([]int, 0)
+
+	level := make([]*RBNode, 0)
+	if t.Root != t.NIL {
+		level = append(level, t.Root)
+	}
+	for depth := 0; len(level) > 0; depth++ {
+		ids := make([]int, len(level))
+		for i, node := range level {
+			ids[i] = node.ID
+		}
+
+		next := make([]*RBNode, 0)
+		for _, node := range level {
+			order = append(order, node.Value)
+			t.addStep(StepVisit, t.msg("tree.levelorder.visit", node.Value, depth), &node.ID, ids)
+			if node.Left != t.NIL {
+				next = append(next, node.Left)
+			}
+			if node.Right != t.NIL {
+				next = append(next, node.Right)
+			}
+		}
+		level = next
+	}
+
+	t.addStep(StepComplete, t.msg("tree.levelorder.done", order), nil)
+	result := t.newResult(true, t.msg("tree.levelorder.success", len(order)))
+	result.Traversal = order
+	return result
+}
+
+// ForEach calls fn with every value of the AVL Tree in ascending order. No
+// steps are recorded.
+func (t *AVLTree) ForEach(fn func(int)) {
+	stack := make([]*AVLNode, 0)
+	current := t.Root
+	for current != nil || len(stack) > 0 {
+		for current != nil {
+			stack = append(stack, current)
+			current = current.Left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(current.Value)
+		current = current.Right
+	}
+}
+
+// Values returns the values of the AVL Tree in ascending order
+func (t *AVLTree) Values() []int {
+	values := make([]int, 0)
+	t.ForEach(func(v int) { values = append(values, v) })
+	return values
+}
+
+// ForEach calls fn with every value of the Red-Black Tree in ascending
+// order. No steps are recorded.
+func (t *RedBlackTree) ForEach(fn func(int)) {
+	stack := make([]*RBNode, 0)
+	current := t.Root
+	for current != t.NIL || len(stack) > 0 {
+		for current != t.NIL {
+			stack = append(stack, current)
+			current = current.Left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(current.Value)
+		current = current.Right
+	}
+}
+
+// Values returns the values of the Red-Black Tree in ascending order
+func (t *RedBlackTree) Values() []int {
+	values := make([]int, 0)
+	t.ForEach(func(v int) { values = append(values, v) })
+	return values
+}
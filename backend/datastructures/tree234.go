@@ -0,0 +1,308 @@
+package datastructures
+
+import "time"
+
+// Node234 represents a node of a 2-3-4 tree holding one to three sorted keys.
+// Internal nodes have exactly len(Keys)+1 children.
+type Node234 struct {
+	ID       int
+	Keys     []int
+	Children []*Node234
+}
+
+func (n *Node234) isLeaf() bool {
+	return len(n.Children) == 0
+}
+
+// childIndex returns the child to descend into for value. Equal keys go
+// right, matching the Red-Black Tree's handling of duplicates.
+func (n *Node234) childIndex(value int) int {
+	i := 0
+	for i < len(n.Keys) && value >= n.Keys[i] {
+		i++
+	}
+	return i
+}
+
+// Tree234 represents a 2-3-4 tree with step tracking. It is the B-tree of
+// order 4 that Red-Black Trees are isometric to.
+type Tree234 struct {
+	Root   *Node234
+	nextID int
+	steps  []Step
+
+	// started is when the current operation began, the origin of its step
+	// timestamps
+	started time.Time
+
+	// layout is the canvas size snapshots are laid out for
+	layout TreeLayout
+
+	// includeSnapshots controls whether every step embeds a full TreeState
+	includeSnapshots bool
+
+	// operand is the value the current operation works on
+	operand *int
+
+	// locale selects the language of step descriptions and messages
+	locale Locale
+}
+
+// NewTree234 creates a new 2-3-4 tree
+func NewTree234() *Tree234 {
+	return &Tree234{
+		steps:            make([]Step, 0),
+		includeSnapshots: true,
+		layout:           newTreeLayout(0, 0),
+	}
+}
+
+// SetIncludeSnapshots toggles per-step tree snapshots. The final tree
+// snapshot of an operation is always produced.
+func (t *Tree234) SetIncludeSnapshots(include bool) {
+	t.includeSnapshots = include
+}
+
+// SetLayout sets the canvas width and the distance between levels of the
+// snapshots. Non-positive values restore the defaults.
+func (t *Tree234) SetLayout(width, levelHeight float64) {
+	t.layout = newTreeLayout(width, levelHeight)
+}
+
+// SetLocale selects the language of step descriptions and messages
+func (t *Tree234) SetLocale(locale Locale) {
+	t.locale = locale
+}
+
+func (t *Tree234) msg(key string, args ...interface{}) string {
+	return Localize(t.locale, key, args...)
+}
+
+func (t *Tree234) clearSteps() {
+	t.steps = make([]Step, 0)
+	t.started = time.Now()
+	t.operand = nil
+}
+
+// newResult builds an OperationResult from the steps recorded so far
+func (t *Tree234) newResult(success bool, message string) OperationResult {
+	return OperationResult{
+		Success:   success,
+		Message:   message,
+		Steps:     t.steps,
+		FinalTree: t.getTreeSnapshot(),
+	}
+}
+
+func (t *Tree234) addStep(stepType StepType, desc string, nodeID *int, highlights ...int) {
+	step := Step{
+		Type:        stepType,
+		Description: desc,
+		NodeID:      nodeID,
+		Value:       t.operand,
+		Highlight:   highlights,
+	}
+	if t.includeSnapshots {
+		step.TreeState = t.getTreeSnapshot()
+	}
+	step.stamp(len(t.steps), t.started)
+	t.steps = append(t.steps, step)
+}
+
+func (t *Tree234) getTreeSnapshot() []TreeNodeSnapshot {
+	var nodes []TreeNodeSnapshot
+	t.snapshot(t.Root, &nodes, 0, 0, t.layout.Width)
+	return nodes
+}
+
+// snapshot lays out node in [xMin, xMax] and splits the range evenly among
+// its children
+func (t *Tree234) snapshot(node *Node234, nodes *[]TreeNodeSnapshot, depth int, xMin, xMax float64) {
+	if node == nil {
+		return
+	}
+
+	s := TreeNodeSnapshot{
+		ID:    node.ID,
+		Value: node.Keys[0],
+		Keys:  append([]int(nil), node.Keys...),
+		X:     (xMin + xMax) / 2,
+		Y:     t.layout.levelY(depth),
+	}
+	for _, child := range node.Children {
+		s.ChildIDs = append(s.ChildIDs, child.ID)
+	}
+	*nodes = append(*nodes, s)
+
+	if node.isLeaf() {
+		return
+	}
+	width := (xMax - xMin) / float64(len(node.Children))
+	for i, child := range node.Children {
+		t.snapshot(child, nodes, depth+1, xMin+float64(i)*width, xMin+float64(i+1)*width)
+	}
+}
+
+func (t *Tree234) newNode(keys ...int) *Node234 {
+	node := &Node234{ID: t.nextID, Keys: keys}
+	t.nextID++
+	return node
+}
+
+// splitChild splits the full child at index i of parent, moving its middle
+// key up into parent. The left half keeps the child's ID.
+func (t *Tree234) splitChild(parent *Node234, i int) {
+	child := parent.Children[i]
+	full := append([]int(nil), child.Keys...)
+	middle := child.Keys[1]
+
+	right := t.newNode(child.Keys[2])
+	if !child.isLeaf() {
+		right.Children = append([]*Node234(nil), child.Children[2:]...)
+		child.Children = append([]*Node234(nil), child.Children[:2]...)
+	}
+	child.Keys = []int{child.Keys[0]}
+
+	parent.Keys = append(parent.Keys, 0)
+	copy(parent.Keys[i+1:], parent.Keys[i:])
+	parent.Keys[i] = middle
+	parent.Children = append(parent.Children, nil)
+	copy(parent.Children[i+2:], parent.Children[i+1:])
+	parent.Children[i+1] = right
+
+	t.addStep(StepSplit, t.msg("t234.split", full, middle), &parent.ID, parent.ID, child.ID, right.ID)
+}
+
+// Insert inserts a value using top-down insertion: every 4-node met on the
+// way down is split first, so the leaf reached always has room.
+func (t *Tree234) Insert(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+	t.addStep(StepInsert, t.msg("tree.insert.start", value), nil)
+
+	if t.Root == nil {
+		t.Root = t.newNode(value)
+		t.addStep(StepInsert, t.msg("t234.insert.root", value), &t.Root.ID, t.Root.ID)
+		t.addStep(StepComplete, t.msg("tree.insert.done"), nil)
+		return t.newResult(true, "")
+	}
+
+	if len(t.Root.Keys) == 3 {
+		oldRoot := t.Root
+		t.addStep(StepSplit, t.msg("t234.split_root"), &oldRoot.ID, oldRoot.ID)
+		t.Root = t.newNode()
+		t.Root.Children = []*Node234{oldRoot}
+		t.splitChild(t.Root, 0)
+	}
+
+	node := t.Root
+	for {
+		t.addStep(StepCompare, t.msg("t234.compare", value, node.Keys), &node.ID, node.ID)
+		if node.isLeaf() {
+			i := node.childIndex(value)
+			node.Keys = append(node.Keys, 0)
+			copy(node.Keys[i+1:], node.Keys[i:])
+			node.Keys[i] = value
+			t.addStep(StepInsert, t.msg("t234.insert.leaf", value, len(node.Keys)+1), &node.ID, node.ID)
+			break
+		}
+
+		i := node.childIndex(value)
+		if len(node.Children[i].Keys) == 3 {
+			t.splitChild(node, i)
+			if value >= node.Keys[i] {
+				i++
+			}
+		}
+		node = node.Children[i]
+	}
+
+	t.addStep(StepComplete, t.msg("tree.insert.done"), nil)
+	return t.newResult(true, "")
+}
+
+// Search searches for a value in the 2-3-4 tree
+func (t *Tree234) Search(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	node := t.Root
+	for node != nil {
+		t.addStep(StepCompare, t.msg("t234.compare", value, node.Keys), &node.ID, node.ID)
+		for _, key := range node.Keys {
+			if key == value {
+				t.addStep(StepFound, t.msg("tree.search.found_node", value), &node.ID, node.ID)
+				return t.newResult(true, t.msg("tree.search.found", value))
+			}
+		}
+		if node.isLeaf() {
+			break
+		}
+		node = node.Children[node.childIndex(value)]
+	}
+
+	t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
+}
+
+// State returns a snapshot of the tree without modifying it
+func (t *Tree234) State() OperationResult {
+	return OperationResult{
+		Success:   true,
+		Message:   t.msg("tree.state", t.Size()),
+		Steps:     []Step{},
+		FinalTree: t.getTreeSnapshot(),
+	}
+}
+
+// Height returns the number of levels of the 2-3-4 tree (0 when empty). All
+// leaves are at the same depth.
+func (t *Tree234) Height() int {
+	levels := 0
+	for node := t.Root; node != nil; levels++ {
+		if node.isLeaf() {
+			node = nil
+		} else {
+			node = node.Children[0]
+		}
+	}
+	return levels
+}
+
+// Size returns the number of keys in the 2-3-4 tree
+func (t *Tree234) Size() int {
+	return len(t.Values())
+}
+
+// Values returns the keys of the 2-3-4 tree in ascending order
+func (t *Tree234) Values() []int {
+	values := make([]int, 0)
+	var walk func(node *Node234)
+	walk = func(node *Node234) {
+		if node == nil {
+			return
+		}
+		for i, key := range node.Keys {
+			if !node.isLeaf() {
+				walk(node.Children[i])
+			}
+			values = append(values, key)
+		}
+		if !node.isLeaf() {
+			walk(node.Children[len(node.Keys)])
+		}
+	}
+	walk(t.Root)
+	return values
+}
@@ -0,0 +1,48 @@
+package datastructures
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// render234 writes node as (keys children...) for comparison with a tree
+// worked out by hand
+func render234(node *Node234) string {
+	parts := make([]string, 0, len(node.Keys)+len(node.Children))
+	for _, key := range node.Keys {
+		parts = append(parts, fmt.Sprint(key))
+	}
+	for _, child := range node.Children {
+		parts = append(parts, render234(child))
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestTree234InsertArrangement(t *testing.T) {
+	tree := NewTree234()
+	for v := 1; v <= 10; v++ {
+		tree.Insert(v)
+	}
+
+	// Splits happen top-down at 4, 6, 8 and 10; the last one splits the
+	// root [2 4 6] before splitting [7 8 9] below it
+	if got, want := render234(tree.Root), "(4 (2 (1) (3)) (6 8 (5) (7) (9 10)))"; got != want {
+		t.Errorf("tree %s, want %s", got, want)
+	}
+	if tree.Height() != 3 || tree.Size() != 10 {
+		t.Errorf("height %d size %d, want 3 and 10", tree.Height(), tree.Size())
+	}
+}
+
+func TestTree234ValuesAreSorted(t *testing.T) {
+	tree := NewTree234()
+	inserted := []int{50, 20, 80, 10, 30, 60, 90, 40, 70, 25, 35, 20}
+	for _, v := range inserted {
+		tree.Insert(v)
+	}
+	if values, want := tree.Values(), slices.Sorted(slices.Values(inserted)); !slices.Equal(values, want) {
+		t.Errorf("values %v, want %v", values, want)
+	}
+}
@@ -0,0 +1,428 @@
+package datastructures
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// searchTree is the behaviour shared by the three search trees
+type searchTree interface {
+	Insert(value int) OperationResult
+	Search(value int) OperationResult
+}
+
+// newTrees returns an empty instance of every search tree
+func newTrees() map[string]searchTree {
+	return map[string]searchTree{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+		"tree234": NewTree234(),
+	}
+}
+
+func TestSearchReasons(t *testing.T) {
+	for name, tree := range newTrees() {
+		t.Run(name, func(t *testing.T) {
+			if result := tree.Search(7); result.Success || result.Reason != ReasonEmptyTree || !result.NoOp {
+				t.Errorf("empty tree: success %v reason %q noOp %v, want %q", result.Success, result.Reason, result.NoOp, ReasonEmptyTree)
+			}
+			tree.Insert(5)
+			if result := tree.Search(7); result.Success || result.Reason != ReasonNotFound || !result.NoOp {
+				t.Errorf("missing value: success %v reason %q noOp %v, want %q", result.Success, result.Reason, result.NoOp, ReasonNotFound)
+			}
+			if result := tree.Search(5); !result.Success || result.Reason != "" || result.NoOp {
+				t.Errorf("present value: success %v reason %q noOp %v", result.Success, result.Reason, result.NoOp)
+			}
+		})
+	}
+}
+
+func TestStepValueIsTheOperand(t *testing.T) {
+	trees := map[string]interface {
+		searchTree
+		Delete(value int) OperationResult
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			for _, v := range []int{20, 10, 30, 5} {
+				tree.Insert(v)
+			}
+			for _, result := range []OperationResult{tree.Insert(15), tree.Search(15), tree.Delete(15)} {
+				for _, step := range result.Steps {
+					if step.Value == nil || *step.Value != 15 {
+						t.Fatalf("%s step %d has value %v, want 15", step.Type, step.Index, step.Value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStateIsReadOnly(t *testing.T) {
+	rb, avl, t234, heap := NewRedBlackTree(), NewAVLTree(), NewTree234(), NewBinaryHeap()
+	for _, v := range []int{5, 3, 8, 1} {
+		rb.Insert(v)
+		avl.Insert(v)
+		t234.Insert(v)
+		heap.Insert(v)
+	}
+	structures := []struct {
+		name   string
+		state  func() OperationResult
+		nextID func() int // nil for structures without node IDs
+	}{
+		{"rbtree", rb.State, func() int { return rb.nextID }},
+		{"avltree", avl.State, func() int { return avl.nextID }},
+		{"tree234", t234.State, func() int { return t234.nextID }},
+		{"heap", heap.State, nil},
+		{"graph", CreateSampleGraph().State, nil},
+	}
+	for _, s := range structures {
+		t.Run(s.name, func(t *testing.T) {
+			nextID := -1
+			if s.nextID != nil {
+				nextID = s.nextID()
+			}
+			first, second := s.state(), s.state()
+			if len(first.Steps) != 0 {
+				t.Errorf("state emitted %d steps", len(first.Steps))
+			}
+			a, _ := json.Marshal(first)
+			b, _ := json.Marshal(second)
+			if !bytes.Equal(a, b) {
+				t.Errorf("consecutive states differ:\n%s\n%s", a, b)
+			}
+			if s.nextID != nil && s.nextID() != nextID {
+				t.Errorf("nextID moved from %d to %d", nextID, s.nextID())
+			}
+		})
+	}
+}
+
+func TestLevelOrder(t *testing.T) {
+	avl, rb := NewAVLTree(), NewRedBlackTree()
+	for v := 1; v <= 7; v++ {
+		avl.Insert(v)
+	}
+	for _, v := range []int{10, 5, 15, 1, 7, 20} {
+		rb.Insert(v)
+	}
+	tests := []struct {
+		name   string
+		result OperationResult
+		want   []int
+	}{
+		// Sorted inserts rotate the AVL tree into a perfect tree rooted at 4
+		{"avltree", avl.LevelOrder(), []int{4, 2, 6, 1, 3, 5, 7}},
+		{"rbtree", rb.LevelOrder(), []int{10, 5, 15, 1, 7, 20}},
+		{"empty", NewAVLTree().LevelOrder(), []int{}},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.result.Traversal, tt.want) {
+			t.Errorf("%s: traversal %v, want %v", tt.name, tt.result.Traversal, tt.want)
+		}
+		visited := make([]int, 0)
+		for _, step := range tt.result.Steps {
+			if step.Type == StepVisit {
+				visited = append(visited, valueOf(t, step.TreeState, *step.NodeID))
+			}
+		}
+		if !slices.Equal(visited, tt.want) {
+			t.Errorf("%s: visit steps %v, want %v", tt.name, visited, tt.want)
+		}
+	}
+}
+
+// valueOf returns the value of node id in a tree snapshot
+func valueOf(t *testing.T, snapshot []TreeNodeSnapshot, id int) int {
+	t.Helper()
+	for _, node := range snapshot {
+		if node.ID == id {
+			return node.Value
+		}
+	}
+	t.Fatalf("node %d not in snapshot", id)
+	return 0
+}
+
+func TestNoOpResults(t *testing.T) {
+	rb, avl := NewRedBlackTree(), NewAVLTree()
+	rb.Insert(5)
+	avl.Insert(5)
+	unreachable := CreateSampleGraph()
+	unreachable.AddNode("G", 700, 150)
+	duplicate := CreateSampleGraph()
+	duplicate.AddNode("1", 700, 150)
+
+	tests := []struct {
+		name   string
+		result OperationResult
+		noOp   bool
+	}{
+		{"rbtree delete missing", rb.Delete(7), true},
+		{"avltree delete missing", avl.Delete(7), true},
+		{"dijkstra unreachable", unreachable.Dijkstra("A", "G"), true},
+		{"graph insert duplicate", duplicate.Insert(1), false},
+		{"longest path undirected", duplicate.LongestPath(), false},
+	}
+	for _, tt := range tests {
+		if tt.result.Success {
+			t.Errorf("%s succeeded", tt.name)
+		}
+		if tt.result.NoOp != tt.noOp {
+			t.Errorf("%s: noOp %v, want %v", tt.name, tt.result.NoOp, tt.noOp)
+		}
+		// A failure caused by bad input carries a code, an empty outcome a reason
+		if tt.noOp && (tt.result.Reason == "" || tt.result.Code != "") {
+			t.Errorf("%s: reason %q code %q for an empty outcome", tt.name, tt.result.Reason, tt.result.Code)
+		}
+		if !tt.noOp && tt.result.Code == "" {
+			t.Errorf("%s: failure without a code", tt.name)
+		}
+	}
+}
+
+func TestValuesAreSortedInsertedValues(t *testing.T) {
+	inserted := rand.New(rand.NewSource(7)).Perm(200)
+	trees := map[string]interface {
+		Insert(value int) OperationResult
+		Values() []int
+		ForEach(fn func(int))
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	want := slices.Sorted(slices.Values(inserted))
+	for name, tree := range trees {
+		for _, v := range inserted {
+			tree.Insert(v)
+		}
+		if values := tree.Values(); !slices.Equal(values, want) {
+			t.Errorf("%s: values %v, want %v", name, values, want)
+		}
+		visited := make([]int, 0, len(inserted))
+		tree.ForEach(func(v int) { visited = append(visited, v) })
+		if !slices.Equal(visited, want) {
+			t.Errorf("%s: ForEach visited %v, want %v", name, visited, want)
+		}
+	}
+	if values := NewAVLTree().Values(); values == nil || len(values) != 0 {
+		t.Errorf("empty tree values %v, want an empty slice", values)
+	}
+}
+
+func TestContains(t *testing.T) {
+	trees := map[string]interface {
+		Insert(value int) OperationResult
+		Contains(value int) bool
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	for name, tree := range trees {
+		if tree.Contains(5) {
+			t.Errorf("%s: empty tree contains 5", name)
+		}
+		for _, v := range []int{5, 3, 8} {
+			tree.Insert(v)
+		}
+		for _, v := range []int{5, 3, 8} {
+			if !tree.Contains(v) {
+				t.Errorf("%s: missing %d", name, v)
+			}
+		}
+		for _, v := range []int{0, 4, 9} {
+			if tree.Contains(v) {
+				t.Errorf("%s: contains absent %d", name, v)
+			}
+		}
+	}
+
+	// Contains records no steps, leaving the last operation's log intact
+	rb := NewRedBlackTree()
+	steps := len(rb.Insert(1).Steps)
+	rb.Contains(1)
+	if len(rb.steps) != steps {
+		t.Errorf("contains changed the step log from %d to %d steps", steps, len(rb.steps))
+	}
+
+	g := CreateSampleGraph()
+	if !g.HasNode("A") || g.HasNode("Z") {
+		t.Errorf("HasNode(A) = %v, HasNode(Z) = %v", g.HasNode("A"), g.HasNode("Z"))
+	}
+}
+
+// snapshotIDs maps every value of a tree snapshot to its node ID
+func snapshotIDs(snapshot []TreeNodeSnapshot) map[int]int {
+	ids := make(map[int]int, len(snapshot))
+	for _, node := range snapshot {
+		ids[node.Value] = node.ID
+	}
+	return ids
+}
+
+func TestNodeIDsAreStable(t *testing.T) {
+	trees := map[string]interface {
+		searchTree
+		Delete(value int) OperationResult
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			var result OperationResult
+			for v := 1; v <= 15; v++ {
+				result = tree.Insert(v)
+			}
+			before := snapshotIDs(result.FinalTree)
+
+			// 8 has two children in both trees, so its delete moves the
+			// successor node into its place
+			deleted := snapshotIDs(tree.Delete(8).FinalTree)
+			for value, id := range deleted {
+				if before[value] != id {
+					t.Errorf("delete changed the ID of %d from %d to %d", value, before[value], id)
+				}
+			}
+			for _, v := range []int{1, 2, 3} {
+				tree.Delete(v)
+			}
+
+			after := snapshotIDs(tree.Insert(8).FinalTree)
+			if after[8] != before[8] {
+				t.Errorf("re-inserted 8 got ID %d, want %d", after[8], before[8])
+			}
+			for value, id := range after {
+				if before[value] != id {
+					t.Errorf("ID of %d changed from %d to %d", value, before[value], id)
+				}
+			}
+		})
+	}
+}
+
+// fixupBrackets fails t unless every rotation of steps lies between a
+// fixup bracket pair, the node-less rebalance steps, and returns the number
+// of pairs
+func fixupBrackets(t *testing.T, steps []Step) int {
+	t.Helper()
+	done := Localize(LocaleZh, "tree.fixup.done")
+	open, pairs := false, 0
+	for _, step := range steps {
+		switch {
+		case step.Type == StepRebalance && step.NodeID == nil && step.Description == done:
+			if !open {
+				t.Fatalf("step %d closes a fixup that was never opened", step.Index)
+			}
+			open = false
+			pairs++
+		case step.Type == StepRebalance && step.NodeID == nil:
+			if open {
+				t.Fatalf("step %d opens a fixup inside another", step.Index)
+			}
+			open = true
+		case step.Type == StepRotateLeft || step.Type == StepRotateRight:
+			if !open {
+				t.Fatalf("step %d rotates outside a fixup", step.Index)
+			}
+		}
+	}
+	if open {
+		t.Fatal("fixup left open")
+	}
+	return pairs
+}
+
+func TestRebalancingIsBracketed(t *testing.T) {
+	trees := map[string]interface {
+		Insert(value int) OperationResult
+		Delete(value int) OperationResult
+	}{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	for name, tree := range trees {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(9))
+			values := rng.Perm(100)
+			pairs := 0
+			for _, v := range values {
+				pairs += fixupBrackets(t, tree.Insert(v).Steps)
+			}
+			for _, v := range values[:50] {
+				pairs += fixupBrackets(t, tree.Delete(v).Steps)
+			}
+			if pairs == 0 {
+				t.Error("no fixup was bracketed")
+			}
+		})
+	}
+}
+
+func TestSearchAllFindsEveryCopy(t *testing.T) {
+	t.Run("rbtree", func(t *testing.T) {
+		tree := NewRedBlackTree()
+		// The duplicates are interleaved with inserts that rotate them apart
+		for _, v := range []int{10, 5, 10, 20, 10, 1, 2, 3, 10, 30, 40} {
+			tree.Insert(v)
+		}
+		var want []int
+		for _, node := range tree.getTreeSnapshot() {
+			if node.Value == 10 {
+				want = append(want, node.ID)
+			}
+		}
+		result := tree.SearchAll(10)
+		if !result.Success || result.MatchCount != 4 {
+			t.Fatalf("success %v with %d matches, want 4", result.Success, result.MatchCount)
+		}
+		if got := slices.Sorted(slices.Values(result.MatchIDs)); !slices.Equal(got, slices.Sorted(slices.Values(want))) {
+			t.Errorf("matches %v, want the nodes %v", result.MatchIDs, want)
+		}
+		if result := tree.SearchAll(7); result.Success || result.Reason != ReasonNotFound {
+			t.Errorf("missing value: success %v reason %q", result.Success, result.Reason)
+		}
+	})
+
+	t.Run("avltree", func(t *testing.T) {
+		tree := NewAVLTree()
+		for _, v := range []int{10, 5, 10, 20, 10} {
+			tree.Insert(v)
+		}
+		result := tree.SearchAll(10)
+		if !result.Success || result.MatchCount != 1 || len(result.MatchIDs) != 1 {
+			t.Errorf("success %v with %d matches %v, want the single copy", result.Success, result.MatchCount, result.MatchIDs)
+		}
+	})
+}
+
+func TestInsertComparisonsGrowLogarithmically(t *testing.T) {
+	rb, avl := NewRedBlackTree(), NewAVLTree()
+	rb.SetIncludeSnapshots(false)
+	avl.SetIncludeSnapshots(false)
+	for name, tree := range map[string]searchTree{"rbtree": rb, "avltree": avl} {
+		t.Run(name, func(t *testing.T) {
+			for n := 1; n <= 1024; n++ {
+				result := tree.Insert(n)
+				result.Finalize()
+				// The descent passes the n-1 nodes already in the tree, whose
+				// height is at most 2·log2(n) in a Red-Black Tree and less in
+				// an AVL Tree
+				if bound := 2 * math.Log2(float64(n)); float64(result.ComparisonCount) > bound {
+					t.Fatalf("insert %d made %d comparisons, bound %.1f", n, result.ComparisonCount, bound)
+				}
+				if n == 1024 && result.ComparisonCount < 9 {
+					t.Errorf("insert 1024 made only %d comparisons", result.ComparisonCount)
+				}
+			}
+		})
+	}
+}
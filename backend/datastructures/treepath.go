@@ -0,0 +1,288 @@
+package datastructures
+
+import "slices"
+
+// PathTo searches the AVL Tree for value and highlights every node on the
+// path from the root to it. When the value is missing the traversed search
+// path is highlighted instead.
+func (t *AVLTree) PathTo(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	path := make([]int, 0)
+	current := t.Root
+	for current != nil {
+		path = append(path, current.ID)
+		t.addStep(StepCompare, t.msg("tree.compare", value, current.Value), &current.ID, []int{current.ID})
+		if value == current.Value {
+			t.addStep(StepFound, t.msg("tree.path.found", value, len(path)), &current.ID, path)
+			result := t.newResult(true, t.msg("tree.search.found", value))
+			result.PathIDs = path
+			return result
+		} else if value < current.Value {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+
+	t.addStep(StepNotFound, t.msg("tree.path.missing", value), nil, path)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	result.PathIDs = path
+	return result
+}
+
+// PathTo searches the Red-Black Tree for value and highlights every node on
+// the path from the root to it. When the value is missing the traversed
+// search path is highlighted instead.
+func (t *RedBlackTree) PathTo(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	path := make([]int, 0)
+	x := t.Root
+	for x != t.NIL {
+		path = append(path, x.ID)
+		t.addStep(StepCompare, t.msg("tree.compare", value, x.Value), &x.ID, []int{x.ID})
+		if value == x.Value {
+			t.addStep(StepFound, t.msg("tree.path.found", value, len(path)), &x.ID, path)
+			result := t.newResult(true, t.msg("tree.search.found", value))
+			result.PathIDs = path
+			return result
+		} else if value < x.Value {
+			x = x.Left
+		} else {
+			x = x.Right
+		}
+	}
+
+	t.addStep(StepNotFound, t.msg("tree.path.missing", value), nil, path)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	result.PathIDs = path
+	return result
+}
+
+// Depth searches the AVL Tree for value and reports its depth, with the
+// root at depth 0
+func (t *AVLTree) Depth(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	current := t.Root
+	for depth := 0; current != nil; depth++ {
+		t.addStep(StepCompare, t.msg("tree.depth.compare", value, current.Value, depth), &current.ID, []int{current.ID})
+		if value == current.Value {
+			t.addStep(StepFound, t.msg("tree.depth.found", value, depth), &current.ID, []int{current.ID})
+			result := t.newResult(true, t.msg("tree.depth.success", value, depth))
+			result.Depth = &depth
+			return result
+		} else if value < current.Value {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+
+	t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
+}
+
+// Depth searches the Red-Black Tree for value and reports its depth, with
+// the root at depth 0
+func (t *RedBlackTree) Depth(value int) OperationResult {
+	t.clearSteps()
+	t.operand = &value
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+
+	x := t.Root
+	for depth := 0; x != t.NIL; depth++ {
+		t.addStep(StepCompare, t.msg("tree.depth.compare", value, x.Value, depth), &x.ID, []int{x.ID})
+		if value == x.Value {
+			t.addStep(StepFound, t.msg("tree.depth.found", value, depth), &x.ID, []int{x.ID})
+			result := t.newResult(true, t.msg("tree.depth.success", value, depth))
+			result.Depth = &depth
+			return result
+		} else if value < x.Value {
+			x = x.Left
+		} else {
+			x = x.Right
+		}
+	}
+
+	t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	result := t.newResult(false, t.msg("tree.search.missing", value))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
+}
+
+// missingValues returns the distinct values that are not in the tree, in
+// order
+func (t *AVLTree) missingValues(values ...int) []int {
+	missing := make([]int, 0)
+	for i, value := range values {
+		if !t.Contains(value) && !slices.Contains(values[:i], value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// lcaMissing is the result of an LCA query naming absent values, recording
+// a step for each of them
+func (t *AVLTree) lcaMissing(missing []int) OperationResult {
+	for _, value := range missing {
+		t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	}
+	result := t.newResult(false, t.msg("tree.lca.missing", missing))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
+}
+
+// LCA finds the lowest common ancestor of values a and b in the AVL Tree,
+// walking down from the root while both values lie on the same side of the
+// current node. The node where they split is the ancestor; the path to it is
+// highlighted. Both values must be in the tree; every absent one is reported.
+func (t *AVLTree) LCA(a, b int) OperationResult {
+	t.clearSteps()
+
+	if t.Root == nil {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+	if missing := t.missingValues(a, b); len(missing) > 0 {
+		return t.lcaMissing(missing)
+	}
+
+	lo, hi := min(a, b), max(a, b)
+	path := make([]int, 0)
+	current := t.Root
+	for {
+		path = append(path, current.ID)
+		t.addStep(StepCompare, t.msg("tree.lca.compare", lo, hi, current.Value), &current.ID, []int{current.ID})
+		if hi < current.Value {
+			current = current.Left
+		} else if lo > current.Value {
+			current = current.Right
+		} else {
+			break
+		}
+	}
+
+	t.addStep(StepFound, t.msg("tree.lca.found", a, b, current.Value), &current.ID, path)
+	result := t.newResult(true, t.msg("tree.lca.success", a, b, current.Value))
+	result.PathIDs = path
+	if node, ok := findSnapshotNode(result.FinalTree, current.ID); ok {
+		result.Node = &node
+	}
+	return result
+}
+
+// missingValues returns the distinct values that are not in the tree, in
+// order
+func (t *RedBlackTree) missingValues(values ...int) []int {
+	missing := make([]int, 0)
+	for i, value := range values {
+		if !t.Contains(value) && !slices.Contains(values[:i], value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// lcaMissing is the result of an LCA query naming absent values, recording
+// a step for each of them
+func (t *RedBlackTree) lcaMissing(missing []int) OperationResult {
+	for _, value := range missing {
+		t.addStep(StepNotFound, t.msg("tree.search.missing_step", value), nil)
+	}
+	result := t.newResult(false, t.msg("tree.lca.missing", missing))
+	result.Reason = ReasonNotFound
+	result.NoOp = true
+	return result
+}
+
+// LCA finds the lowest common ancestor of values a and b in the Red-Black
+// Tree, walking down from the root while both values lie on the same side
+// of the current node. The node where they split is the ancestor; the path
+// to it is highlighted. Both values must be in the tree; every absent one
+// is reported.
+func (t *RedBlackTree) LCA(a, b int) OperationResult {
+	t.clearSteps()
+
+	if t.Root == t.NIL {
+		t.addStep(StepNotFound, t.msg("tree.search.empty"), nil)
+		result := t.newResult(false, t.msg("tree.empty"))
+		result.Reason = ReasonEmptyTree
+		result.NoOp = true
+		return result
+	}
+	if missing := t.missingValues(a, b); len(missing) > 0 {
+		return t.lcaMissing(missing)
+	}
+
+	lo, hi := min(a, b), max(a, b)
+	path := make([]int, 0)
+	x := t.Root
+	for {
+		path = append(path, x.ID)
+		t.addStep(StepCompare, t.msg("tree.lca.compare", lo, hi, x.Value), &x.ID, []int{x.ID})
+		if hi < x.Value {
+			x = x.Left
+		} else if lo > x.Value {
+			x = x.Right
+		} else {
+			break
+		}
+	}
+
+	t.addStep(StepFound, t.msg("tree.lca.found", a, b, x.Value), &x.ID, path)
+	result := t.newResult(true, t.msg("tree.lca.success", a, b, x.Value))
+	result.PathIDs = path
+	if node, ok := findSnapshotNode(result.FinalTree, x.ID); ok {
+		result.Node = &node
+	}
+	return result
+}
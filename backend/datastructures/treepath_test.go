@@ -0,0 +1,102 @@
+package datastructures
+
+import "testing"
+
+// pathTree is the behaviour shared by the trees with path queries
+type pathTree interface {
+	Insert(value int) OperationResult
+	Search(value int) OperationResult
+	Depth(value int) OperationResult
+	LCA(a, b int) OperationResult
+}
+
+// newPathTrees returns the perfect tree of 1..7 rooted at 4 in every tree
+// with path queries. Inserted in this order neither tree rotates.
+func newPathTrees() map[string]pathTree {
+	trees := map[string]pathTree{
+		"rbtree":  NewRedBlackTree(),
+		"avltree": NewAVLTree(),
+	}
+	for _, tree := range trees {
+		for _, v := range []int{4, 2, 6, 1, 3, 5, 7} {
+			tree.Insert(v)
+		}
+	}
+	return trees
+}
+
+func TestDepth(t *testing.T) {
+	depths := map[int]int{4: 0, 2: 1, 6: 1, 1: 2, 3: 2, 5: 2, 7: 2}
+	for name, tree := range newPathTrees() {
+		t.Run(name, func(t *testing.T) {
+			for value, want := range depths {
+				result := tree.Depth(value)
+				if !result.Success || result.Depth == nil || *result.Depth != want {
+					t.Errorf("Depth(%d): success %v depth %v, want %d", value, result.Success, result.Depth, want)
+				}
+			}
+			result := tree.Depth(8)
+			if result.Success || result.Depth != nil || result.Reason != ReasonNotFound || !result.NoOp {
+				t.Errorf("Depth(8): success %v depth %v reason %q", result.Success, result.Depth, result.Reason)
+			}
+		})
+	}
+	for name, tree := range map[string]pathTree{"rbtree": NewRedBlackTree(), "avltree": NewAVLTree()} {
+		if result := tree.Depth(4); result.Success || result.Reason != ReasonEmptyTree {
+			t.Errorf("%s empty tree: success %v reason %q", name, result.Success, result.Reason)
+		}
+	}
+}
+
+func TestSearchComparesOncePerLevel(t *testing.T) {
+	depths := map[int]int{4: 0, 2: 1, 6: 1, 1: 2, 3: 2, 5: 2, 7: 2}
+	for name, tree := range newPathTrees() {
+		t.Run(name, func(t *testing.T) {
+			for value, depth := range depths {
+				result := tree.Search(value)
+				result.Finalize()
+				if result.ComparisonCount != depth+1 {
+					t.Errorf("Search(%d) at depth %d: %d comparisons, want %d", value, depth, result.ComparisonCount, depth+1)
+				}
+			}
+			// A miss compares against every node down to a leaf
+			result := tree.Search(8)
+			result.Finalize()
+			if result.ComparisonCount != 3 {
+				t.Errorf("Search(8): %d comparisons, want 3", result.ComparisonCount)
+			}
+		})
+	}
+}
+
+func TestLCA(t *testing.T) {
+	cases := []struct {
+		name    string
+		a, b    int
+		want    int
+		visited int
+	}{
+		{"same value", 3, 3, 3, 3},
+		{"ancestor and descendant", 2, 3, 2, 2},
+		{"descendant and ancestor", 7, 6, 6, 2},
+		{"siblings", 1, 3, 2, 2},
+		{"across the root", 1, 7, 4, 1},
+	}
+	for name, tree := range newPathTrees() {
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range cases {
+				result := tree.LCA(tc.a, tc.b)
+				if !result.Success || result.Node == nil || result.Node.Value != tc.want {
+					t.Errorf("%s: LCA(%d, %d) success %v node %+v, want %d", tc.name, tc.a, tc.b, result.Success, result.Node, tc.want)
+					continue
+				}
+				if len(result.PathIDs) != tc.visited || result.PathIDs[len(result.PathIDs)-1] != result.Node.ID {
+					t.Errorf("%s: path %v should end at node %d after %d nodes", tc.name, result.PathIDs, result.Node.ID, tc.visited)
+				}
+			}
+			if result := tree.LCA(1, 9); result.Success || result.Reason != ReasonNotFound {
+				t.Errorf("missing value: success %v reason %q", result.Success, result.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package datastructures
+
+import "time"
+
+// PathThroughWaypoints finds the shortest route that visits nodes in order
+// by running Dijkstra between every pair of consecutive waypoints. The
+// segments' steps are concatenated, each segment starting from a fresh
+// Dijkstra state (its first step's delta is against an empty graph). Paths
+// holds the combined route first, followed by every segment.
+func (g *Graph) PathThroughWaypoints(nodes []string) OperationResult {
+	g.clearSteps()
+	started := g.started
+
+	if len(nodes) < 2 {
+		return OperationResult{
+			Success: false,
+			Message: g.msg("graph.waypoints.too_few"),
+			Code:    CodeInvalidParam,
+			Steps:   []Step{},
+		}
+	}
+	for _, id := range nodes {
+		if !g.HasNode(id) {
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.node_missing", id),
+				Code:    CodeNodeNotFound,
+				Steps:   []Step{},
+			}
+		}
+	}
+
+	steps := make([]Step, 0)
+	route := []string{nodes[0]}
+	total := 0.0
+	segments := make([]PathResult, 0, len(nodes)-1)
+	for i := 1; i < len(nodes); i++ {
+		from, to := nodes[i-1], nodes[i]
+		result := g.Dijkstra(from, to)
+		g.resumeClock(started, result.Steps)
+		if result.Reason == ReasonCanceled {
+			result.Steps = restampSteps(append(steps, result.Steps...))
+			return result
+		}
+		// The initial step of every run introduces its segment
+		result.Steps[0].Description = g.msg("graph.waypoints.segment", i, len(nodes)-1, from, to)
+		steps = append(steps, result.Steps...)
+		if !result.Success {
+			g.steps = restampSteps(steps)
+			return OperationResult{
+				Success: false,
+				Message: g.msg("graph.waypoints.unreachable", from, to),
+				Reason:  ReasonUnreachable,
+				NoOp:    true,
+				Steps:   g.steps,
+			}
+		}
+
+		segment := result.Paths[0]
+		segments = append(segments, segment)
+		route = append(route, segment.Nodes[1:]...)
+		total += segment.Cost
+	}
+
+	g.steps = restampSteps(steps)
+	g.addStep(StepComplete, g.msg("graph.waypoints.found", route, total), nil, nil, route, nil)
+	return OperationResult{
+		Success:    true,
+		Message:    g.msg("graph.waypoints.success", len(segments), total),
+		Steps:      g.steps,
+		FinalGraph: g.latest,
+		Paths:      append([]PathResult{{Nodes: route, Cost: total}}, segments...),
+	}
+}
+
+// restampSteps renumbers steps joined from several runs so their indices
+// follow the combined log
+func restampSteps(steps []Step) []Step {
+	for i := range steps {
+		steps[i].Index = i
+	}
+	return steps
+}
+
+// resumeClock shifts the timestamps of steps a nested run recorded after
+// restarting the clock, so they count from started like the rest of the
+// enclosing operation, and restores started for the steps that follow
+func (g *Graph) resumeClock(started time.Time, steps []Step) {
+	offset := g.started.Sub(started).Microseconds()
+	for i := range steps {
+		steps[i].Timestamp += offset
+	}
+	g.started = started
+}
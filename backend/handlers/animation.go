@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"gin/datastructures"
+)
+
+// AnimationSchemaVersion is the version of the AnimationScript format. It is
+// bumped whenever a field changes meaning or is removed.
+const AnimationSchemaVersion = 2
+
+// AnimationScript bundles an operation's complete step log with the state it
+// started from and the context it ran in, so that an external player can
+// replay it without the server
+type AnimationScript struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	Structure     string                 `json:"structure"`
+	Operation     string                 `json:"operation"`
+	Params        map[string]interface{} `json:"params,omitempty"`
+	// Timestamp is when the script was exported, in microseconds since the
+	// Unix epoch. Step timestamps count from the start of the operation
+	// since version 2.
+	Timestamp  int64 `json:"timestamp"`
+	TotalSteps int   `json:"totalSteps"`
+
+	// InitialTree or InitialGraph is the structure before the first step
+	InitialTree  []datastructures.TreeNodeSnapshot `json:"initialTree,omitempty"`
+	InitialGraph *datastructures.GraphState        `json:"initialGraph,omitempty"`
+
+	Steps []datastructures.Step `json:"steps"`
+
+	Success    bool                              `json:"success"`
+	Message    string                            `json:"message,omitempty"`
+	FinalTree  []datastructures.TreeNodeSnapshot `json:"finalTree,omitempty"`
+	FinalGraph *datastructures.GraphState        `json:"finalGraph,omitempty"`
+}
+
+// captureInitialState returns the state of req's structure before it runs.
+// Callers must hold stateMu.
+func captureInitialState(ctx context.Context, req OperationRequest) datastructures.OperationResult {
+	state, _ := dispatchOperation(ctx, OperationRequest{
+		Structure: req.Structure,
+		Operation: "state",
+		Params:    req.Params,
+	})
+	return state
+}
+
+// newAnimationScript builds the script of req from the state captured before
+// it ran and its unfiltered result
+func newAnimationScript(req OperationRequest, initial, result datastructures.OperationResult) AnimationScript {
+	return AnimationScript{
+		SchemaVersion: AnimationSchemaVersion,
+		Structure:     req.Structure,
+		Operation:     req.Operation,
+		Params:        req.Params,
+		Timestamp:     time.Now().UnixMicro(),
+		TotalSteps:    len(result.Steps),
+		InitialTree:   initial.FinalTree,
+		InitialGraph:  initial.FinalGraph,
+		Steps:         result.Steps,
+		Success:       result.Success,
+		Message:       result.Message,
+		FinalTree:     result.FinalTree,
+		FinalGraph:    result.FinalGraph,
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleExportASCII renders the tree named by the structure query param
+// (rbtree or avltree) of the caller's session as a plain-text diagram for
+// terminals and markdown
+func HandleExportASCII(c *gin.Context) {
+	structure := c.Query("structure")
+
+	stateMu.Lock()
+	state := sessionFor(requestSession(c))
+	var text string
+	switch structure {
+	case "rbtree":
+		text = state.rbTree.ToASCII()
+	case "avltree":
+		text = state.avlTree.ToASCII()
+	default:
+		stateMu.Unlock()
+		respondError(c, http.StatusBadRequest, datastructures.CodeUnknownStructure, "ASCII export is not supported for structure: "+structure)
+		return
+	}
+	stateMu.Unlock()
+
+	c.String(http.StatusOK, text)
+}
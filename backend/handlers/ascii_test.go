@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestExportASCIIRendersSessionTree(t *testing.T) {
+	state := freshSession()
+	for _, v := range []int{2, 1, 3} {
+		state.rbTree.Insert(v)
+	}
+	r := gin.New()
+	r.GET("/", HandleExportASCII)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?structure=rbtree", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if want := "2 (B)\n├── L: 1 (R)\n└── R: 3 (R)\n"; w.Body.String() != want {
+		t.Errorf("body =\n%s\nwant\n%s", w.Body, want)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?structure=heap", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("heap export status %d, want 400", w.Code)
+	}
+}
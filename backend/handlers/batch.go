@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gin/datastructures"
+	"gin/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBatchOperations bounds the number of operations in a single batch
+const MaxBatchOperations = 100
+
+// BatchRequest represents a script of operations applied in order
+type BatchRequest struct {
+	Operations []OperationRequest `json:"operations" binding:"required"`
+	// Atomic rolls back every change if any operation fails
+	Atomic bool `json:"atomic"`
+}
+
+// HandleBatch applies a script of operations in order and returns every
+// result together with the final state of all structures
+func HandleBatch(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, "Invalid request: "+err.Error())
+		return
+	}
+	if len(req.Operations) > MaxBatchOperations {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, fmt.Sprintf("A batch may contain at most %d operations", MaxBatchOperations))
+		return
+	}
+	for i, op := range req.Operations {
+		if op.Structure == "" || op.Operation == "" {
+			respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, fmt.Sprintf("Operation %d needs a structure and an operation", i))
+			return
+		}
+		if err := validateParams(op); err != nil {
+			respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, fmt.Sprintf("Operation %d: %s", i, err))
+			return
+		}
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	// The whole batch shares one time budget
+	session := requestSession(c)
+	ctx, cancel := context.WithTimeout(withSession(c.Request.Context(), session), OperationTimeout)
+	defer cancel()
+
+	var before store.Snapshot
+	if req.Atomic {
+		before = captureState(sessionFor(session))
+	}
+
+	results := make([]datastructures.OperationResult, 0, len(req.Operations))
+	mutated := false
+	failedAt := -1
+	for i, op := range req.Operations {
+		result, ok := dispatchOperation(ctx, op)
+		if !ok {
+			result = datastructures.OperationResult{
+				Success: false,
+				Message: "Unknown structure: " + op.Structure,
+				Steps:   []datastructures.Step{},
+			}
+		}
+		results = append(results, result)
+		mutated = mutated || mutatingOperations[op.Operation]
+
+		if !result.Success && !result.NoOp {
+			failedAt = i
+			if req.Atomic {
+				break
+			}
+		}
+	}
+
+	rolledBack := false
+	if req.Atomic && failedAt >= 0 {
+		if err := restoreState(sessionFor(session), before); err != nil {
+			respondError(c, http.StatusInternalServerError, datastructures.CodeInternal, "Rollback failed: "+err.Error())
+			return
+		}
+		rolledBack = true
+	} else if mutated {
+		persistState(session)
+	}
+
+	state := sessionFor(session)
+	response := gin.H{
+		"success":    failedAt < 0,
+		"results":    results,
+		"rolledBack": rolledBack,
+		"final": gin.H{
+			"rbtree":  state.rbTree.State().FinalTree,
+			"avltree": state.avlTree.State().FinalTree,
+			"tree234": state.tree234.State().FinalTree,
+			"heap":    state.heap.State().FinalTree,
+			"array":   state.sorter.Values(),
+			"graph":   state.graph.State().FinalGraph,
+		},
+	}
+	if failedAt >= 0 {
+		response["failedAt"] = failedAt
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serveJSON sends body as JSON to handler and returns the recorded response
+func serveJSON(t *testing.T, method string, handler gin.HandlerFunc, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	r.Handle(method, "/", handler)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "/", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// freshSession gives the default session new structures and returns them
+func freshSession() *sessionState {
+	resetSession(defaultSession)
+	return sessionFor(defaultSession)
+}
+
+func TestAtomicBatchRollsBackEveryStructure(t *testing.T) {
+	cases := []OperationRequest{
+		{Structure: "rbtree", Operation: "insert", Params: map[string]interface{}{"value": 7}},
+		{Structure: "avltree", Operation: "insert", Params: map[string]interface{}{"value": 7}},
+		{Structure: "tree234", Operation: "insert", Params: map[string]interface{}{"value": 7}},
+		{Structure: "heap", Operation: "insert", Params: map[string]interface{}{"value": 7}},
+		{Structure: "array", Operation: "quicksort", Params: map[string]interface{}{"values": []int{3, 1, 2}}},
+		{Structure: "graph", Operation: "insert", Params: map[string]interface{}{"value": 7}},
+	}
+	for _, op := range cases {
+		t.Run(op.Structure, func(t *testing.T) {
+			state := freshSession()
+			state.sorter.QuickSort([]int{9, 8})
+			before, _ := json.Marshal(captureState(state))
+
+			w := serveJSON(t, http.MethodPost, HandleBatch, BatchRequest{
+				Atomic: true,
+				Operations: []OperationRequest{
+					op,
+					{Structure: "missing", Operation: "insert"},
+				},
+			})
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+			var resp struct {
+				RolledBack bool                             `json:"rolledBack"`
+				Results    []datastructures.OperationResult `json:"results"`
+				Final      map[string]json.RawMessage       `json:"final"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if !resp.RolledBack {
+				t.Fatal("batch was not rolled back")
+			}
+			if !resp.Results[0].Success {
+				t.Fatalf("%s %s failed: %s", op.Structure, op.Operation, resp.Results[0].Message)
+			}
+			if _, ok := resp.Final[op.Structure]; !ok {
+				t.Errorf("final snapshot has no %s", op.Structure)
+			}
+
+			after, _ := json.Marshal(captureState(sessionFor(defaultSession)))
+			if !bytes.Equal(before, after) {
+				t.Errorf("state changed by rolled back batch:\nbefore %s\nafter  %s", before, after)
+			}
+		})
+	}
+}
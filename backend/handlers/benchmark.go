@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"gin/benchmark"
+	"gin/datastructures"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,21 +18,112 @@ type BenchmarkRequest struct {
 	DataSize   int      `json:"dataSize" binding:"required"`
 	Structures []string `json:"structures" binding:"required"`
 	Operation  string   `json:"operation" binding:"required"`
+	// Seed reproduces a previous run; a clock-based seed is used when absent
+	Seed *int64 `json:"seed"`
+	// Sorted feeds the structures ascending data instead of random data
+	Sorted bool `json:"sorted"`
+	// Sequential runs the structures one at a time for accurate timing and
+	// memory figures
+	Sequential bool `json:"sequential"`
+	// BTreeOrder is the minimum degree of the btree structure, at least 2.
+	// benchmark.DefaultBTreeOrder is used when absent.
+	BTreeOrder *int `json:"btreeOrder"`
+	// ReportEveryPercent sets the progress step between SSE updates,
+	// between 1 and 50. benchmark.DefaultReportPercent is used when absent.
+	ReportEveryPercent *int `json:"reportEveryPercent"`
 }
 
+// benchmarkRunners holds the runner of every session with a benchmark in
+// progress, so that clients can run and stop benchmarks independently
 var (
-	benchmarkRunner = benchmark.NewRunner()
-	runnerMutex     sync.Mutex
+	benchmarkRunners = make(map[string]*benchmark.Runner)
+	runnerMutex      sync.Mutex
+)
+
+// acquireRunner registers a new runner for session, or returns false if the
+// session already has a benchmark in progress
+func acquireRunner(session string) (*benchmark.Runner, bool) {
+	runnerMutex.Lock()
+	defer runnerMutex.Unlock()
+	if _, busy := benchmarkRunners[session]; busy {
+		return nil, false
+	}
+	runner := benchmark.NewRunner()
+	benchmarkRunners[session] = runner
+	return runner, true
+}
+
+// releaseRunner unregisters runner if it is still the one of session
+func releaseRunner(session string, runner *benchmark.Runner) {
+	runnerMutex.Lock()
+	defer runnerMutex.Unlock()
+	if benchmarkRunners[session] == runner {
+		delete(benchmarkRunners, session)
+	}
+}
+
+var (
+	// MaxBenchmarkDataSize caps the number of elements a benchmark may use
+	MaxBenchmarkDataSize = 1000000
+	// BenchmarkTimeout is the time budget after which a benchmark stops itself
+	BenchmarkTimeout = 2 * time.Minute
 )
 
 // HandleBenchmarkSSE handles SSE connections for benchmark progress
 func HandleBenchmarkSSE(c *gin.Context) {
 	var req BenchmarkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request: " + err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, "Invalid request: "+err.Error())
+		return
+	}
+	if req.DataSize <= 0 || req.DataSize > MaxBenchmarkDataSize {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, fmt.Sprintf("dataSize must be between 1 and %d", MaxBenchmarkDataSize))
+		return
+	}
+	// Each structure may appear once: the runner tracks progress by name,
+	// so a repeat would also skew the overall progress
+	if len(req.Structures) > len(benchmark.Structures) {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, fmt.Sprintf("at most %d structures can be benchmarked at once", len(benchmark.Structures)))
+		return
+	}
+	seen := make(map[string]bool, len(req.Structures))
+	for _, structure := range req.Structures {
+		if !benchmark.IsStructure(structure) {
+			respondError(c, http.StatusBadRequest, datastructures.CodeUnknownStructure, "Unknown structure: "+structure)
+			return
+		}
+		if seen[structure] {
+			respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Duplicate structure: "+structure)
+			return
+		}
+		seen[structure] = true
+	}
+	if !benchmark.IsOperation(req.Operation) {
+		respondError(c, http.StatusBadRequest, datastructures.CodeUnknownOperation, "Unknown operation: "+req.Operation)
+		return
+	}
+
+	order := benchmark.DefaultBTreeOrder
+	if req.BTreeOrder != nil {
+		if *req.BTreeOrder < benchmark.MinBTreeOrder {
+			respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, fmt.Sprintf("btreeOrder must be at least %d", benchmark.MinBTreeOrder))
+			return
+		}
+		order = *req.BTreeOrder
+	}
+	reportPercent := benchmark.DefaultReportPercent
+	if req.ReportEveryPercent != nil {
+		if *req.ReportEveryPercent < benchmark.MinReportPercent || *req.ReportEveryPercent > benchmark.MaxReportPercent {
+			respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, fmt.Sprintf("reportEveryPercent must be between %d and %d", benchmark.MinReportPercent, benchmark.MaxReportPercent))
+			return
+		}
+		reportPercent = *req.ReportEveryPercent
+	}
+
+	session := requestSession(c)
+	runner, ok := acquireRunner(session)
+	if !ok {
+		respondError(c, http.StatusConflict, datastructures.CodeBenchmarkRunning, "A benchmark is already running for session "+session)
 		return
 	}
 
@@ -38,26 +131,53 @@ func HandleBenchmarkSSE(c *gin.Context) {
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
-	c.Header("Access-Control-Allow-Origin", "*")
 
 	// Channel for streaming results
 	resultChan := make(chan benchmark.BenchmarkResult, 100)
 	doneChan := make(chan struct{})
+	clientGone := c.Request.Context().Done()
+
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	// Announce the run ID so the client can fetch a summary afterwards, and
+	// the seed so the run can be reproduced
+	runID, run := newBenchmarkRun()
+	fmt.Fprintf(c.Writer, "event: run\ndata: {\"runId\": \"%s\", \"seed\": %d}\n\n", runID, seed)
+	c.Writer.Flush()
 
 	// Start benchmark in goroutine
 	go func() {
 		defer close(doneChan)
-		runnerMutex.Lock()
-		runner := benchmarkRunner
-		runnerMutex.Unlock()
+		defer releaseRunner(session, runner)
+		defer run.finish()
 
 		config := benchmark.BenchmarkConfig{
-			DataSize:   req.DataSize,
-			Structures: req.Structures,
-			Operation:  req.Operation,
+			DataSize:           req.DataSize,
+			Structures:         req.Structures,
+			Operation:          req.Operation,
+			Seed:               seed,
+			Sorted:             req.Sorted,
+			BTreeOrder:         order,
+			Sequential:         req.Sequential,
+			ReportEveryPercent: reportPercent,
+			Timeout:            BenchmarkTimeout,
 		}
 
 		runner.RunBenchmark(config, func(result benchmark.BenchmarkResult) {
+			if result.Completed {
+				run.record(result)
+			}
+			if result.Stopped {
+				// The terminal result must not be dropped
+				select {
+				case resultChan <- result:
+				case <-clientGone:
+				}
+				return
+			}
 			select {
 			case resultChan <- result:
 			default:
@@ -67,29 +187,48 @@ func HandleBenchmarkSSE(c *gin.Context) {
 	}()
 
 	// Stream results
-	clientGone := c.Request.Context().Done()
 	completedCount := 0
 	totalStructures := len(req.Structures)
 
 	for {
 		select {
 		case <-clientGone:
-			benchmarkRunner.Stop()
+			runner.Stop()
 			return
 		case result := <-resultChan:
 			data, _ := json.Marshal(result)
 			fmt.Fprintf(c.Writer, "data: %s\n\n", data)
 			c.Writer.Flush()
 
+			if result.Stopped {
+				fmt.Fprintf(c.Writer, "event: stopped\ndata: {\"message\": \"Benchmark exceeded %s and was stopped\"}\n\n", BenchmarkTimeout)
+				c.Writer.Flush()
+				return
+			}
+
 			if result.Completed {
 				completedCount++
 				if completedCount >= totalStructures {
+					// Wait for the run to be recorded as finished so its
+					// summary is available once the client sees this event
+					<-doneChan
 					fmt.Fprintf(c.Writer, "event: complete\ndata: {\"message\": \"All benchmarks completed\"}\n\n")
 					c.Writer.Flush()
 					return
 				}
 			}
 		case <-doneChan:
+			// Forward a terminal stop result that raced with completion
+			select {
+			case result := <-resultChan:
+				if result.Stopped {
+					data, _ := json.Marshal(result)
+					fmt.Fprintf(c.Writer, "data: %s\n\nevent: stopped\ndata: {\"message\": \"Benchmark exceeded %s and was stopped\"}\n\n", data, BenchmarkTimeout)
+					c.Writer.Flush()
+					return
+				}
+			default:
+			}
 			fmt.Fprintf(c.Writer, "event: complete\ndata: {\"message\": \"All benchmarks completed\"}\n\n")
 			c.Writer.Flush()
 			return
@@ -97,11 +236,14 @@ func HandleBenchmarkSSE(c *gin.Context) {
 	}
 }
 
-// HandleStopBenchmark stops any running benchmark
+// HandleStopBenchmark stops the running benchmark of the caller's session
 func HandleStopBenchmark(c *gin.Context) {
+	session := requestSession(c)
 	runnerMutex.Lock()
-	benchmarkRunner.Stop()
-	benchmarkRunner = benchmark.NewRunner()
+	if runner, ok := benchmarkRunners[session]; ok {
+		runner.Stop()
+		delete(benchmarkRunners, session)
+	}
 	runnerMutex.Unlock()
 
 	c.JSON(http.StatusOK, gin.H{
@@ -110,11 +252,42 @@ func HandleStopBenchmark(c *gin.Context) {
 	})
 }
 
-// HandleBenchmarkStatus returns current benchmark status
+// StopBenchmarks stops every running benchmark so their SSE streams can finish
+func StopBenchmarks() {
+	runnerMutex.Lock()
+	defer runnerMutex.Unlock()
+	for _, runner := range benchmarkRunners {
+		runner.Stop()
+	}
+}
+
+// benchmarksRunning returns the number of sessions with a benchmark in
+// progress
+func benchmarksRunning() int {
+	runnerMutex.Lock()
+	defer runnerMutex.Unlock()
+	running := 0
+	for _, runner := range benchmarkRunners {
+		if runner.Running() {
+			running++
+		}
+	}
+	return running
+}
+
+// HandleBenchmarkStatus returns current benchmark status for the caller's
+// session
 func HandleBenchmarkStatus(c *gin.Context) {
+	session := requestSession(c)
+	runnerMutex.Lock()
+	runner, ok := benchmarkRunners[session]
+	runnerMutex.Unlock()
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":    true,
-		"structures": []string{"hashmap", "btree", "rbtree", "avltree"},
-		"operations": []string{"insert", "search"},
+		"session":    session,
+		"running":    ok && runner.Running(),
+		"structures": benchmark.Structures,
+		"operations": benchmark.Operations,
 	})
 }
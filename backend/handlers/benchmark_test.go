@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBenchmarkRejectsInvalidRequests(t *testing.T) {
+	order := 1
+	tests := []struct {
+		name string
+		req  BenchmarkRequest
+		code string
+	}{
+		{"oversize", BenchmarkRequest{DataSize: MaxBenchmarkDataSize + 1, Structures: []string{"rbtree"}, Operation: "insert"}, "INVALID_PARAM"},
+		{"negative size", BenchmarkRequest{DataSize: -1, Structures: []string{"rbtree"}, Operation: "insert"}, "INVALID_PARAM"},
+		{"unknown structure", BenchmarkRequest{DataSize: 10, Structures: []string{"rbtree", "skiplist"}, Operation: "insert"}, "UNKNOWN_STRUCTURE"},
+		{"duplicate structure", BenchmarkRequest{DataSize: 10, Structures: []string{"rbtree", "bst", "rbtree"}, Operation: "insert"}, "INVALID_PARAM"},
+		{"too many structures", BenchmarkRequest{DataSize: 10, Structures: []string{"hashmap", "btree", "bst", "rbtree", "avltree", "hashmap"}, Operation: "insert"}, "INVALID_PARAM"},
+		{"unknown operation", BenchmarkRequest{DataSize: 10, Structures: []string{"rbtree"}, Operation: "shuffle"}, "UNKNOWN_OPERATION"},
+		{"btree order", BenchmarkRequest{DataSize: 10, Structures: []string{"btree"}, Operation: "insert", BTreeOrder: &order}, "INVALID_PARAM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveJSON(t, http.MethodPost, HandleBenchmarkSSE, tt.req)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want 400: %s", w.Code, w.Body)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["code"] != tt.code {
+				t.Errorf("code %v, want %s", body["code"], tt.code)
+			}
+		})
+	}
+	if benchmarksRunning() != 0 {
+		t.Error("a rejected request left a runner registered")
+	}
+}
+
+// serveSession sends body as JSON to handler on behalf of session
+func serveSession(t *testing.T, handler gin.HandlerFunc, session string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	r.POST("/", handler)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", session)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestBenchmarkRunnersArePerSession(t *testing.T) {
+	alice, ok := acquireRunner("alice")
+	if !ok {
+		t.Fatal("alice already has a runner")
+	}
+	defer releaseRunner("alice", alice)
+	bob, ok := acquireRunner("bob")
+	if !ok {
+		t.Fatal("bob was blocked by alice's runner")
+	}
+	defer releaseRunner("bob", bob)
+	if _, ok := acquireRunner("alice"); ok {
+		t.Fatal("alice got a second runner")
+	}
+
+	w := serveSession(t, HandleBenchmarkSSE, "alice", BenchmarkRequest{DataSize: 10, Structures: []string{"rbtree"}, Operation: "insert"})
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "BENCHMARK_RUNNING") {
+		t.Errorf("second alice run: status %d: %s", w.Code, w.Body)
+	}
+
+	serveSession(t, HandleStopBenchmark, "alice", nil)
+	runnerMutex.Lock()
+	_, aliceRunning := benchmarkRunners["alice"]
+	_, bobRunning := benchmarkRunners["bob"]
+	runnerMutex.Unlock()
+	if aliceRunning || !bobRunning {
+		t.Errorf("after stopping alice: alice registered %v, bob registered %v", aliceRunning, bobRunning)
+	}
+}
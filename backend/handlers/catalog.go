@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParamDescriptor describes one param of a cataloged operation
+type ParamDescriptor struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// OperationDescriptor describes one cataloged operation
+type OperationDescriptor struct {
+	Name   string            `json:"name"`
+	Params []ParamDescriptor `json:"params"`
+}
+
+// String returns the JSON type name of a param kind
+func (k paramKind) String() string {
+	switch k {
+	case intParam:
+		return "integer"
+	case arrayParam:
+		return "array"
+	case objectParam:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// describeOperations builds the descriptors of every operation of a
+// registered structure from the required params table
+func describeOperations(structure string, s Structure) []OperationDescriptor {
+	names := s.Operations()
+	ops := make([]OperationDescriptor, 0, len(names))
+	for _, op := range names {
+		required := requiredParams[structure][op]
+		keys := make([]string, 0, len(required))
+		for key := range required {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		params := make([]ParamDescriptor, 0, len(keys))
+		for _, name := range keys {
+			params = append(params, ParamDescriptor{
+				Name:     name,
+				Type:     required[name].String(),
+				Required: true,
+			})
+		}
+		ops = append(ops, OperationDescriptor{Name: op, Params: params})
+	}
+	return ops
+}
+
+// HandleStructures returns the catalog of registered structures, their
+// operations and the params each operation requires
+func HandleStructures(c *gin.Context) {
+	catalog := make(map[string][]OperationDescriptor)
+	for _, name := range structures.Names() {
+		s, _ := structures.Lookup(name)
+		catalog[name] = describeOperations(name, s)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"structures": catalog,
+	})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestCatalogDescribesRedBlackOperations(t *testing.T) {
+	w := serveJSON(t, http.MethodGet, HandleStructures, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	var resp struct {
+		Structures map[string][]OperationDescriptor `json:"structures"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := resp.Structures["rbtree"]
+	value := ParamDescriptor{Name: "value", Type: "integer", Required: true}
+	for _, name := range []string{"insert", "search", "delete"} {
+		i := slices.IndexFunc(ops, func(op OperationDescriptor) bool { return op.Name == name })
+		if i < 0 {
+			t.Errorf("rbtree has no %s operation", name)
+			continue
+		}
+		if !slices.Contains(ops[i].Params, value) {
+			t.Errorf("rbtree %s params %v, want %v", name, ops[i].Params, value)
+		}
+	}
+	for _, name := range []string{"avltree", "tree234", "heap", "array", "graph"} {
+		if len(resp.Structures[name]) == 0 {
+			t.Errorf("catalog has no operations for %s", name)
+		}
+	}
+}
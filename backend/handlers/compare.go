@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompareOperation is a single operation in a shared compare sequence
+type CompareOperation struct {
+	Operation string                 `json:"operation" binding:"required"`
+	Params    map[string]interface{} `json:"params"`
+}
+
+// CompareRequest represents a request to run one operation sequence on several structures
+type CompareRequest struct {
+	Structures []string           `json:"structures" binding:"required"`
+	Operations []CompareOperation `json:"operations" binding:"required"`
+}
+
+// CompareStats summarizes a structure after the whole sequence has been applied
+type CompareStats struct {
+	Structure     string `json:"structure"`
+	Height        int    `json:"height"`
+	NodeCount     int    `json:"nodeCount"`
+	RotationCount int    `json:"rotationCount"`
+	StepCount     int    `json:"stepCount"`
+}
+
+// compareTree is the subset of tree behaviour needed for a comparison run
+type compareTree interface {
+	Insert(value int) datastructures.OperationResult
+	Search(value int) datastructures.OperationResult
+	Delete(value int) datastructures.OperationResult
+	Height() int
+	Size() int
+	SetIncludeSnapshots(include bool)
+	SetContext(ctx context.Context)
+}
+
+func newCompareTree(structure string) (compareTree, bool) {
+	switch structure {
+	case "rbtree":
+		return datastructures.NewRedBlackTree(), true
+	case "avltree":
+		return datastructures.NewAVLTree(), true
+	}
+	return nil, false
+}
+
+// HandleCompare applies the same operation sequence to fresh instances of
+// each requested structure and reports their final statistics. Sequences
+// are bounded like batches and the whole comparison shares one
+// OperationTimeout; a comparison cut short reports success false with the
+// canceled reason and the structures finished so far.
+func HandleCompare(c *gin.Context) {
+	var req CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, "Invalid request: "+err.Error())
+		return
+	}
+	if len(req.Operations) > MaxBatchOperations {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, fmt.Sprintf("A comparison may contain at most %d operations", MaxBatchOperations))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), OperationTimeout)
+	defer cancel()
+
+	results := make([]CompareStats, 0, len(req.Structures))
+	for _, structure := range req.Structures {
+		tree, ok := newCompareTree(structure)
+		if !ok {
+			respondError(c, http.StatusBadRequest, datastructures.CodeUnknownStructure, "Unknown structure: "+structure)
+			return
+		}
+		// Only counts are reported, so intermediate snapshots are wasted work
+		tree.SetIncludeSnapshots(false)
+		tree.SetContext(ctx)
+
+		stats := CompareStats{Structure: structure}
+		for _, op := range req.Operations {
+			if ctx.Err() != nil {
+				c.JSON(http.StatusOK, gin.H{
+					"success": false,
+					"reason":  datastructures.ReasonCanceled,
+					"results": results,
+				})
+				return
+			}
+			if err := checkRequiredParams(valueParam, op.Params); err != nil {
+				respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Invalid request: "+err.Error())
+				return
+			}
+			value := getIntParam(op.Params, "value", 0)
+
+			var result datastructures.OperationResult
+			switch op.Operation {
+			case "insert":
+				result = tree.Insert(value)
+			case "search":
+				result = tree.Search(value)
+			case "delete":
+				result = tree.Delete(value)
+			default:
+				respondError(c, http.StatusBadRequest, datastructures.CodeUnknownOperation, "Unknown operation: "+op.Operation)
+				return
+			}
+
+			stats.StepCount += len(result.Steps)
+			stats.RotationCount += result.RotationCount
+		}
+		stats.Height = tree.Height()
+		stats.NodeCount = tree.Size()
+		results = append(results, stats)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"results": results,
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCompareSortedInsertsKeepAVLShallow(t *testing.T) {
+	const n = 31
+	ops := make([]CompareOperation, 0, n)
+	for v := 1; v <= n; v++ {
+		ops = append(ops, CompareOperation{Operation: "insert", Params: map[string]interface{}{"value": v}})
+	}
+
+	w := serveJSON(t, http.MethodPost, HandleCompare, CompareRequest{
+		Structures: []string{"avltree", "rbtree"},
+		Operations: ops,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Results []CompareStats `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	// A naive BST degenerates into a list of height n on sorted input
+	for _, stats := range resp.Results {
+		if stats.NodeCount != n {
+			t.Errorf("%s has %d nodes, want %d", stats.Structure, stats.NodeCount, n)
+		}
+		if stats.Height >= n {
+			t.Errorf("%s height %d, want less than the naive BST height %d", stats.Structure, stats.Height, n)
+		}
+	}
+	if avl := resp.Results[0]; avl.Height != 5 || avl.RotationCount == 0 {
+		t.Errorf("avltree height %d with %d rotations, want a perfect tree of height 5", avl.Height, avl.RotationCount)
+	}
+}
+
+func TestCompareRejectsUnknownStructure(t *testing.T) {
+	w := serveJSON(t, http.MethodPost, HandleCompare, CompareRequest{
+		Structures: []string{"avltree", "skiplist"},
+		Operations: []CompareOperation{{Operation: "insert", Params: map[string]interface{}{"value": 1}}},
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["code"] != "UNKNOWN_STRUCTURE" {
+		t.Errorf("code %v, want UNKNOWN_STRUCTURE", body["code"])
+	}
+}
+
+func TestCompareIsBoundedLikeBatches(t *testing.T) {
+	ops := make([]CompareOperation, MaxBatchOperations+1)
+	for i := range ops {
+		ops[i] = CompareOperation{Operation: "insert", Params: map[string]interface{}{"value": i}}
+	}
+	w := serveJSON(t, http.MethodPost, HandleCompare, CompareRequest{Structures: []string{"rbtree"}, Operations: ops})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("oversize sequence: status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	saved := OperationTimeout
+	OperationTimeout = 0
+	defer func() { OperationTimeout = saved }()
+	w = serveJSON(t, http.MethodPost, HandleCompare, CompareRequest{Structures: []string{"rbtree"}, Operations: ops[:1]})
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["success"] != false || body["reason"] != "canceled" {
+		t.Errorf("expired budget: success %v, reason %v", body["success"], body["reason"])
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+
+	"gin/datastructures"
+)
+
+// sessionKey is the context key of the session an operation runs for
+type sessionKey struct{}
+
+// withSession tags ctx with the session of the request being served
+func withSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// contextSession returns the session ctx was tagged with
+func contextSession(ctx context.Context) string {
+	if session, ok := ctx.Value(sessionKey{}).(string); ok {
+		return session
+	}
+	return defaultSession
+}
+
+// inorderCursors holds, by session and structure, the last value the next
+// operation returned, so repeated calls walk the sorted order one key at a
+// time. Guarded by stateMu.
+var inorderCursors = make(map[string]map[string]int)
+
+// nextInOrder runs next from the session's cursor on structure and moves
+// the cursor to the value it returns. An explicit value param continues
+// from that value instead, and reset starts over from the minimum. The
+// cursor is dropped at the end of the sequence, so the call after done
+// starts over.
+func nextInOrder(req OperationRequest, structure string, next func(after *int) datastructures.OperationResult) datastructures.OperationResult {
+	cursors, ok := inorderCursors[req.session]
+	if !ok {
+		if len(inorderCursors) >= maxStepLogs {
+			// Losing a cursor only restarts a walk, so any session can go
+			for session := range inorderCursors {
+				delete(inorderCursors, session)
+				break
+			}
+		}
+		cursors = make(map[string]int)
+		inorderCursors[req.session] = cursors
+	}
+
+	var after *int
+	if value, ok := cursors[structure]; ok && !getBoolParam(req.Params, "reset", false) {
+		after = &value
+	}
+	if _, ok := req.Params["value"]; ok {
+		value := getIntParam(req.Params, "value", 0)
+		after = &value
+	}
+
+	result := next(after)
+	if result.Node != nil && !result.Done {
+		cursors[structure] = result.Node.Value
+	} else {
+		delete(cursors, structure)
+	}
+	return result
+}
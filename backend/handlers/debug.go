@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPprof mounts the net/http/pprof profiling handlers under
+// /debug/pprof of group. It is only called when profiling is enabled, as
+// the profiles expose internals and a CPU profile blocks for its duration.
+func RegisterPprof(group *gin.RouterGroup) {
+	debug := group.Group("/debug/pprof")
+	debug.GET("/", gin.WrapF(pprof.Index))
+	debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	debug.GET("/profile", gin.WrapF(pprof.Profile))
+	debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+	debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+	debug.GET("/trace", gin.WrapF(pprof.Trace))
+	// pprof.Index only serves named profiles under the root /debug/pprof/
+	// path, so heap, goroutine, allocs and the rest get their own route
+	debug.GET("/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
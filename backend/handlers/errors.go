@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError writes a failed API response carrying a machine-readable code
+// next to the human-readable message
+func respondError(c *gin.Context, status int, code datastructures.ErrorCode, message string) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"code":    code,
+		"error":   message,
+	})
+}
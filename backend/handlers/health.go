@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the service version. Override it at build time with
+// -ldflags "-X gin/handlers.Version=v1.2.3".
+var Version = "dev"
+
+// startTime is when the process started serving
+var startTime = time.Now()
+
+// HandleHealth reports service status, version, uptime, goroutine count,
+// current heap allocation and whether any benchmark is running
+func HandleHealth(c *gin.Context) {
+	uptime := time.Since(startTime)
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	running := benchmarksRunning()
+	c.JSON(http.StatusOK, gin.H{
+		"status":        "ok",
+		"service":       "StructTrace Engine API",
+		"version":       Version,
+		"uptime":        uptime.Round(time.Second).String(),
+		"uptimeSeconds": int64(uptime.Seconds()),
+		"goroutines":    runtime.NumGoroutine(),
+		"heapAlloc":     mem.HeapAlloc,
+		"benchmarking":  running > 0,
+		"benchmarks":    running,
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"gin/benchmark"
+)
+
+func healthStatus(t *testing.T) map[string]interface{} {
+	t.Helper()
+	w := serveJSON(t, http.MethodGet, HandleHealth, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestHealthReportsHeapAndBenchmarks(t *testing.T) {
+	body := healthStatus(t)
+	if body["status"] != "ok" || body["service"] == nil {
+		t.Errorf("basic fields changed: %v", body)
+	}
+	if heap, _ := body["heapAlloc"].(float64); heap <= 0 {
+		t.Errorf("heapAlloc = %v", body["heapAlloc"])
+	}
+	if body["benchmarking"] != false {
+		t.Errorf("benchmarking = %v while idle", body["benchmarking"])
+	}
+
+	// Hold a benchmark mid-run by blocking its first progress callback
+	runner, ok := acquireRunner("health-test")
+	if !ok {
+		t.Fatal("session already has a runner")
+	}
+	reported := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.RunBenchmark(benchmark.BenchmarkConfig{
+			DataSize:   1000,
+			Structures: []string{"hashmap"},
+			Operation:  "insert",
+		}, func(benchmark.BenchmarkResult) {
+			once.Do(func() {
+				close(reported)
+				<-release
+			})
+		})
+	}()
+	<-reported
+
+	body = healthStatus(t)
+	close(release)
+	<-done
+	releaseRunner("health-test", runner)
+
+	if body["benchmarking"] != true || body["benchmarks"] != float64(1) {
+		t.Errorf("benchmarking = %v, benchmarks = %v during a run", body["benchmarking"], body["benchmarks"])
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"gin/benchmark"
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBenchmarkRuns bounds how many finished runs are kept for summaries
+const maxBenchmarkRuns = 50
+
+// benchmarkRun collects the final results of one benchmark run
+type benchmarkRun struct {
+	mu      sync.Mutex
+	results []benchmark.BenchmarkResult
+	done    bool
+}
+
+func (r *benchmarkRun) record(result benchmark.BenchmarkResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+}
+
+func (r *benchmarkRun) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done = true
+}
+
+var (
+	runCounter uint64
+
+	// benchmarkRuns caches recent runs by ID; runOrder evicts the oldest
+	benchmarkRuns = make(map[string]*benchmarkRun)
+	runOrder      []string
+	runsMutex     sync.Mutex
+)
+
+// newBenchmarkRun registers a run and returns its ID
+func newBenchmarkRun() (string, *benchmarkRun) {
+	id := fmt.Sprintf("run-%d", atomic.AddUint64(&runCounter, 1))
+	run := &benchmarkRun{}
+
+	runsMutex.Lock()
+	defer runsMutex.Unlock()
+	benchmarkRuns[id] = run
+	runOrder = append(runOrder, id)
+	if len(runOrder) > maxBenchmarkRuns {
+		delete(benchmarkRuns, runOrder[0])
+		runOrder = runOrder[1:]
+	}
+	return id, run
+}
+
+// finishedRunResults returns the results of the run named by the runId
+// path parameter, responding with an error if it is unknown or still
+// running
+func finishedRunResults(c *gin.Context) ([]benchmark.BenchmarkResult, bool) {
+	runsMutex.Lock()
+	run, ok := benchmarkRuns[c.Param("runId")]
+	runsMutex.Unlock()
+	if !ok {
+		respondError(c, http.StatusNotFound, datastructures.CodeNotFound, "Unknown benchmark run: "+c.Param("runId"))
+		return nil, false
+	}
+
+	run.mu.Lock()
+	done := run.done
+	results := append([]benchmark.BenchmarkResult(nil), run.results...)
+	run.mu.Unlock()
+	if !done {
+		respondError(c, http.StatusConflict, datastructures.CodeNotFinished, "Benchmark run has not finished yet")
+		return nil, false
+	}
+	return results, true
+}
+
+// HandleBenchmarkSummary ranks the structures of a completed run by ops/sec
+func HandleBenchmarkSummary(c *gin.Context) {
+	results, ok := finishedRunResults(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"runId":   c.Param("runId"),
+		"summary": benchmark.Summarize(results),
+	})
+}
+
+// HandleBenchmarkExport downloads the results of a completed run as CSV,
+// or as JSON with format=json
+func HandleBenchmarkExport(c *gin.Context) {
+	results, ok := finishedRunResults(c)
+	if !ok {
+		return
+	}
+
+	runID := c.Param("runId")
+	switch format := c.DefaultQuery("format", "csv"); format {
+	case "csv":
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, runID))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := benchmark.WriteCSV(c.Writer, results); err != nil {
+			c.Error(err)
+		}
+	case "json":
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, runID))
+		c.JSON(http.StatusOK, gin.H{
+			"runId":   runID,
+			"results": results,
+		})
+	default:
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Unknown export format: "+format)
+	}
+}
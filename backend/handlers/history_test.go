@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gin/benchmark"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordRun runs a small benchmark of structures into a new finished run
+// and returns its ID and the final ops/sec reported for each structure
+func recordRun(t *testing.T, structures ...string) (string, map[string]float64) {
+	t.Helper()
+	id, run := newBenchmarkRun()
+	opsPerSec := make(map[string]float64)
+	benchmark.NewRunner().RunBenchmark(benchmark.BenchmarkConfig{
+		DataSize:   2000,
+		Structures: structures,
+		Operation:  "insert",
+		Seed:       1,
+		Sequential: true,
+	}, func(result benchmark.BenchmarkResult) {
+		// Like HandleBenchmark, keep only the final result of each structure
+		if result.Completed {
+			run.record(result)
+			opsPerSec[result.Structure] = result.OpsPerSec
+		}
+	})
+	run.finish()
+	if len(opsPerSec) != len(structures) {
+		t.Fatalf("%d of %d structures completed", len(opsPerSec), len(structures))
+	}
+	return id, opsPerSec
+}
+
+// getRun requests path with the runId parameter bound to id
+func getRun(handler gin.HandlerFunc, path, id string) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.GET(path+"/:runId", handler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"/"+id, nil))
+	return w
+}
+
+func TestBenchmarkSummaryRanksByOpsPerSec(t *testing.T) {
+	id, opsPerSec := recordRun(t, "hashmap", "rbtree", "avltree", "bst")
+
+	w := getRun(HandleBenchmarkSummary, "/summary", id)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Summary benchmark.Summary `json:"summary"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	ranking := resp.Summary.Ranking
+	if len(ranking) != len(opsPerSec) {
+		t.Fatalf("%d ranked structures, want %d", len(ranking), len(opsPerSec))
+	}
+	for i, row := range ranking {
+		if row.Rank != i+1 {
+			t.Errorf("row %d has rank %d", i, row.Rank)
+		}
+		if row.OpsPerSec != opsPerSec[row.Structure] {
+			t.Errorf("%s ranked with %v ops/sec, reported %v", row.Structure, row.OpsPerSec, opsPerSec[row.Structure])
+		}
+		if i > 0 && row.OpsPerSec > ranking[i-1].OpsPerSec {
+			t.Errorf("%s ranked below slower %s", row.Structure, ranking[i-1].Structure)
+		}
+	}
+	if last := ranking[len(ranking)-1]; last.Speedup != 1 {
+		t.Errorf("slowest structure has speedup %v, want 1", last.Speedup)
+	}
+}
+
+func TestBenchmarkSummaryUnknownAndUnfinishedRuns(t *testing.T) {
+	if w := getRun(HandleBenchmarkSummary, "/summary", "run-missing"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown run: status %d", w.Code)
+	}
+	id, _ := newBenchmarkRun()
+	if w := getRun(HandleBenchmarkSummary, "/summary", id); w.Code != http.StatusConflict {
+		t.Errorf("unfinished run: status %d", w.Code)
+	}
+}
+
+func TestBenchmarkExportWritesOneRowPerStructure(t *testing.T) {
+	id, opsPerSec := recordRun(t, "rbtree", "avltree", "bst")
+
+	w := getRun(HandleBenchmarkExport, "/export", id)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("content type %q", ct)
+	}
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1+len(opsPerSec) {
+		t.Fatalf("%d rows, want a header and %d results", len(rows), len(opsPerSec))
+	}
+	if !slices.Equal(rows[0], benchmark.CSVHeader) {
+		t.Errorf("header %v", rows[0])
+	}
+	for _, row := range rows[1:] {
+		want, ok := opsPerSec[row[0]]
+		if !ok {
+			t.Errorf("unexpected structure %q", row[0])
+			continue
+		}
+		if got, err := strconv.ParseFloat(row[4], 64); err != nil || got != want {
+			t.Errorf("%s opsPerSec %q, want %v", row[0], row[4], want)
+		}
+		if row[1] != "insert" || row[2] != "2000" {
+			t.Errorf("%s operation %q dataSize %q", row[0], row[1], row[2])
+		}
+		delete(opsPerSec, row[0])
+	}
+
+	if w := getRun(HandleBenchmarkExport, "/export", id+"?format=xml"); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: status %d, want 400", w.Code)
+	}
+}
@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"gin/datastructures"
 
@@ -13,122 +18,532 @@ type OperationRequest struct {
 	Structure string                 `json:"structure" binding:"required"`
 	Operation string                 `json:"operation" binding:"required"`
 	Params    map[string]interface{} `json:"params"`
+
+	// session is the session the operation runs for, filled in by Execute
+	session string
 }
 
-// Stateful data structures for persistence
-var (
-	rbTree  = datastructures.NewRedBlackTree()
-	avlTree = datastructures.NewAVLTree()
-	graph   = datastructures.CreateSampleGraph()
-)
+// stateMu serializes access to the session structures
+var stateMu sync.Mutex
+
+// OperationTimeout bounds how long a single operation may run before it is
+// canceled
+var OperationTimeout = 10 * time.Second
+
+// CheckSteps enables the debug check that every node a step references is
+// present in that step's snapshot. Violations are logged.
+var CheckSteps = false
 
 // HandleOperation handles data structure operation requests
 func HandleOperation(c *gin.Context) {
 	var req OperationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request: " + err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := validateParams(req); err != nil {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Invalid request: "+err.Error())
+		return
+	}
+
+	var stepTypes []datastructures.StepType
+	if err := decodeParam(req.Params, "stepTypes", &stepTypes); err != nil {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Invalid request: param \"stepTypes\": "+err.Error())
 		return
 	}
 
-	var result datastructures.OperationResult
-
-	switch req.Structure {
-	case "rbtree":
-		result = handleRBTreeOperation(req)
-	case "avltree":
-		result = handleAVLTreeOperation(req)
-	case "graph":
-		result = handleGraphOperation(req)
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Unknown structure: " + req.Structure,
-		})
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	// Long-running operations stop when the client goes away or the
+	// operation exceeds its time budget
+	ctx, cancel := context.WithTimeout(withSession(c.Request.Context(), requestSession(c)), OperationTimeout)
+	defer cancel()
+
+	// An animation script needs the state the operation starts from
+	exportScript := c.Query("export") == "script"
+	var initial datastructures.OperationResult
+	if exportScript {
+		initial = captureInitialState(ctx, req)
+	}
+
+	result, ok := dispatchOperation(ctx, req)
+	if !ok {
+		respondError(c, http.StatusBadRequest, datastructures.CodeUnknownStructure, "Unknown structure: "+req.Structure)
 		return
 	}
 
+	if mutatingOperations[req.Operation] {
+		persistState(requestSession(c))
+	}
+
+	if CheckSteps {
+		for _, err := range datastructures.CheckStepReferences(result.Steps) {
+			log.Printf("%s %s: %v", req.Structure, req.Operation, err)
+		}
+	}
+
+	// Scripts always carry the complete log, so filtering and paging do not
+	// apply to them
+	if exportScript {
+		cacheSteps(requestSession(c), result.Steps)
+		c.JSON(http.StatusOK, newAnimationScript(req, initial, result))
+		return
+	}
+
+	// Filtering happens after the operation so the kept steps carry the
+	// same snapshots and indices as in the full log
+	if len(stepTypes) > 0 {
+		result.Steps = datastructures.FilterSteps(result.Steps, stepTypes)
+	}
+
+	// The whole log is cached so later pages can be fetched from /steps and
+	// single steps by token
+	result.StepToken = cacheSteps(requestSession(c), result.Steps)
+	paginateResult(&result, req.Params)
+
 	c.JSON(http.StatusOK, result)
 }
 
-func handleRBTreeOperation(req OperationRequest) datastructures.OperationResult {
-	switch req.Operation {
-	case "insert":
-		value := getIntParam(req.Params, "value", 0)
-		return rbTree.Insert(value)
-	case "search":
-		value := getIntParam(req.Params, "value", 0)
-		return rbTree.Search(value)
-	case "delete":
-		value := getIntParam(req.Params, "value", 0)
-		return rbTree.Delete(value)
-	case "reset":
-		rbTree = datastructures.NewRedBlackTree()
-		return datastructures.OperationResult{
-			Success: true,
-			Message: "Red-Black Tree 已重置",
-			Steps:   []datastructures.Step{},
-		}
-	default:
-		return datastructures.OperationResult{
-			Success: false,
-			Message: "Unknown operation: " + req.Operation,
-		}
+// dispatchOperation runs req against its registered structure, returning
+// false if the structure is unknown. Callers must hold stateMu.
+func dispatchOperation(ctx context.Context, req OperationRequest) (datastructures.OperationResult, bool) {
+	s, ok := structures.Lookup(req.Structure)
+	if !ok {
+		return datastructures.OperationResult{}, false
 	}
+	return s.Execute(ctx, req.Operation, req.Params), true
 }
 
-func handleAVLTreeOperation(req OperationRequest) datastructures.OperationResult {
-	switch req.Operation {
-	case "insert":
-		value := getIntParam(req.Params, "value", 0)
-		return avlTree.Insert(value)
-	case "search":
-		value := getIntParam(req.Params, "value", 0)
-		return avlTree.Search(value)
-	case "delete":
-		value := getIntParam(req.Params, "value", 0)
-		return avlTree.Delete(value)
-	case "reset":
-		avlTree = datastructures.NewAVLTree()
-		return datastructures.OperationResult{
-			Success: true,
-			Message: "AVL Tree 已重置",
-			Steps:   []datastructures.Step{},
-		}
-	default:
-		return datastructures.OperationResult{
-			Success: false,
-			Message: "Unknown operation: " + req.Operation,
-		}
+// resetResult is the result of a reset operation
+func resetResult(req OperationRequest, key string) datastructures.OperationResult {
+	return datastructures.OperationResult{
+		Success: true,
+		Message: datastructures.Localize(requestLocale(req), key),
+		Steps:   []datastructures.Step{},
 	}
 }
 
-func handleGraphOperation(req OperationRequest) datastructures.OperationResult {
-	switch req.Operation {
-	case "insert":
-		value := getIntParam(req.Params, "value", 0)
-		return graph.Insert(value)
-	case "shortest_path":
-		start := getStringParam(req.Params, "start", "A")
-		end := getStringParam(req.Params, "end", "F")
-		return graph.Dijkstra(start, end)
-	case "reset":
-		graph = datastructures.CreateSampleGraph()
-		return datastructures.OperationResult{
-			Success: true,
-			Message: "Graph 已重置",
-			Steps:   []datastructures.Step{},
-		}
-	default:
-		return datastructures.OperationResult{
-			Success: false,
-			Message: "Unknown operation: " + req.Operation,
-		}
+// valuesResult is the result of a values operation. It records no steps.
+func valuesResult(req OperationRequest, values []int) datastructures.OperationResult {
+	return datastructures.OperationResult{
+		Success: true,
+		Message: datastructures.Localize(requestLocale(req), "tree.values", len(values)),
+		Steps:   []datastructures.Step{},
+		Values:  values,
+	}
+}
+
+func rbTreeOperations() operationTable {
+	return operationTable{
+		kind: datastructures.KindRBTree,
+		prepare: func(ctx context.Context, state *sessionState, req OperationRequest) {
+			state.rbTree.SetContext(ctx)
+			state.rbTree.SetIncludeSnapshots(getBoolParam(req.Params, "includeSnapshots", true))
+			state.rbTree.SetLayout(getFloatParam(req.Params, "canvasWidth", 0), getFloatParam(req.Params, "levelHeight", 0))
+			state.rbTree.SetLocale(requestLocale(req))
+		},
+		operations: map[string]operationFunc{
+			"insert": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.Insert(getIntParam(req.Params, "value", 0))
+			},
+			"search": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.Search(getIntParam(req.Params, "value", 0))
+			},
+			"delete": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.Delete(getIntParam(req.Params, "value", 0))
+			},
+			"state": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.State()
+			},
+			"values": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return valuesResult(req, state.rbTree.Values())
+			},
+			"levelorder": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.LevelOrder()
+			},
+			"morris": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.MorrisInorder()
+			},
+			"morris_inorder": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.MorrisInorder()
+			},
+			"balance_info": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.BalanceInfo()
+			},
+			"check_bst": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.CheckBST()
+			},
+			"export_nested": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.ExportNested()
+			},
+			"search_all": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.SearchAll(getIntParam(req.Params, "value", 0))
+			},
+			"next": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return nextInOrder(req, "rbtree", state.rbTree.Next)
+			},
+			"rebalance": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.Rebalance()
+			},
+			"black_height": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				if _, ok := req.Params["value"]; !ok {
+					return state.rbTree.BlackHeight(nil)
+				}
+				value := getIntParam(req.Params, "value", 0)
+				return state.rbTree.BlackHeight(&value)
+			},
+			"diameter": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.Diameter()
+			},
+			"depth": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.Depth(getIntParam(req.Params, "value", 0))
+			},
+			"get_node": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.GetNode(getIntParam(req.Params, "id", 0))
+			},
+			"path_to": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.PathTo(getIntParam(req.Params, "value", 0))
+			},
+			"lca": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.LCA(getIntParam(req.Params, "a", 0), getIntParam(req.Params, "b", 0))
+			},
+			"bulk_delete": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var values []int
+				if err := decodeParam(req.Params, "values", &values); err != nil {
+					return invalidParamResult("values", err)
+				}
+				return state.rbTree.DeleteMany(values)
+			},
+			"insert_raw": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var parentID *int
+				if _, ok := req.Params["parentId"]; ok {
+					id := getIntParam(req.Params, "parentId", 0)
+					parentID = &id
+				}
+				color := datastructures.NodeColor(getStringParam(req.Params, "color", string(datastructures.Red)))
+				return state.rbTree.InsertRaw(getIntParam(req.Params, "value", 0), color, parentID, getStringParam(req.Params, "side", ""))
+			},
+			"trigger_fixup": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.rbTree.TriggerFixupAt(getIntParam(req.Params, "id", 0))
+			},
+			"reset": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				state.rbTree = datastructures.NewRedBlackTree()
+				return resetResult(req, "reset.rbtree")
+			},
+		},
+	}
+}
+
+func avlTreeOperations() operationTable {
+	return operationTable{
+		kind: datastructures.KindAVLTree,
+		prepare: func(ctx context.Context, state *sessionState, req OperationRequest) {
+			state.avlTree.SetContext(ctx)
+			state.avlTree.SetIncludeSnapshots(getBoolParam(req.Params, "includeSnapshots", true))
+			state.avlTree.SetLayout(getFloatParam(req.Params, "canvasWidth", 0), getFloatParam(req.Params, "levelHeight", 0))
+			state.avlTree.SetLocale(requestLocale(req))
+		},
+		operations: map[string]operationFunc{
+			"insert": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				value := getIntParam(req.Params, "value", 0)
+				if getBoolParam(req.Params, "iterative", false) {
+					return state.avlTree.InsertIterative(value)
+				}
+				return state.avlTree.Insert(value)
+			},
+			"search": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.Search(getIntParam(req.Params, "value", 0))
+			},
+			"delete": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.Delete(getIntParam(req.Params, "value", 0))
+			},
+			"state": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.State()
+			},
+			"values": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return valuesResult(req, state.avlTree.Values())
+			},
+			"levelorder": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.LevelOrder()
+			},
+			"morris": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.MorrisInorder()
+			},
+			"morris_inorder": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.MorrisInorder()
+			},
+			"balance_info": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.BalanceInfo()
+			},
+			"check_bst": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.CheckBST()
+			},
+			"export_nested": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.ExportNested()
+			},
+			"search_all": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.SearchAll(getIntParam(req.Params, "value", 0))
+			},
+			"next": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return nextInOrder(req, "avltree", state.avlTree.Next)
+			},
+			"diameter": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.Diameter()
+			},
+			"depth": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.Depth(getIntParam(req.Params, "value", 0))
+			},
+			"get_node": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.GetNode(getIntParam(req.Params, "id", 0))
+			},
+			"build_balanced": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var values []int
+				if err := decodeParam(req.Params, "values", &values); err != nil {
+					return invalidParamResult("values", err)
+				}
+				return state.avlTree.BuildBalanced(values)
+			},
+			"path_to": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.PathTo(getIntParam(req.Params, "value", 0))
+			},
+			"lca": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.avlTree.LCA(getIntParam(req.Params, "a", 0), getIntParam(req.Params, "b", 0))
+			},
+			"reset": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				state.avlTree = datastructures.NewAVLTree()
+				return resetResult(req, "reset.avltree")
+			},
+		},
+	}
+}
+
+func tree234Operations() operationTable {
+	return operationTable{
+		kind: datastructures.KindTree234,
+		prepare: func(ctx context.Context, state *sessionState, req OperationRequest) {
+			state.tree234.SetIncludeSnapshots(getBoolParam(req.Params, "includeSnapshots", true))
+			state.tree234.SetLayout(getFloatParam(req.Params, "canvasWidth", 0), getFloatParam(req.Params, "levelHeight", 0))
+			state.tree234.SetLocale(requestLocale(req))
+		},
+		operations: map[string]operationFunc{
+			"insert": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.tree234.Insert(getIntParam(req.Params, "value", 0))
+			},
+			"search": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.tree234.Search(getIntParam(req.Params, "value", 0))
+			},
+			"state": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.tree234.State()
+			},
+			"values": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return valuesResult(req, state.tree234.Values())
+			},
+			"get_node": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.tree234.GetNode(getIntParam(req.Params, "id", 0))
+			},
+			"reset": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				state.tree234 = datastructures.NewTree234()
+				return resetResult(req, "reset.tree234")
+			},
+		},
+	}
+}
+
+func heapOperations() operationTable {
+	return operationTable{
+		kind: datastructures.KindHeap,
+		prepare: func(ctx context.Context, state *sessionState, req OperationRequest) {
+			state.heap.SetIncludeSnapshots(getBoolParam(req.Params, "includeSnapshots", true))
+			state.heap.SetLayout(getFloatParam(req.Params, "canvasWidth", 0), getFloatParam(req.Params, "levelHeight", 0))
+			state.heap.SetLocale(requestLocale(req))
+			state.heap.SetContext(ctx)
+		},
+		operations: map[string]operationFunc{
+			"insert": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.heap.Insert(getIntParam(req.Params, "value", 0))
+			},
+			"extract_min": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.heap.ExtractMin()
+			},
+			"state": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.heap.State()
+			},
+			"heapsort": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var values []int
+				if err := decodeParam(req.Params, "values", &values); err != nil {
+					return invalidParamResult("values", err)
+				}
+				return state.heap.HeapSort(values)
+			},
+			"reset": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				state.heap = datastructures.NewBinaryHeap()
+				return resetResult(req, "reset.heap")
+			},
+		},
+	}
+}
+
+func arrayOperations() operationTable {
+	return operationTable{
+		kind: datastructures.KindArray,
+		prepare: func(ctx context.Context, state *sessionState, req OperationRequest) {
+			state.sorter.SetIncludeSnapshots(getBoolParam(req.Params, "includeSnapshots", true))
+			state.sorter.SetLocale(requestLocale(req))
+		},
+		operations: map[string]operationFunc{
+			"quicksort": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var values []int
+				if err := decodeParam(req.Params, "values", &values); err != nil {
+					return invalidParamResult("values", err)
+				}
+				return state.sorter.QuickSort(values)
+			},
+			"mergesort": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var values []int
+				if err := decodeParam(req.Params, "values", &values); err != nil {
+					return invalidParamResult("values", err)
+				}
+				return state.sorter.MergeSort(values)
+			},
+		},
+	}
+}
+
+func graphOperations() operationTable {
+	return operationTable{
+		kind: datastructures.KindGraph,
+		prepare: func(ctx context.Context, state *sessionState, req OperationRequest) {
+			state.graph.SetContext(ctx)
+			state.graph.SetIncludeSnapshots(getBoolParam(req.Params, "includeSnapshots", true))
+			state.graph.SetLocale(requestLocale(req))
+		},
+		finish: func(state *sessionState, req OperationRequest, result *datastructures.OperationResult) {
+			if getBoolParam(req.Params, "includeMatrix", false) {
+				result.AdjacencyMatrix = state.graph.AdjacencyMatrix()
+			}
+		},
+		operations: map[string]operationFunc{
+			"insert": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.Insert(getIntParam(req.Params, "value", 0))
+			},
+			"build_graph": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var nodes []datastructures.GraphNodeInput
+				var edges []datastructures.GraphEdgeInput
+				if err := decodeParam(req.Params, "nodes", &nodes); err != nil {
+					return invalidParamResult("nodes", err)
+				}
+				if err := decodeParam(req.Params, "edges", &edges); err != nil {
+					return invalidParamResult("edges", err)
+				}
+				return state.graph.BuildGraph(nodes, edges, getBoolParam(req.Params, "allowSelfLoops", false), getBoolParam(req.Params, "allowParallelEdges", false), getBoolParam(req.Params, "directed", false))
+			},
+			"generate_graph": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				n := getIntParam(req.Params, "nodes", 0)
+				m := getIntParam(req.Params, "edges", 2*n)
+				seed := time.Now().UnixNano()
+				if _, ok := req.Params["seed"]; ok {
+					seed = int64(getIntParam(req.Params, "seed", 0))
+				}
+				return state.graph.GenerateGraph(n, m, seed)
+			},
+			"state": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.State()
+			},
+			"export": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				data, err := state.graph.ExportAdjacency()
+				if err != nil {
+					return invalidParamResult("document", err)
+				}
+				return datastructures.OperationResult{
+					Success:  true,
+					Message:  datastructures.Localize(requestLocale(req), "graph.export.success"),
+					Steps:    []datastructures.Step{},
+					Document: data,
+				}
+			},
+			"import": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var document json.RawMessage
+				if err := decodeParam(req.Params, "document", &document); err != nil {
+					return invalidParamResult("document", err)
+				}
+				if err := state.graph.ImportAdjacency(document); err != nil {
+					return invalidParamResult("document", err)
+				}
+				result := state.graph.State()
+				result.Message = datastructures.Localize(requestLocale(req), "graph.import.success", len(state.graph.Nodes), len(state.graph.Export().Edges))
+				return result
+			},
+			"graph_info": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.Info()
+			},
+			"shortest_path": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				start := getStringParam(req.Params, "start", "A")
+				end := getStringParam(req.Params, "end", "F")
+				return state.graph.Dijkstra(start, end)
+			},
+			"shortest_path_nodecost": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				start := getStringParam(req.Params, "start", "A")
+				end := getStringParam(req.Params, "end", "F")
+				return state.graph.DijkstraNodeCost(start, end)
+			},
+			"compare_paths": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				start := getStringParam(req.Params, "start", "A")
+				end := getStringParam(req.Params, "end", "F")
+				return state.graph.ComparePaths(start, end)
+			},
+			"waypoints": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				var nodes []string
+				if err := decodeParam(req.Params, "nodes", &nodes); err != nil {
+					return invalidParamResult("nodes", err)
+				}
+				return state.graph.PathThroughWaypoints(nodes)
+			},
+			"reroute": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				start := getStringParam(req.Params, "start", "A")
+				end := getStringParam(req.Params, "end", "F")
+				return state.graph.Reroute(start, end, getStringParam(req.Params, "from", ""), getStringParam(req.Params, "to", ""))
+			},
+			"sssp": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.DijkstraAll(getStringParam(req.Params, "start", "A"))
+			},
+			"k_shortest": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				start := getStringParam(req.Params, "start", "A")
+				end := getStringParam(req.Params, "end", "F")
+				k := getIntParam(req.Params, "k", 3)
+				return state.graph.KShortestPaths(start, end, k)
+			},
+			"centrality": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.Centrality()
+			},
+			"longest_path": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.LongestPath()
+			},
+			"scc": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.SCC()
+			},
+			"bipartite": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				return state.graph.IsBipartite()
+			},
+			"max_flow": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				source := getStringParam(req.Params, "source", "A")
+				sink := getStringParam(req.Params, "sink", "F")
+				return state.graph.MaxFlow(source, sink)
+			},
+			"reset": func(state *sessionState, req OperationRequest) datastructures.OperationResult {
+				state.graph = datastructures.CreateSampleGraph()
+				return resetResult(req, "reset.graph")
+			},
+		},
 	}
 }
 
+// requestLocale returns the locale requested through the "lang" param
+func requestLocale(req OperationRequest) datastructures.Locale {
+	return datastructures.ParseLocale(getStringParam(req.Params, "lang", ""))
+}
+
 func getIntParam(params map[string]interface{}, key string, defaultVal int) int {
 	if val, ok := params[key]; ok {
 		switch v := val.(type) {
@@ -141,6 +556,15 @@ func getIntParam(params map[string]interface{}, key string, defaultVal int) int
 	return defaultVal
 }
 
+func getFloatParam(params map[string]interface{}, key string, defaultVal float64) float64 {
+	if val, ok := params[key]; ok {
+		if f, ok := val.(float64); ok {
+			return f
+		}
+	}
+	return defaultVal
+}
+
 func getStringParam(params map[string]interface{}, key string, defaultVal string) string {
 	if val, ok := params[key]; ok {
 		if str, ok := val.(string); ok {
@@ -150,11 +574,45 @@ func getStringParam(params map[string]interface{}, key string, defaultVal string
 	return defaultVal
 }
 
-// HandleReset resets all data structures
+func getBoolParam(params map[string]interface{}, key string, defaultVal bool) bool {
+	if val, ok := params[key]; ok {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return defaultVal
+}
+
+// decodeParam decodes a structured (object or array) param into dst by
+// round-tripping it through JSON. A missing param leaves dst untouched.
+func decodeParam(params map[string]interface{}, key string, dst interface{}) error {
+	val, ok := params[key]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func invalidParamResult(key string, err error) datastructures.OperationResult {
+	return datastructures.OperationResult{
+		Success: false,
+		Message: "Invalid param " + key + ": " + err.Error(),
+		Code:    datastructures.CodeInvalidParam,
+		Steps:   []datastructures.Step{},
+	}
+}
+
+// HandleReset resets all data structures of the caller's session
 func HandleReset(c *gin.Context) {
-	rbTree = datastructures.NewRedBlackTree()
-	avlTree = datastructures.NewAVLTree()
-	graph = datastructures.CreateSampleGraph()
+	session := requestSession(c)
+	stateMu.Lock()
+	resetSession(session)
+	persistState(session)
+	stateMu.Unlock()
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"gin/datastructures"
+)
+
+func TestOperationParamErrorsAreBadRequests(t *testing.T) {
+	freshSession()
+	cases := map[string]OperationRequest{
+		"missing value":       {Structure: "rbtree", Operation: "insert"},
+		"non-integer value":   {Structure: "rbtree", Operation: "insert", Params: map[string]interface{}{"value": "x"}},
+		"unknown step type":   {Structure: "rbtree", Operation: "state", Params: map[string]interface{}{"stepTypes": []string{"teleport"}}},
+		"malformed stepTypes": {Structure: "rbtree", Operation: "state", Params: map[string]interface{}{"stepTypes": 5}},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := serveJSON(t, http.MethodPost, HandleOperation, req)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want 400: %s", w.Code, w.Body)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["code"] != "INVALID_PARAM" {
+				t.Errorf("code %v, want INVALID_PARAM", body["code"])
+			}
+		})
+	}
+}
+
+func TestOperationFiltersStepTypes(t *testing.T) {
+	state := freshSession()
+	state.avlTree.Insert(30)
+	state.avlTree.Insert(10)
+
+	// 20 is the left-right case, rebalanced by two rotations
+	w := serveJSON(t, http.MethodPost, HandleOperation, OperationRequest{
+		Structure: "avltree",
+		Operation: "insert",
+		Params:    map[string]interface{}{"value": 20, "stepTypes": []string{"rotate_left", "rotate_right"}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var result datastructures.OperationResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Steps) != 2 {
+		t.Fatalf("%d steps, want the 2 rotations", len(result.Steps))
+	}
+	if result.Steps[0].Type != datastructures.StepRotateLeft || result.Steps[1].Type != datastructures.StepRotateRight {
+		t.Errorf("step types %s, %s", result.Steps[0].Type, result.Steps[1].Type)
+	}
+	if result.Steps[0].Index == 0 {
+		t.Error("filtered steps lost their original indices")
+	}
+}
+
+func TestValuesOperationReturnsSortedValues(t *testing.T) {
+	for _, structure := range []string{"rbtree", "avltree", "tree234"} {
+		t.Run(structure, func(t *testing.T) {
+			state := freshSession()
+			for _, v := range []int{42, 7, 19, 88, 3, 56} {
+				state.rbTree.Insert(v)
+				state.avlTree.Insert(v)
+				state.tree234.Insert(v)
+			}
+			w := serveJSON(t, http.MethodPost, HandleOperation, OperationRequest{Structure: structure, Operation: "values"})
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+			var result datastructures.OperationResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatal(err)
+			}
+			if want := []int{3, 7, 19, 42, 56, 88}; !slices.Equal(result.Values, want) {
+				t.Errorf("values %v, want %v", result.Values, want)
+			}
+			if len(result.Steps) != 0 {
+				t.Errorf("%d steps, want none", len(result.Steps))
+			}
+		})
+	}
+}
+
+func TestTimingParamAddsDurationHints(t *testing.T) {
+	freshSession()
+	for _, timing := range []bool{false, true} {
+		w := serveJSON(t, http.MethodPost, HandleOperation, OperationRequest{
+			Structure: "rbtree",
+			Operation: "insert",
+			Params:    map[string]interface{}{"value": 10, "timing": timing},
+		})
+		var result datastructures.OperationResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		for _, step := range result.Steps {
+			if (step.DurationHint > 0) != timing {
+				t.Errorf("timing %v: %s step has duration hint %d", timing, step.Type, step.DurationHint)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+)
+
+// paramKind is the JSON type a required param must have
+type paramKind int
+
+const (
+	intParam paramKind = iota
+	arrayParam
+	objectParam
+	stringParam
+)
+
+// valueParam is the single integer param of the value-based tree operations
+var valueParam = map[string]paramKind{"value": intParam}
+
+// idParam is the node ID param of get_node
+var idParam = map[string]paramKind{"id": intParam}
+
+// requiredParams lists, by structure and operation, the params an operation
+// cannot run without. Params not listed here are optional and fall back to
+// their defaults.
+var requiredParams = map[string]map[string]map[string]paramKind{
+	"rbtree": {
+		"insert":        valueParam,
+		"search":        valueParam,
+		"delete":        valueParam,
+		"path_to":       valueParam,
+		"depth":         valueParam,
+		"get_node":      idParam,
+		"bulk_delete":   {"values": arrayParam},
+		"insert_raw":    valueParam,
+		"trigger_fixup": idParam,
+		"lca":           {"a": intParam, "b": intParam},
+		"search_all":    valueParam,
+	},
+	"avltree": {
+		"insert":         valueParam,
+		"search":         valueParam,
+		"delete":         valueParam,
+		"path_to":        valueParam,
+		"depth":          valueParam,
+		"get_node":       idParam,
+		"build_balanced": {"values": arrayParam},
+		"lca":            {"a": intParam, "b": intParam},
+		"search_all":     valueParam,
+	},
+	"tree234": {
+		"insert":   valueParam,
+		"search":   valueParam,
+		"get_node": idParam,
+	},
+	"heap": {
+		"insert":   valueParam,
+		"heapsort": {"values": arrayParam},
+	},
+	"array": {
+		"quicksort": {"values": arrayParam},
+		"mergesort": {"values": arrayParam},
+	},
+	"graph": {
+		"insert":         valueParam,
+		"build_graph":    {"nodes": arrayParam},
+		"generate_graph": {"nodes": intParam},
+		"import":         {"document": objectParam},
+		"waypoints":      {"nodes": arrayParam},
+		"reroute":        {"from": stringParam, "to": stringParam},
+	},
+}
+
+// validateParams reports the first required param of req that is missing
+// or has the wrong type
+func validateParams(req OperationRequest) error {
+	return checkRequiredParams(requiredParams[req.Structure][req.Operation], req.Params)
+}
+
+func checkRequiredParams(required map[string]paramKind, params map[string]interface{}) error {
+	for key, kind := range required {
+		val, ok := params[key]
+		if !ok || val == nil {
+			return fmt.Errorf("missing required param %q", key)
+		}
+		switch kind {
+		case intParam:
+			if f, ok := val.(float64); !ok || f != math.Trunc(f) {
+				return fmt.Errorf("param %q must be an integer", key)
+			}
+		case arrayParam:
+			if _, ok := val.([]interface{}); !ok {
+				return fmt.Errorf("param %q must be an array", key)
+			}
+		case objectParam:
+			if _, ok := val.(map[string]interface{}); !ok {
+				return fmt.Errorf("param %q must be an object", key)
+			}
+		case stringParam:
+			if _, ok := val.(string); !ok {
+				return fmt.Errorf("param %q must be a string", key)
+			}
+		}
+	}
+	return nil
+}
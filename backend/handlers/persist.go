@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"log"
+
+	"gin/datastructures"
+	"gin/store"
+)
+
+// defaultSession is the session of requests that name none
+const defaultSession = "default"
+
+// sessionStore persists the sessions. Without persistence it is a NopStore
+// and sessions live only in memory, bounded by maxSessions.
+var sessionStore store.Store = store.NopStore{}
+
+// mutatingOperations lists the operations that change a structure and must
+// therefore be persisted afterwards
+var mutatingOperations = map[string]bool{
+	"insert":         true,
+	"extract_min":    true,
+	"quicksort":      true,
+	"mergesort":      true,
+	"delete":         true,
+	"bulk_delete":    true,
+	"reset":          true,
+	"build_graph":    true,
+	"generate_graph": true,
+	"build_balanced": true,
+	"import":         true,
+	"insert_raw":     true,
+	"trigger_fixup":  true,
+	"rebalance":      true,
+}
+
+// InitStore installs s as the session store. Sessions are restored from it
+// on first use; the default session is restored right away so a corrupt
+// snapshot is reported at startup.
+func InitStore(s store.Store) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	sessionStore = s
+	sessions = make(map[string]*sessionState)
+	sessionOrder = nil
+
+	snapshot, ok, err := s.Load(defaultSession)
+	if err != nil || !ok {
+		return err
+	}
+	return restoreState(sessionFor(defaultSession), snapshot)
+}
+
+// captureState exports all structures of a session. Callers must hold
+// stateMu.
+func captureState(state *sessionState) store.Snapshot {
+	rbExport := state.rbTree.Export()
+	avlExport := state.avlTree.Export()
+	tree234Export := state.tree234.Export()
+	heapExport := state.heap.Export()
+	arrayExport := state.sorter.Export()
+	graphExport := state.graph.Export()
+	return store.Snapshot{
+		RBTree:  &rbExport,
+		AVLTree: &avlExport,
+		Tree234: &tree234Export,
+		Heap:    &heapExport,
+		Array:   &arrayExport,
+		Graph:   &graphExport,
+	}
+}
+
+// restoreState replaces the structures of state present in snapshot.
+// Callers must hold stateMu.
+func restoreState(state *sessionState, snapshot store.Snapshot) error {
+	if snapshot.RBTree != nil {
+		t, err := datastructures.ImportRedBlackTree(*snapshot.RBTree)
+		if err != nil {
+			return err
+		}
+		state.rbTree = t
+	}
+	if snapshot.AVLTree != nil {
+		t, err := datastructures.ImportAVLTree(*snapshot.AVLTree)
+		if err != nil {
+			return err
+		}
+		state.avlTree = t
+	}
+	if snapshot.Tree234 != nil {
+		t, err := datastructures.ImportTree234(*snapshot.Tree234)
+		if err != nil {
+			return err
+		}
+		state.tree234 = t
+	}
+	if snapshot.Heap != nil {
+		h, err := datastructures.ImportBinaryHeap(*snapshot.Heap)
+		if err != nil {
+			return err
+		}
+		state.heap = h
+	}
+	if snapshot.Array != nil {
+		state.sorter = datastructures.ImportArraySorter(*snapshot.Array)
+	}
+	if snapshot.Graph != nil {
+		g, err := datastructures.ImportGraph(*snapshot.Graph)
+		if err != nil {
+			return err
+		}
+		state.graph = g
+	}
+	return nil
+}
+
+// persistState saves the structures of session to the session store.
+// Callers must hold stateMu. Failures are logged rather than failing the
+// request, since the in-memory state is still authoritative.
+func persistState(session string) {
+	if _, ok := sessionStore.(store.NopStore); ok {
+		return
+	}
+	if err := sessionStore.Save(session, captureState(sessionFor(session))); err != nil {
+		log.Printf("failed to persist session %s: %v", session, err)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+
+	"gin/datastructures"
+)
+
+// Structure is a data structure exposed through the operation API
+type Structure interface {
+	// Execute runs operation with params. Unknown operations produce an
+	// unsuccessful result rather than an error.
+	Execute(ctx context.Context, operation string, params map[string]interface{}) datastructures.OperationResult
+	// Operations lists the supported operations in alphabetical order
+	Operations() []string
+}
+
+// StructureRegistry maps structure names to their implementations
+type StructureRegistry struct {
+	structures map[string]Structure
+}
+
+// NewStructureRegistry creates an empty registry
+func NewStructureRegistry() *StructureRegistry {
+	return &StructureRegistry{structures: make(map[string]Structure)}
+}
+
+// Register adds s under name, replacing any structure registered before
+func (r *StructureRegistry) Register(name string, s Structure) {
+	r.structures[name] = s
+}
+
+// Lookup returns the structure registered under name
+func (r *StructureRegistry) Lookup(name string) (Structure, bool) {
+	s, ok := r.structures[name]
+	return s, ok
+}
+
+// Names returns the registered structure names in alphabetical order
+func (r *StructureRegistry) Names() []string {
+	names := make([]string, 0, len(r.structures))
+	for name := range r.structures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// operationFunc runs one operation of a structure of a session
+type operationFunc func(state *sessionState, req OperationRequest) datastructures.OperationResult
+
+// operationTable is a Structure backed by a map of operation functions.
+// Operations run against the structures of the session carried by their
+// context. prepare applies the per-request options before every operation
+// and finish, if set, amends every result. Results are tagged with kind.
+type operationTable struct {
+	kind       datastructures.StructureKind
+	prepare    func(ctx context.Context, state *sessionState, req OperationRequest)
+	finish     func(state *sessionState, req OperationRequest, result *datastructures.OperationResult)
+	operations map[string]operationFunc
+}
+
+// Execute implements Structure
+func (t operationTable) Execute(ctx context.Context, operation string, params map[string]interface{}) datastructures.OperationResult {
+	fn, ok := t.operations[operation]
+	if !ok {
+		return datastructures.OperationResult{
+			Success: false,
+			Message: "Unknown operation: " + operation,
+			Kind:    t.kind,
+			Code:    datastructures.CodeUnknownOperation,
+		}
+	}
+	req := OperationRequest{Operation: operation, Params: params, session: contextSession(ctx)}
+	state := sessionFor(req.session)
+	if t.prepare != nil {
+		t.prepare(ctx, state, req)
+	}
+	result := fn(state, req)
+	if t.finish != nil {
+		t.finish(state, req, &result)
+	}
+	result.Kind = t.kind
+	result.Finalize()
+	if getBoolParam(params, "timing", false) {
+		result.AnnotateDurations()
+	}
+	return result
+}
+
+// Operations implements Structure
+func (t operationTable) Operations() []string {
+	names := make([]string, 0, len(t.operations))
+	for name := range t.operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// structures is the registry the operation, batch and catalog endpoints
+// dispatch through
+var structures = NewStructureRegistry()
+
+func init() {
+	structures.Register("rbtree", rbTreeOperations())
+	structures.Register("avltree", avlTreeOperations())
+	structures.Register("tree234", tree234Operations())
+	structures.Register("heap", heapOperations())
+	structures.Register("array", arrayOperations())
+	structures.Register("graph", graphOperations())
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"gin/datastructures"
+)
+
+func TestRegistryDispatchesByName(t *testing.T) {
+	want := []string{"array", "avltree", "graph", "heap", "rbtree", "tree234"}
+	if got := structures.Names(); !slices.Equal(got, want) {
+		t.Fatalf("registered %v, want %v", got, want)
+	}
+	for _, name := range want {
+		s, ok := structures.Lookup(name)
+		if !ok {
+			t.Fatalf("%s not found", name)
+		}
+		if len(s.Operations()) == 0 {
+			t.Errorf("%s lists no operations", name)
+		}
+		// Unknown operations are answered by the structure itself
+		result := s.Execute(context.Background(), "teleport", nil)
+		if result.Success || result.Code != datastructures.CodeUnknownOperation {
+			t.Errorf("%s teleport: success %v, code %s", name, result.Success, result.Code)
+		}
+		if string(result.Kind) != name {
+			t.Errorf("%s result tagged %s", name, result.Kind)
+		}
+	}
+	if _, ok := structures.Lookup("splaytree"); ok {
+		t.Error("Lookup found an unregistered structure")
+	}
+}
+
+func TestOperationRejectsUnregisteredStructure(t *testing.T) {
+	freshSession()
+	w := serveJSON(t, http.MethodPost, HandleOperation, OperationRequest{Structure: "splaytree", Operation: "insert"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want 400: %s", w.Code, w.Body)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["code"] != string(datastructures.CodeUnknownStructure) {
+		t.Errorf("code %v, want %s", body["code"], datastructures.CodeUnknownStructure)
+	}
+
+	w = serveJSON(t, http.MethodPost, HandleOperation, OperationRequest{
+		Structure: "heap",
+		Operation: "insert",
+		Params:    map[string]interface{}{"value": 7},
+	})
+	var result datastructures.OperationResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !result.Success || result.Kind != datastructures.KindHeap {
+		t.Errorf("heap insert: status %d, success %v, kind %s", w.Code, result.Success, result.Kind)
+	}
+}
+
+func TestResultsAreTaggedWithTheirStructure(t *testing.T) {
+	freshSession()
+	requests := []OperationRequest{
+		{Structure: "rbtree", Operation: "insert", Params: map[string]interface{}{"value": 5}},
+		{Structure: "avltree", Operation: "insert", Params: map[string]interface{}{"value": 5}},
+		{Structure: "tree234", Operation: "insert", Params: map[string]interface{}{"value": 5}},
+		{Structure: "heap", Operation: "state"},
+		{Structure: "array", Operation: "mergesort", Params: map[string]interface{}{"values": []int{3, 1, 2}}},
+		{Structure: "graph", Operation: "state"},
+	}
+	for _, req := range requests {
+		w := serveJSON(t, http.MethodPost, HandleOperation, req)
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK || body["kind"] != req.Structure {
+			t.Errorf("%s %s: status %d, kind %v", req.Structure, req.Operation, w.Code, body["kind"])
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResetClearsEveryStructure(t *testing.T) {
+	state := freshSession()
+	state.rbTree.Insert(1)
+	state.avlTree.Insert(1)
+	state.tree234.Insert(1)
+	state.heap.Insert(1)
+	state.sorter.QuickSort([]int{2, 1})
+
+	if w := serveJSON(t, http.MethodPost, HandleReset, nil); w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	state = sessionFor(defaultSession)
+	if state.rbTree.Size() != 0 || state.avlTree.Size() != 0 || state.tree234.Size() != 0 {
+		t.Error("a tree kept its values")
+	}
+	if len(state.heap.Items) != 0 {
+		t.Errorf("heap kept %v", state.heap.Items)
+	}
+	if values := state.sorter.Values(); len(values) != 0 {
+		t.Errorf("array kept %v", values)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"log"
+
+	"gin/datastructures"
+)
+
+// maxSessions bounds the sessions whose structures are kept in memory. The
+// least recently created one is dropped first; with a persistent store it
+// is reloaded on its next request.
+const maxSessions = 100
+
+// sessionState holds the data structures of one session
+type sessionState struct {
+	rbTree  *datastructures.RedBlackTree
+	avlTree *datastructures.AVLTree
+	tree234 *datastructures.Tree234
+	heap    *datastructures.BinaryHeap
+	sorter  *datastructures.ArraySorter
+	graph   *datastructures.Graph
+}
+
+// newSessionState creates empty structures and the sample graph
+func newSessionState() *sessionState {
+	return &sessionState{
+		rbTree:  datastructures.NewRedBlackTree(),
+		avlTree: datastructures.NewAVLTree(),
+		tree234: datastructures.NewTree234(),
+		heap:    datastructures.NewBinaryHeap(),
+		sorter:  datastructures.NewArraySorter(),
+		graph:   datastructures.CreateSampleGraph(),
+	}
+}
+
+// sessions holds the structures of every session in memory, and
+// sessionOrder their creation order. Guarded by stateMu.
+var (
+	sessions     = make(map[string]*sessionState)
+	sessionOrder []string
+)
+
+// sessionFor returns the structures of session, restoring them from the
+// session store, or creating them, on first use. Callers must hold stateMu.
+func sessionFor(session string) *sessionState {
+	if state, ok := sessions[session]; ok {
+		return state
+	}
+
+	state := newSessionState()
+	snapshot, ok, err := sessionStore.Load(session)
+	if err != nil {
+		log.Printf("failed to load session %s: %v", session, err)
+	} else if ok {
+		if err := restoreState(state, snapshot); err != nil {
+			log.Printf("failed to restore session %s: %v", session, err)
+			state = newSessionState()
+		}
+	}
+
+	sessionOrder = append(sessionOrder, session)
+	if len(sessionOrder) > maxSessions {
+		delete(sessions, sessionOrder[0])
+		sessionOrder = sessionOrder[1:]
+	}
+	sessions[session] = state
+	return state
+}
+
+// resetSession replaces the structures of session with fresh ones. Callers
+// must hold stateMu.
+func resetSession(session string) {
+	*sessionFor(session) = *newSessionState()
+}
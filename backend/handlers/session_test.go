@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"gin/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// insertFor inserts value into the rbtree of session through the API
+func insertFor(t *testing.T, session string, value string) {
+	t.Helper()
+	r := gin.New()
+	r.POST("/", HandleOperation)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"structure":"rbtree","operation":"insert","params":{"value":`+value+`}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", session)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("insert for %s: status %d: %s", session, w.Code, w.Body)
+	}
+}
+
+func TestSessionsPersistSeparately(t *testing.T) {
+	fileStore, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitStore(fileStore); err != nil {
+		t.Fatal(err)
+	}
+	defer InitStore(store.NopStore{})
+
+	insertFor(t, "alice", "1")
+	insertFor(t, "bob", "2")
+	insertFor(t, "alice", "3")
+
+	// Reopening the store stands in for a server restart
+	if err := InitStore(fileStore); err != nil {
+		t.Fatal(err)
+	}
+	if got := sessionFor("alice").rbTree.Values(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("alice reloaded %v, want [1 3]", got)
+	}
+	if got := sessionFor("bob").rbTree.Values(); !slices.Equal(got, []int{2}) {
+		t.Errorf("bob reloaded %v, want [2]", got)
+	}
+	if got := sessionFor(defaultSession).rbTree.Values(); len(got) != 0 {
+		t.Errorf("default session picked up %v", got)
+	}
+}
+
+func TestSessionsWithoutPersistenceAreBounded(t *testing.T) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if _, ok := sessionStore.(store.NopStore); !ok {
+		t.Fatalf("default store %T, want NopStore", sessionStore)
+	}
+
+	sessionFor("evicted").rbTree.Insert(1)
+	persistState("evicted")
+	for i := 0; i < maxSessions; i++ {
+		sessionFor(fmt.Sprintf("filler-%d", i))
+	}
+	if _, ok := sessions["evicted"]; ok {
+		t.Fatal("the oldest session was not evicted")
+	}
+	if got := sessionFor("evicted").rbTree.Values(); len(got) != 0 {
+		t.Errorf("evicted session came back with %v", got)
+	}
+	if len(sessions) > maxSessions {
+		t.Errorf("%d sessions kept, bound is %d", len(sessions), maxSessions)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStepLogs bounds how many sessions keep their last step log
+const maxStepLogs = 50
+
+// stepLog is the cached step log of one operation. token identifies the
+// operation, so a newer operation of the session expires older tokens.
+type stepLog struct {
+	token string
+	steps []datastructures.Step
+}
+
+var (
+	// stepLogs caches the steps of the last operation of every session so
+	// that clients can fetch them in pages; stepLogOrder evicts the oldest
+	// and stepTokenCounter numbers the tokens. All are guarded by stateMu.
+	stepLogs         = make(map[string]stepLog)
+	stepLogOrder     []string
+	stepTokenCounter int
+)
+
+// requestSession returns the session a request belongs to, taken from the
+// X-Session-ID header or the sessionId query parameter
+func requestSession(c *gin.Context) string {
+	if session := c.GetHeader("X-Session-ID"); session != "" {
+		return session
+	}
+	if session := c.Query("sessionId"); session != "" {
+		return session
+	}
+	return defaultSession
+}
+
+// cacheSteps stores steps as the last step log of session and returns the
+// token identifying it. Callers must hold stateMu.
+func cacheSteps(session string, steps []datastructures.Step) string {
+	if _, ok := stepLogs[session]; !ok {
+		stepLogOrder = append(stepLogOrder, session)
+		if len(stepLogOrder) > maxStepLogs {
+			delete(stepLogs, stepLogOrder[0])
+			stepLogOrder = stepLogOrder[1:]
+		}
+	}
+	stepTokenCounter++
+	token := "op-" + strconv.Itoa(stepTokenCounter)
+	stepLogs[session] = stepLog{token: token, steps: steps}
+	return token
+}
+
+// pageSteps returns the steps in [offset, offset+limit) and the offset of
+// the next page, or nil when the page reaches the end of the log. A
+// non-positive limit returns everything from offset on.
+func pageSteps(steps []datastructures.Step, offset, limit int) ([]datastructures.Step, *int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(steps) {
+		offset = len(steps)
+	}
+	end := len(steps)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	if end == len(steps) {
+		return steps[offset:end], nil
+	}
+	return steps[offset:end], &end
+}
+
+// paginateResult replaces result.Steps with the page selected by the
+// "offset" and "limit" params, if either is given
+func paginateResult(result *datastructures.OperationResult, params map[string]interface{}) {
+	if _, ok := params["offset"]; !ok {
+		if _, ok := params["limit"]; !ok {
+			return
+		}
+	}
+	result.TotalSteps = len(result.Steps)
+	result.Steps, result.NextOffset = pageSteps(result.Steps,
+		getIntParam(params, "offset", 0), getIntParam(params, "limit", 0))
+}
+
+// HandleSteps serves a page of the session's last step log, selected by the
+// offset and limit query parameters
+func HandleSteps(c *gin.Context) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Invalid offset: "+c.Query("offset"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Invalid limit: "+c.Query("limit"))
+		return
+	}
+
+	session := requestSession(c)
+	stateMu.Lock()
+	entry, ok := stepLogs[session]
+	stateMu.Unlock()
+	if !ok {
+		respondError(c, http.StatusNotFound, datastructures.CodeNotFound, "No operation recorded for session "+session)
+		return
+	}
+	steps := entry.steps
+
+	page, next := pageSteps(steps, offset, limit)
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"steps":      page,
+		"totalSteps": len(steps),
+		"nextOffset": next,
+	})
+}
+
+// HandleStep serves the single step at position index of the step log
+// identified by token, so clients can jump to any point of an animation.
+// Only the caller's session is searched, so another session's token is
+// unknown. Tokens expire once their session runs another operation or the
+// log is evicted.
+func HandleStep(c *gin.Context) {
+	token := c.Param("token")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, datastructures.CodeInvalidParam, "Invalid step index: "+c.Param("index"))
+		return
+	}
+
+	stateMu.Lock()
+	entry, ok := stepLogs[requestSession(c)]
+	stateMu.Unlock()
+	if !ok || entry.token != token {
+		respondError(c, http.StatusNotFound, datastructures.CodeNotFound, "Unknown or expired step token: "+token)
+		return
+	}
+	steps := entry.steps
+	if index < 0 || index >= len(steps) {
+		respondError(c, http.StatusNotFound, datastructures.CodeNotFound, fmt.Sprintf("Step %d is out of range, the log has %d steps", index, len(steps)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"stepToken":  token,
+		"totalSteps": len(steps),
+		"step":       steps[index],
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gin/datastructures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stepsPage is a page of steps as served by HandleOperation and HandleSteps
+type stepsPage struct {
+	Steps      []datastructures.Step `json:"steps"`
+	TotalSteps int                   `json:"totalSteps"`
+	NextOffset *int                  `json:"nextOffset"`
+}
+
+func TestStepPagesCoverEveryStepOnce(t *testing.T) {
+	state := freshSession()
+	for v := 1; v <= 30; v++ {
+		state.rbTree.Insert(v)
+	}
+
+	w := serveJSON(t, http.MethodPost, HandleOperation, OperationRequest{
+		Structure: "rbtree",
+		Operation: "insert",
+		Params:    map[string]interface{}{"value": 31, "offset": 0, "limit": 10},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var page stepsPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	total := page.TotalSteps
+	if total <= 10 {
+		t.Fatalf("only %d steps, want more than one page", total)
+	}
+
+	seen := make([]int, 0, total)
+	r := gin.New()
+	r.GET("/steps", HandleSteps)
+	for {
+		if len(page.Steps) > 10 {
+			t.Fatalf("page of %d steps exceeds the limit", len(page.Steps))
+		}
+		for _, step := range page.Steps {
+			seen = append(seen, step.Index)
+		}
+		if page.NextOffset == nil {
+			break
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/steps?offset=%d&limit=10", *page.NextOffset), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body)
+		}
+		page = stepsPage{}
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(seen) != total {
+		t.Fatalf("paged through %d steps, want %d", len(seen), total)
+	}
+	for i, index := range seen {
+		if index != i {
+			t.Fatalf("page position %d holds step %d", i, index)
+		}
+	}
+}
+
+func TestStepTokensOnlyResolveInTheirSession(t *testing.T) {
+	stateMu.Lock()
+	token := cacheSteps("alice", []datastructures.Step{{Type: datastructures.StepComplete}})
+	stateMu.Unlock()
+
+	r := gin.New()
+	r.GET("/steps/:token/:index", HandleStep)
+	for session, want := range map[string]int{"alice": http.StatusOK, "bob": http.StatusNotFound, "": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodGet, "/steps/"+token+"/0", nil)
+		if session != "" {
+			req.Header.Set("X-Session-ID", session)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("session %q: status %d, want %d", session, w.Code, want)
+		}
+	}
+}
@@ -1,48 +1,208 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
 	"gin/handlers"
+	"gin/middleware"
+	"gin/store"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	r := gin.Default()
+	port := flag.String("port", envOrDefault("PORT", "8080"), "port to listen on")
+	addr := flag.String("addr", os.Getenv("LISTEN_ADDR"), "full listen address such as 127.0.0.1:8080; overrides -port")
+	shutdownTimeout := flag.Duration("shutdown-timeout", durationEnvOrDefault("SHUTDOWN_TIMEOUT", 5*time.Second), "time allowed for in-flight requests to finish on shutdown")
+	operationTimeout := flag.Duration("operation-timeout", durationEnvOrDefault("OPERATION_TIMEOUT", handlers.OperationTimeout), "maximum duration of a single operation")
+	benchmarkTimeout := flag.Duration("benchmark-timeout", durationEnvOrDefault("BENCHMARK_TIMEOUT", handlers.BenchmarkTimeout), "maximum duration of a benchmark run")
+	maxDataSize := flag.Int("benchmark-max-size", intEnvOrDefault("BENCHMARK_MAX_DATA_SIZE", handlers.MaxBenchmarkDataSize), "maximum dataSize accepted by benchmarks")
+	debugSteps := flag.Bool("debug-steps", boolEnvOrDefault("DEBUG_STEPS", false), "log steps that reference nodes missing from their snapshot")
+	pprofEnabled := flag.Bool("pprof", boolEnvOrDefault("PPROF_ENABLED", false), "serve net/http/pprof profiles under /api/v1/debug/pprof")
+	flag.Parse()
 
-	// CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	handlers.OperationTimeout = *operationTimeout
+	handlers.BenchmarkTimeout = *benchmarkTimeout
+	handlers.MaxBenchmarkDataSize = *maxDataSize
+	handlers.CheckSteps = *debugSteps
+
+	// Persist structures to disk only when a store directory is configured
+	if dir := os.Getenv("STRUCTTRACE_STORE_DIR"); dir != "" {
+		fileStore, err := store.NewFileStore(dir)
+		if err != nil {
+			log.Fatalf("failed to open store: %v", err)
+		}
+		if err := handlers.InitStore(fileStore); err != nil {
+			log.Fatalf("failed to restore session: %v", err)
 		}
+		log.Printf("persisting structures to %s", dir)
+	}
 
-		c.Next()
-	})
+	listenAddr := *addr
+	if listenAddr == "" {
+		listenAddr = ":" + *port
+	}
+
+	// Wait for an interrupt, then give in-flight requests time to finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := runServer(ctx, serverConfig{
+		Addr:            listenAddr,
+		ShutdownTimeout: *shutdownTimeout,
+		CORSOrigins:     envOrDefault("CORS_ORIGINS", middleware.DefaultCORSOrigins),
+		Pprof:           *pprofEnabled,
+	}); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
+
+// serverConfig holds the settings runServer needs
+type serverConfig struct {
+	// Addr is the listen address, such as ":8080"
+	Addr string
+	// ShutdownTimeout is the time in-flight requests get to finish
+	ShutdownTimeout time.Duration
+	// CORSOrigins is the comma-separated list of allowed origins
+	CORSOrigins string
+	// Pprof serves net/http/pprof profiles under /api/v1/debug/pprof
+	Pprof bool
+}
+
+// newRouter builds the API router
+func newRouter(cfg serverConfig) *gin.Engine {
+	r := gin.Default()
+
+	// CORS middleware
+	r.Use(middleware.CORS(middleware.ParseOrigins(cfg.CORSOrigins)))
 
 	// API v1 routes
 	api := r.Group("/api/v1")
 	{
 		// Data structure operations
 		api.POST("/operations", handlers.HandleOperation)
+		api.POST("/operations/batch", handlers.HandleBatch)
+		api.GET("/steps", handlers.HandleSteps)
+		api.GET("/steps/:token/:index", handlers.HandleStep)
+		api.GET("/structures", handlers.HandleStructures)
 		api.POST("/reset", handlers.HandleReset)
+		api.POST("/compare", handlers.HandleCompare)
+		api.GET("/export/ascii", handlers.HandleExportASCII)
 
 		// Benchmark endpoints
 		api.POST("/benchmark/start", handlers.HandleBenchmarkSSE)
 		api.POST("/benchmark/stop", handlers.HandleStopBenchmark)
 		api.GET("/benchmark/status", handlers.HandleBenchmarkStatus)
+		api.GET("/benchmark/summary/:runId", handlers.HandleBenchmarkSummary)
+		api.GET("/benchmark/export/:runId", handlers.HandleBenchmarkExport)
 
 		// Health check
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"status":  "ok",
-				"service": "StructTrace Engine API",
-			})
-		})
+		api.GET("/health", handlers.HandleHealth)
+	}
+
+	// Profiling stays off unless explicitly enabled
+	if cfg.Pprof {
+		handlers.RegisterPprof(api)
+		log.Println("pprof profiles available under /api/v1/debug/pprof")
+	}
+	return r
+}
+
+// runServer serves the API on cfg.Addr until ctx is done, then stops any
+// running benchmarks and gives in-flight requests cfg.ShutdownTimeout to
+// finish. It returns once the server has shut down.
+func runServer(ctx context.Context, cfg serverConfig) error {
+	srv := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: newRouter(cfg),
 	}
+	// Benchmark SSE streams would otherwise hold shutdown until the timeout
+	srv.RegisterOnShutdown(handlers.StopBenchmarks)
 
-	r.Run(":8080")
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	log.Printf("StructTrace Engine listening on %s", srv.Addr)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown: %w", err)
+	}
+	// ListenAndServe returns ErrServerClosed as soon as Shutdown starts
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// envOrDefault returns the value of the environment variable key, or
+// fallback when it is unset or empty
+func envOrDefault(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// durationEnvOrDefault parses the environment variable key as a duration,
+// returning fallback when it is unset or invalid
+func durationEnvOrDefault(key string, fallback time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Printf("invalid %s %q, using %s", key, val, fallback)
+		return fallback
+	}
+	return d
+}
+
+// intEnvOrDefault parses the environment variable key as an integer,
+// returning fallback when it is unset or invalid
+func intEnvOrDefault(key string, fallback int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		log.Printf("invalid %s %q, using %d", key, val, fallback)
+		return fallback
+	}
+	return n
+}
+
+// boolEnvOrDefault parses the environment variable key as a bool,
+// returning fallback when it is unset or invalid
+func boolEnvOrDefault(key string, fallback bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Printf("invalid %s %q, using %t", key, val, fallback)
+		return fallback
+	}
+	return b
 }
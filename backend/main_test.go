@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a loopback address with a currently unused port
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestRunServerShutdownStopsBenchmarks(t *testing.T) {
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runServer(ctx, serverConfig{Addr: addr, ShutdownTimeout: 5 * time.Second})
+	}()
+
+	// Without keep-alives no spare connection is left open: the server
+	// counts a connection that never sent a request as active for 5s
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	base := "http://" + addr + "/api/v1"
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := client.Get(base + "/health")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// A benchmark big enough to still be running when shutdown starts
+	body := `{"dataSize": 1000000, "structures": ["bst", "rbtree", "avltree"], "operation": "insert", "sorted": true}`
+	resp, err := client.Post(base+"/benchmark/start", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, "event: run") {
+		t.Fatalf("first event %q, %v", line, err)
+	}
+
+	start := time.Now()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("runServer: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("runServer did not return after shutdown")
+	}
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Errorf("shutdown took %s, the benchmark was not stopped", elapsed)
+	}
+
+	// The stream ends once the stopped benchmark's handler returns
+	if _, err := io.ReadAll(reader); err != nil {
+		t.Errorf("reading the rest of the stream: %v", err)
+	}
+}
+
+func TestEnvDefaults(t *testing.T) {
+	t.Setenv("TEST_TIMEOUT", "250ms")
+	t.Setenv("TEST_BAD_TIMEOUT", "soon")
+	if got := durationEnvOrDefault("TEST_TIMEOUT", time.Second); got != 250*time.Millisecond {
+		t.Errorf("duration %s, want 250ms", got)
+	}
+	if got := durationEnvOrDefault("TEST_BAD_TIMEOUT", time.Second); got != time.Second {
+		t.Errorf("invalid duration gave %s, want the fallback", got)
+	}
+	if got := durationEnvOrDefault("TEST_UNSET", time.Second); got != time.Second {
+		t.Errorf("unset duration gave %s, want the fallback", got)
+	}
+	if got := envOrDefault("TEST_UNSET", "8080"); got != "8080" {
+		t.Errorf("unset value gave %q, want the fallback", got)
+	}
+}
+
+func TestRunServerReportsListenErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runServer(context.Background(), serverConfig{Addr: l.Addr().String(), ShutdownTimeout: time.Second})
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("listening on a taken address succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runServer did not return")
+	}
+}
+
+func TestRouterAllowsConfiguredOrigins(t *testing.T) {
+	r := newRouter(serverConfig{CORSOrigins: "https://app.example"})
+	for origin, want := range map[string]string{
+		"https://app.example":   "https://app.example",
+		"http://localhost:5173": "",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d", w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("origin %s: Allow-Origin %q, want %q", origin, got, want)
+		}
+	}
+}
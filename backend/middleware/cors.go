@@ -0,0 +1,62 @@
+// Package middleware holds the HTTP middleware shared by every route
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCORSOrigins are the origins allowed when none are configured: the
+// frontend's Vite dev server. Deployments list their own origins, or "*".
+const DefaultCORSOrigins = "http://localhost:5173,http://127.0.0.1:5173"
+
+// ParseOrigins splits a comma-separated origin list, dropping blanks and
+// trailing slashes
+func ParseOrigins(list string) []string {
+	origins := make([]string, 0)
+	for _, origin := range strings.Split(list, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// CORS allows cross-origin requests from the given origins. An
+// entry of "*" allows any origin. A listed origin is echoed back and may
+// send credentials, which browsers never allow together with "*".
+func CORS(allowedOrigins []string) gin.HandlerFunc {
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if allowAll {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			// The response differs per origin, so caches must key on it
+			c.Header("Vary", "Origin")
+			if origin != "" && allowed[origin] {
+				c.Header("Access-Control-Allow-Origin", origin)
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Session-ID")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	}
+}
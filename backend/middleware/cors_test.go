@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func corsRouter(origins string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(ParseOrigins(origins)))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func corsRequest(r *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestParseOrigins(t *testing.T) {
+	got := ParseOrigins(" http://a.example/ ,, http://b.example")
+	if want := []string{"http://a.example", "http://b.example"}; !slices.Equal(got, want) {
+		t.Errorf("ParseOrigins = %v, want %v", got, want)
+	}
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	w := corsRequest(corsRouter(DefaultCORSOrigins), http.MethodGet, "http://localhost:5173")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:5173" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Allow-Credentials = %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	w := corsRequest(corsRouter(DefaultCORSOrigins), http.MethodGet, "http://evil.example")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Allow-Origin = %q for an unlisted origin", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Allow-Credentials = %q for an unlisted origin", got)
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	w := corsRequest(corsRouter("*"), http.MethodGet, "http://any.example")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Allow-Credentials = %q with a wildcard", got)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	w := corsRequest(corsRouter(DefaultCORSOrigins), http.MethodOptions, "http://127.0.0.1:5173")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("preflight status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://127.0.0.1:5173" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got == "" {
+		t.Error("preflight lists no allowed headers")
+	}
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"gin/datastructures"
+)
+
+// Snapshot holds the serialized data structures of a single session
+type Snapshot struct {
+	RBTree  *datastructures.TreeExport    `json:"rbtree,omitempty"`
+	AVLTree *datastructures.TreeExport    `json:"avltree,omitempty"`
+	Tree234 *datastructures.Tree234Export `json:"tree234,omitempty"`
+	Heap    *datastructures.HeapExport    `json:"heap,omitempty"`
+	Array   *datastructures.ArrayExport   `json:"array,omitempty"`
+	Graph   *datastructures.GraphExport   `json:"graph,omitempty"`
+}
+
+// Store persists session snapshots
+type Store interface {
+	// Save stores the snapshot of a session, replacing any previous one
+	Save(session string, snapshot Snapshot) error
+	// Load returns the stored snapshot of a session and whether one existed
+	Load(session string) (Snapshot, bool, error)
+}
+
+// NopStore persists nothing. It is the store of servers running without
+// persistence, whose sessions live in memory only.
+type NopStore struct{}
+
+// Save discards the snapshot
+func (NopStore) Save(session string, snapshot Snapshot) error {
+	return nil
+}
+
+// Load never finds a snapshot
+func (NopStore) Load(session string) (Snapshot, bool, error) {
+	return Snapshot{}, false, nil
+}
+
+// MemoryStore keeps snapshots in process memory only
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots map[string]Snapshot
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		snapshots: make(map[string]Snapshot),
+	}
+}
+
+// Save stores the snapshot in memory
+func (s *MemoryStore) Save(session string, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[session] = snapshot
+	return nil
+}
+
+// Load returns the in-memory snapshot of a session
+func (s *MemoryStore) Load(session string) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.snapshots[session]
+	return snapshot, ok, nil
+}
+
+var sessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FileStore keeps one JSON file per session in a directory
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore creates a file-backed store rooted at dir, creating it if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(session string) (string, error) {
+	if !sessionNamePattern.MatchString(session) {
+		return "", fmt.Errorf("invalid session name %q", session)
+	}
+	return filepath.Join(s.dir, session+".json"), nil
+}
+
+// Save writes the snapshot to disk. The file is replaced atomically so a
+// crash mid-write never leaves a truncated snapshot behind.
+func (s *FileStore) Save(session string, snapshot Snapshot) error {
+	path, err := s.path(session)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, session+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads the snapshot of a session from disk
+func (s *FileStore) Load(session string) (Snapshot, bool, error) {
+	path, err := s.path(session)
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, false, fmt.Errorf("decode snapshot %s: %w", path, err)
+	}
+	return snapshot, true, nil
+}
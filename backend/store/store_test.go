@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gin/datastructures"
+)
+
+func TestFileStoreReloadsIdenticalTrees(t *testing.T) {
+	rb := datastructures.NewRedBlackTree()
+	avl := datastructures.NewAVLTree()
+	for _, v := range []int{41, 38, 31, 12, 19, 8, 38} {
+		rb.Insert(v)
+		avl.Insert(v)
+	}
+	rb.Delete(31)
+	avl.Delete(31)
+	rbExport, avlExport := rb.Export(), avl.Export()
+
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("lesson-1", Snapshot{RBTree: &rbExport, AVLTree: &avlExport}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store on the same directory stands in for a restarted server
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, ok, err := reopened.Load("lesson-1")
+	if err != nil || !ok {
+		t.Fatalf("load: ok=%v err=%v", ok, err)
+	}
+	rbLoaded, err := datastructures.ImportRedBlackTree(*snapshot.RBTree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	avlLoaded, err := datastructures.ImportAVLTree(*snapshot.AVLTree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, pair := range map[string][2]interface{}{
+		"rbtree":  {rb.State().FinalTree, rbLoaded.State().FinalTree},
+		"avltree": {avl.State().FinalTree, avlLoaded.State().FinalTree},
+	} {
+		want, _ := json.Marshal(pair[0])
+		got, _ := json.Marshal(pair[1])
+		if string(want) != string(got) {
+			t.Errorf("%s reloaded as\n%s\nwant\n%s", name, got, want)
+		}
+	}
+
+	if _, ok, err := reopened.Load("other"); ok || err != nil {
+		t.Errorf("unknown session: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestFileStoreRejectsUnsafeSessionNames(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("../escape", Snapshot{}); err == nil {
+		t.Error("saved a session named ../escape")
+	}
+}